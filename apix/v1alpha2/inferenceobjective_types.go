@@ -25,9 +25,11 @@ import (
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:storageversion
-// +kubebuilder:printcolumn:name="Model Name",type=string,JSONPath=`.spec.modelName`
 // +kubebuilder:printcolumn:name="Inference Pool",type=string,JSONPath=`.spec.poolRef.name`
 // +kubebuilder:printcolumn:name="Priority",type=string,JSONPath=`.spec.priority`
+// +kubebuilder:printcolumn:name="Accepted",type=string,JSONPath=`.status.conditions[?(@.type=="Accepted")].status`
+// +kubebuilder:printcolumn:name="Requests",type=integer,JSONPath=`.status.usage.requests`
+// +kubebuilder:printcolumn:name="Rejected",type=integer,JSONPath=`.status.usage.rejectedRequests`
 // +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 // +genclient
 type InferenceObjective struct {
@@ -83,6 +85,108 @@ type InferenceObjectiveSpec struct {
 	//
 	// +kubebuilder:validation:Required
 	PoolRef PoolObjectReference `json:"poolRef"`
+
+	// Match defines the requests the InferenceObjective applies to when they don't name an InferenceObjective
+	// in the x-gateway-inference-objective header. When unset, the InferenceObjective only applies to the
+	// requests that name it in the header.
+	//
+	// +optional
+	Match *ObjectiveMatch `json:"match,omitempty"`
+
+	// SLOs defines the latency objectives of the requests. Implementations may use them to detect saturation,
+	// and to make scheduling decisions.
+	//
+	// +optional
+	SLOs *ObjectiveSLOs `json:"slos,omitempty"`
+
+	// MaxInputTokens is the maximum number of input tokens of a request. Requests with more input tokens are
+	// rejected.
+	//
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MaxInputTokens *int64 `json:"maxInputTokens,omitempty"`
+
+	// MaxOutputTokens is the maximum number of output tokens of a request. The maximum number of output tokens
+	// a request asks for is lowered to this value, and set to it if the request doesn't ask for a maximum.
+	// It only applies to requests of the OpenAI and Anthropic APIs, not to KServe inference requests.
+	//
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MaxOutputTokens *int64 `json:"maxOutputTokens,omitempty"`
+
+	// Budget limits the rate and the concurrency of the requests. Requests beyond the budget are rejected.
+	//
+	// +optional
+	Budget *ObjectiveBudget `json:"budget,omitempty"`
+}
+
+// ObjectiveMatch defines the requests an InferenceObjective applies to. A request matches if it matches all
+// the conditions that are set. When a request matches several InferenceObjectives, the one with the most
+// conditions applies, and among those the oldest one, based on creation timestamp.
+//
+// +kubebuilder:validation:XValidation:rule="has(self.models) || has(self.headers)",message="at least one of models and headers must be set"
+type ObjectiveMatch struct {
+	// Models are the model names the requests may target, as given in the requests.
+	//
+	// +optional
+	// +kubebuilder:validation:MaxItems=16
+	// +kubebuilder:validation:MinItems=1
+	Models []string `json:"models,omitempty"`
+
+	// Headers are the headers the requests must have.
+	//
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=16
+	// +kubebuilder:validation:MinItems=1
+	Headers []HeaderMatch `json:"headers,omitempty"`
+}
+
+// HeaderMatch matches a request header by its exact value.
+type HeaderMatch struct {
+	// Name is the name of the header, matched case-insensitively.
+	//
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=256
+	Name string `json:"name"`
+
+	// Value is the value of the header.
+	//
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MaxLength=4096
+	Value string `json:"value"`
+}
+
+// ObjectiveSLOs defines the latency objectives of requests.
+type ObjectiveSLOs struct {
+	// TimeToFirstToken is the objective of the time to the first token of streamed responses.
+	//
+	// +optional
+	TimeToFirstToken *metav1.Duration `json:"timeToFirstToken,omitempty"`
+
+	// EndToEndLatency is the objective of the time to the complete response.
+	//
+	// +optional
+	EndToEndLatency *metav1.Duration `json:"endToEndLatency,omitempty"`
+}
+
+// ObjectiveBudget limits the rate and the concurrency of requests. Each Endpoint Picker replica enforces the
+// budget on the requests it handles.
+type ObjectiveBudget struct {
+	// RequestsPerSecond is the maximum sustained rate of requests. Bursts of up to this many requests are
+	// allowed.
+	//
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	RequestsPerSecond *int32 `json:"requestsPerSecond,omitempty"`
+
+	// MaxConcurrentRequests is the maximum number of requests being served at the same time.
+	//
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MaxConcurrentRequests *int32 `json:"maxConcurrentRequests,omitempty"`
 }
 
 // PoolObjectReference identifies an API object within the namespace of the
@@ -113,13 +217,35 @@ type InferenceObjectiveStatus struct {
 	// Known condition types are:
 	//
 	// * "Accepted"
+	// * "ResolvedRefs"
 	//
 	// +optional
 	// +listType=map
 	// +listMapKey=type
 	// +kubebuilder:validation:MaxItems=8
-	// +kubebuilder:default={{type: "Ready", status: "Unknown", reason:"Pending", message:"Waiting for controller", lastTransitionTime: "1970-01-01T00:00:00Z"}}
+	// +kubebuilder:default={{type: "Accepted", status: "Unknown", reason:"Pending", message:"Waiting for controller", lastTransitionTime: "1970-01-01T00:00:00Z"}}
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// Usage is the usage of the InferenceObjective observed by the Endpoint Picker of its pool.
+	//
+	// +optional
+	Usage *ObjectiveUsage `json:"usage,omitempty"`
+}
+
+// ObjectiveUsage is the usage of an InferenceObjective observed by an Endpoint Picker. The counters start
+// from zero when the Endpoint Picker that reports them starts.
+type ObjectiveUsage struct {
+	// Requests is the number of requests of the InferenceObjective.
+	Requests int64 `json:"requests"`
+
+	// RejectedRequests is the number of requests of the InferenceObjective that were not sent to a model
+	// server, e.g. because the pool was saturated or the budget of the InferenceObjective was exhausted.
+	RejectedRequests int64 `json:"rejectedRequests"`
+
+	// LastRequestTime is the time of the latest request of the InferenceObjective.
+	//
+	// +optional
+	LastRequestTime *metav1.Time `json:"lastRequestTime,omitempty"`
 }
 
 // InferenceObjectiveConditionType is a type of condition for the InferenceObjective.
@@ -138,6 +264,7 @@ const (
 	// Possible reasons for this condition to be False are:
 	//
 	// * "ModelNameInUse"
+	// * "MatchConflict"
 	//
 	// Possible reasons for this condition to be Unknown are:
 	//
@@ -152,6 +279,31 @@ const (
 	// Details about naming conflict resolution are on the ModelName field itself.
 	ObjectiveReasonNameInUse InferenceObjectiveConditionReason = "ModelNameInUse"
 
+	// ObjectiveReasonMatchConflict is used when the match rules of the InferenceObjective are the same as those
+	// of an older InferenceObjective of the pool, which gets the requests that match them. The InferenceObjective
+	// still applies to the requests that name it.
+	ObjectiveReasonMatchConflict InferenceObjectiveConditionReason = "MatchConflict"
+
 	// ObjectiveReasonPending is the initial state, and indicates that the controller has not yet reconciled the InferenceObjective.
 	ObjectiveReasonPending InferenceObjectiveConditionReason = "Pending"
 )
+
+const (
+	// ObjectiveConditionResolvedRefs indicates whether the InferencePool the objective references was found.
+	//
+	// Possible reasons for this condition to be True are:
+	//
+	// * "ResolvedRefs"
+	//
+	// Possible reasons for this condition to be False are:
+	//
+	// * "PoolNotFound"
+	//
+	ObjectiveConditionResolvedRefs InferenceObjectiveConditionType = "ResolvedRefs"
+
+	// ObjectiveReasonResolvedRefs is used when the InferencePool the objective references was found.
+	ObjectiveReasonResolvedRefs InferenceObjectiveConditionReason = "ResolvedRefs"
+
+	// ObjectiveReasonPoolNotFound is used when the InferencePool the objective references was not found.
+	ObjectiveReasonPoolNotFound InferenceObjectiveConditionReason = "PoolNotFound"
+)
@@ -60,6 +60,21 @@ func (in *Extension) DeepCopy() *Extension {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HeaderMatch) DeepCopyInto(out *HeaderMatch) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HeaderMatch.
+func (in *HeaderMatch) DeepCopy() *HeaderMatch {
+	if in == nil {
+		return nil
+	}
+	out := new(HeaderMatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InferenceObjective) DeepCopyInto(out *InferenceObjective) {
 	*out = *in
@@ -128,6 +143,31 @@ func (in *InferenceObjectiveSpec) DeepCopyInto(out *InferenceObjectiveSpec) {
 		**out = **in
 	}
 	out.PoolRef = in.PoolRef
+	if in.Match != nil {
+		in, out := &in.Match, &out.Match
+		*out = new(ObjectiveMatch)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SLOs != nil {
+		in, out := &in.SLOs, &out.SLOs
+		*out = new(ObjectiveSLOs)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaxInputTokens != nil {
+		in, out := &in.MaxInputTokens, &out.MaxInputTokens
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MaxOutputTokens != nil {
+		in, out := &in.MaxOutputTokens, &out.MaxOutputTokens
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Budget != nil {
+		in, out := &in.Budget, &out.Budget
+		*out = new(ObjectiveBudget)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InferenceObjectiveSpec.
@@ -150,6 +190,11 @@ func (in *InferenceObjectiveStatus) DeepCopyInto(out *InferenceObjectiveStatus)
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Usage != nil {
+		in, out := &in.Usage, &out.Usage
+		*out = new(ObjectiveUsage)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InferenceObjectiveStatus.
@@ -266,6 +311,100 @@ func (in *InferencePoolStatus) DeepCopy() *InferencePoolStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectiveBudget) DeepCopyInto(out *ObjectiveBudget) {
+	*out = *in
+	if in.RequestsPerSecond != nil {
+		in, out := &in.RequestsPerSecond, &out.RequestsPerSecond
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxConcurrentRequests != nil {
+		in, out := &in.MaxConcurrentRequests, &out.MaxConcurrentRequests
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectiveBudget.
+func (in *ObjectiveBudget) DeepCopy() *ObjectiveBudget {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectiveBudget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectiveMatch) DeepCopyInto(out *ObjectiveMatch) {
+	*out = *in
+	if in.Models != nil {
+		in, out := &in.Models, &out.Models
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make([]HeaderMatch, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectiveMatch.
+func (in *ObjectiveMatch) DeepCopy() *ObjectiveMatch {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectiveMatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectiveSLOs) DeepCopyInto(out *ObjectiveSLOs) {
+	*out = *in
+	if in.TimeToFirstToken != nil {
+		in, out := &in.TimeToFirstToken, &out.TimeToFirstToken
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.EndToEndLatency != nil {
+		in, out := &in.EndToEndLatency, &out.EndToEndLatency
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectiveSLOs.
+func (in *ObjectiveSLOs) DeepCopy() *ObjectiveSLOs {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectiveSLOs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectiveUsage) DeepCopyInto(out *ObjectiveUsage) {
+	*out = *in
+	if in.LastRequestTime != nil {
+		in, out := &in.LastRequestTime, &out.LastRequestTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectiveUsage.
+func (in *ObjectiveUsage) DeepCopy() *ObjectiveUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectiveUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ParentGatewayReference) DeepCopyInto(out *ParentGatewayReference) {
 	*out = *in
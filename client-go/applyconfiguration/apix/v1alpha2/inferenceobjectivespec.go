@@ -21,8 +21,13 @@ package v1alpha2
 // InferenceObjectiveSpecApplyConfiguration represents a declarative configuration of the InferenceObjectiveSpec type for use
 // with apply.
 type InferenceObjectiveSpecApplyConfiguration struct {
-	Priority *int                                   `json:"priority,omitempty"`
-	PoolRef  *PoolObjectReferenceApplyConfiguration `json:"poolRef,omitempty"`
+	Priority        *int                                   `json:"priority,omitempty"`
+	PoolRef         *PoolObjectReferenceApplyConfiguration `json:"poolRef,omitempty"`
+	Match           *ObjectiveMatchApplyConfiguration      `json:"match,omitempty"`
+	SLOs            *ObjectiveSLOsApplyConfiguration       `json:"slos,omitempty"`
+	MaxInputTokens  *int64                                 `json:"maxInputTokens,omitempty"`
+	MaxOutputTokens *int64                                 `json:"maxOutputTokens,omitempty"`
+	Budget          *ObjectiveBudgetApplyConfiguration     `json:"budget,omitempty"`
 }
 
 // InferenceObjectiveSpecApplyConfiguration constructs a declarative configuration of the InferenceObjectiveSpec type for use with
@@ -46,3 +51,43 @@ func (b *InferenceObjectiveSpecApplyConfiguration) WithPoolRef(value *PoolObject
 	b.PoolRef = value
 	return b
 }
+
+// WithMatch sets the Match field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Match field is set to the value of the last call.
+func (b *InferenceObjectiveSpecApplyConfiguration) WithMatch(value *ObjectiveMatchApplyConfiguration) *InferenceObjectiveSpecApplyConfiguration {
+	b.Match = value
+	return b
+}
+
+// WithSLOs sets the SLOs field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the SLOs field is set to the value of the last call.
+func (b *InferenceObjectiveSpecApplyConfiguration) WithSLOs(value *ObjectiveSLOsApplyConfiguration) *InferenceObjectiveSpecApplyConfiguration {
+	b.SLOs = value
+	return b
+}
+
+// WithMaxInputTokens sets the MaxInputTokens field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the MaxInputTokens field is set to the value of the last call.
+func (b *InferenceObjectiveSpecApplyConfiguration) WithMaxInputTokens(value int64) *InferenceObjectiveSpecApplyConfiguration {
+	b.MaxInputTokens = &value
+	return b
+}
+
+// WithMaxOutputTokens sets the MaxOutputTokens field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the MaxOutputTokens field is set to the value of the last call.
+func (b *InferenceObjectiveSpecApplyConfiguration) WithMaxOutputTokens(value int64) *InferenceObjectiveSpecApplyConfiguration {
+	b.MaxOutputTokens = &value
+	return b
+}
+
+// WithBudget sets the Budget field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Budget field is set to the value of the last call.
+func (b *InferenceObjectiveSpecApplyConfiguration) WithBudget(value *ObjectiveBudgetApplyConfiguration) *InferenceObjectiveSpecApplyConfiguration {
+	b.Budget = value
+	return b
+}
@@ -25,7 +25,8 @@ import (
 // InferenceObjectiveStatusApplyConfiguration represents a declarative configuration of the InferenceObjectiveStatus type for use
 // with apply.
 type InferenceObjectiveStatusApplyConfiguration struct {
-	Conditions []v1.ConditionApplyConfiguration `json:"conditions,omitempty"`
+	Conditions []v1.ConditionApplyConfiguration  `json:"conditions,omitempty"`
+	Usage      *ObjectiveUsageApplyConfiguration `json:"usage,omitempty"`
 }
 
 // InferenceObjectiveStatusApplyConfiguration constructs a declarative configuration of the InferenceObjectiveStatus type for use with
@@ -46,3 +47,11 @@ func (b *InferenceObjectiveStatusApplyConfiguration) WithConditions(values ...*v
 	}
 	return b
 }
+
+// WithUsage sets the Usage field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Usage field is set to the value of the last call.
+func (b *InferenceObjectiveStatusApplyConfiguration) WithUsage(value *ObjectiveUsageApplyConfiguration) *InferenceObjectiveStatusApplyConfiguration {
+	b.Usage = value
+	return b
+}
@@ -0,0 +1,48 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v1alpha2
+
+// ObjectiveBudgetApplyConfiguration represents a declarative configuration of the ObjectiveBudget type for use
+// with apply.
+type ObjectiveBudgetApplyConfiguration struct {
+	RequestsPerSecond     *int32 `json:"requestsPerSecond,omitempty"`
+	MaxConcurrentRequests *int32 `json:"maxConcurrentRequests,omitempty"`
+}
+
+// ObjectiveBudgetApplyConfiguration constructs a declarative configuration of the ObjectiveBudget type for use with
+// apply.
+func ObjectiveBudget() *ObjectiveBudgetApplyConfiguration {
+	return &ObjectiveBudgetApplyConfiguration{}
+}
+
+// WithRequestsPerSecond sets the RequestsPerSecond field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the RequestsPerSecond field is set to the value of the last call.
+func (b *ObjectiveBudgetApplyConfiguration) WithRequestsPerSecond(value int32) *ObjectiveBudgetApplyConfiguration {
+	b.RequestsPerSecond = &value
+	return b
+}
+
+// WithMaxConcurrentRequests sets the MaxConcurrentRequests field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the MaxConcurrentRequests field is set to the value of the last call.
+func (b *ObjectiveBudgetApplyConfiguration) WithMaxConcurrentRequests(value int32) *ObjectiveBudgetApplyConfiguration {
+	b.MaxConcurrentRequests = &value
+	return b
+}
@@ -0,0 +1,55 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v1alpha2
+
+// ObjectiveMatchApplyConfiguration represents a declarative configuration of the ObjectiveMatch type for use
+// with apply.
+type ObjectiveMatchApplyConfiguration struct {
+	Models  []string                        `json:"models,omitempty"`
+	Headers []HeaderMatchApplyConfiguration `json:"headers,omitempty"`
+}
+
+// ObjectiveMatchApplyConfiguration constructs a declarative configuration of the ObjectiveMatch type for use with
+// apply.
+func ObjectiveMatch() *ObjectiveMatchApplyConfiguration {
+	return &ObjectiveMatchApplyConfiguration{}
+}
+
+// WithModels adds the given value to the Models field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Models field.
+func (b *ObjectiveMatchApplyConfiguration) WithModels(values ...string) *ObjectiveMatchApplyConfiguration {
+	for i := range values {
+		b.Models = append(b.Models, values[i])
+	}
+	return b
+}
+
+// WithHeaders adds the given value to the Headers field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Headers field.
+func (b *ObjectiveMatchApplyConfiguration) WithHeaders(values ...*HeaderMatchApplyConfiguration) *ObjectiveMatchApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithHeaders")
+		}
+		b.Headers = append(b.Headers, *values[i])
+	}
+	return b
+}
@@ -0,0 +1,52 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v1alpha2
+
+import (
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ObjectiveSLOsApplyConfiguration represents a declarative configuration of the ObjectiveSLOs type for use
+// with apply.
+type ObjectiveSLOsApplyConfiguration struct {
+	TimeToFirstToken *v1.Duration `json:"timeToFirstToken,omitempty"`
+	EndToEndLatency  *v1.Duration `json:"endToEndLatency,omitempty"`
+}
+
+// ObjectiveSLOsApplyConfiguration constructs a declarative configuration of the ObjectiveSLOs type for use with
+// apply.
+func ObjectiveSLOs() *ObjectiveSLOsApplyConfiguration {
+	return &ObjectiveSLOsApplyConfiguration{}
+}
+
+// WithTimeToFirstToken sets the TimeToFirstToken field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the TimeToFirstToken field is set to the value of the last call.
+func (b *ObjectiveSLOsApplyConfiguration) WithTimeToFirstToken(value v1.Duration) *ObjectiveSLOsApplyConfiguration {
+	b.TimeToFirstToken = &value
+	return b
+}
+
+// WithEndToEndLatency sets the EndToEndLatency field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the EndToEndLatency field is set to the value of the last call.
+func (b *ObjectiveSLOsApplyConfiguration) WithEndToEndLatency(value v1.Duration) *ObjectiveSLOsApplyConfiguration {
+	b.EndToEndLatency = &value
+	return b
+}
@@ -0,0 +1,61 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v1alpha2
+
+import (
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ObjectiveUsageApplyConfiguration represents a declarative configuration of the ObjectiveUsage type for use
+// with apply.
+type ObjectiveUsageApplyConfiguration struct {
+	Requests         *int64   `json:"requests,omitempty"`
+	RejectedRequests *int64   `json:"rejectedRequests,omitempty"`
+	LastRequestTime  *v1.Time `json:"lastRequestTime,omitempty"`
+}
+
+// ObjectiveUsageApplyConfiguration constructs a declarative configuration of the ObjectiveUsage type for use with
+// apply.
+func ObjectiveUsage() *ObjectiveUsageApplyConfiguration {
+	return &ObjectiveUsageApplyConfiguration{}
+}
+
+// WithRequests sets the Requests field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Requests field is set to the value of the last call.
+func (b *ObjectiveUsageApplyConfiguration) WithRequests(value int64) *ObjectiveUsageApplyConfiguration {
+	b.Requests = &value
+	return b
+}
+
+// WithRejectedRequests sets the RejectedRequests field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the RejectedRequests field is set to the value of the last call.
+func (b *ObjectiveUsageApplyConfiguration) WithRejectedRequests(value int64) *ObjectiveUsageApplyConfiguration {
+	b.RejectedRequests = &value
+	return b
+}
+
+// WithLastRequestTime sets the LastRequestTime field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the LastRequestTime field is set to the value of the last call.
+func (b *ObjectiveUsageApplyConfiguration) WithLastRequestTime(value v1.Time) *ObjectiveUsageApplyConfiguration {
+	b.LastRequestTime = &value
+	return b
+}
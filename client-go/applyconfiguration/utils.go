@@ -54,6 +54,8 @@ func ForKind(kind schema.GroupVersionKind) interface{} {
 		// Group=inference.networking.x-k8s.io, Version=v1alpha2
 	case v1alpha2.SchemeGroupVersion.WithKind("Extension"):
 		return &apixv1alpha2.ExtensionApplyConfiguration{}
+	case v1alpha2.SchemeGroupVersion.WithKind("HeaderMatch"):
+		return &apixv1alpha2.HeaderMatchApplyConfiguration{}
 	case v1alpha2.SchemeGroupVersion.WithKind("InferenceObjective"):
 		return &apixv1alpha2.InferenceObjectiveApplyConfiguration{}
 	case v1alpha2.SchemeGroupVersion.WithKind("InferenceObjectiveSpec"):
@@ -66,6 +68,14 @@ func ForKind(kind schema.GroupVersionKind) interface{} {
 		return &apixv1alpha2.InferencePoolSpecApplyConfiguration{}
 	case v1alpha2.SchemeGroupVersion.WithKind("InferencePoolStatus"):
 		return &apixv1alpha2.InferencePoolStatusApplyConfiguration{}
+	case v1alpha2.SchemeGroupVersion.WithKind("ObjectiveBudget"):
+		return &apixv1alpha2.ObjectiveBudgetApplyConfiguration{}
+	case v1alpha2.SchemeGroupVersion.WithKind("ObjectiveMatch"):
+		return &apixv1alpha2.ObjectiveMatchApplyConfiguration{}
+	case v1alpha2.SchemeGroupVersion.WithKind("ObjectiveSLOs"):
+		return &apixv1alpha2.ObjectiveSLOsApplyConfiguration{}
+	case v1alpha2.SchemeGroupVersion.WithKind("ObjectiveUsage"):
+		return &apixv1alpha2.ObjectiveUsageApplyConfiguration{}
 	case v1alpha2.SchemeGroupVersion.WithKind("ParentGatewayReference"):
 		return &apixv1alpha2.ParentGatewayReferenceApplyConfiguration{}
 	case v1alpha2.SchemeGroupVersion.WithKind("PoolObjectReference"):
@@ -47,14 +47,17 @@ import (
 	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/config/loader"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datalayer"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datalayer/health"
 	dlmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datalayer/metrics"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datastore"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/lora"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/metrics"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/metrics/collectors"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/plugins"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/requestcontrol"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/saturationdetector"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework/plugins/filter"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework/plugins/multi/prefix"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework/plugins/picker"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework/plugins/profile"
@@ -95,6 +98,8 @@ var (
 	refreshMetricsInterval           = flag.Duration("refresh-metrics-interval", runserver.DefaultRefreshMetricsInterval, "interval to refresh metrics")
 	refreshPrometheusMetricsInterval = flag.Duration("refresh-prometheus-metrics-interval", runserver.DefaultRefreshPrometheusMetricsInterval, "interval to flush prometheus metrics")
 	metricsStalenessThreshold        = flag.Duration("metrics-staleness-threshold", runserver.DefaultMetricsStalenessThreshold, "Duration after which metrics are considered stale. This is used to determine if a pod's metrics are fresh enough.")
+	statusRefreshInterval            = flag.Duration("status-refresh-interval", runserver.DefaultStatusRefreshInterval, "Interval to refresh the status of the InferencePool and its InferenceObjectives at. "+
+		"Only the leader writes status when leader election is enabled. 0, the default, disables writing status.")
 	// configuration flags
 	configFile = flag.String("config-file", runserver.DefaultConfigFile, "The path to the configuration file")
 	configText = flag.String("config-text", runserver.DefaultConfigText, "The configuration specified as text, in lieu of a file")
@@ -105,6 +110,16 @@ var (
 	modelServerMetricsScheme                  = flag.String("model-server-metrics-scheme", "http", "Scheme to scrape metrics from pods")
 	modelServerMetricsHttpsInsecureSkipVerify = flag.Bool("model-server-metrics-https-insecure-skip-verify", true, "When using 'https' scheme for 'model-server-metrics-scheme', configure 'InsecureSkipVerify' (default to true)")
 	haEnableLeaderElection                    = flag.Bool("ha-enable-leader-election", false, "Enables leader election for high availability. When enabled, readiness probes will only pass on the leader.")
+	injectStreamUsage                         = flag.Bool("inject-stream-usage", false, "Sets stream_options.include_usage on streaming completions and chat-completions requests, so that token usage "+
+		"metrics are recorded for all streamed responses. The usage chunk is removed from the responses of clients that didn't request it.")
+	modelServerHealthProbing = flag.Bool("model-server-health-probing", false, "Enables active probing of the health and model-list endpoints of the model servers, "+
+		"excluding the model servers that fail the probes from scheduling. Requires the experimental pluggable data layer.")
+	modelServerType          = flag.String("model-server-type", "vllm", "The type of the model servers, setting the default probed paths: vllm, sglang or triton-tensorrt-llm")
+	modelServerHealthPath    = flag.String("model-server-health-path", "", "Path to probe the health of pods. Defaults to the health path of the model-server type if not set.")
+	modelServerModelsPath    = flag.String("model-server-models-path", "", "Path to probe the model list of pods. Defaults to the model-list path of the model-server type if not set.")
+	modelServerProbeInterval = flag.Duration("model-server-probe-interval", health.DefaultProbeInterval, "Interval to probe the health of pods")
+	modelDiscovery           = flag.Bool("model-discovery", false, "Enables the discovery of the base models and adapters served by each model server from its model-list endpoint, "+
+		"as part of the model server health probes. Requests for a model that no model server serves are rejected with 404. Requires model-server-health-probing.")
 
 	setupLog = ctrl.Log.WithName("setup")
 )
@@ -120,6 +135,14 @@ func NewRunner() *Runner {
 type Runner struct {
 	requestControlConfig *requestcontrol.Config
 	schedulerConfig      *scheduling.SchedulerConfig
+	// saturationDetector is the saturation detector configured in the EndpointPickerConfig, if any.
+	saturationDetector saturationdetector.SaturationDetector
+	// pluginRunnables are the configured plugins that run background work managed by the manager.
+	pluginRunnables []manager.Runnable
+	// pluginDebugHandlers are the configured plugins that expose their internal state for debugging.
+	pluginDebugHandlers []plugins.DebugHandler
+	// configLoadResult describes the result of loading the configuration, reported in the InferencePool status.
+	configLoadResult string
 }
 
 func (r *Runner) WithRequestControlConfig(requestControlConfig *requestcontrol.Config) *Runner {
@@ -142,8 +165,10 @@ func (r *Runner) Run(ctx context.Context) error {
 
 	setupLog.Info("GIE build", "commit-sha", version.CommitSHA, "build-ref", version.BuildRef)
 
+	useDatalayerV2 := env.GetEnvBool(enableExperimentalDatalayerV2, false, setupLog)
+
 	// Validate flags
-	if err := validateFlags(); err != nil {
+	if err := validateFlags(useDatalayerV2); err != nil {
 		setupLog.Error(err, "Failed to validate flags")
 		return err
 	}
@@ -166,7 +191,6 @@ func (r *Runner) Run(ctx context.Context) error {
 	}
 
 	// --- Setup Datastore ---
-	useDatalayerV2 := env.GetEnvBool(enableExperimentalDatalayerV2, false, setupLog)
 	epf, err := r.setupMetricsCollection(setupLog, useDatalayerV2)
 	if err != nil {
 		return err
@@ -230,12 +254,27 @@ func (r *Runner) Run(ctx context.Context) error {
 		}
 	}
 
-	err = r.parsePluginsConfiguration(ctx)
+	err = r.parsePluginsConfiguration(ctx, datastore)
 	if err != nil {
 		setupLog.Error(err, "Failed to parse plugins configuration")
 		return err
 	}
 
+	// Plugins with background work run on all replicas, and are stopped gracefully on shutdown.
+	for _, pluginRunnable := range r.pluginRunnables {
+		if err := mgr.Add(runnable.NoLeaderElection(pluginRunnable)); err != nil {
+			setupLog.Error(err, "Failed to register plugin runnable")
+			return err
+		}
+	}
+
+	for _, pluginDebugHandler := range r.pluginDebugHandlers {
+		if err := mgr.AddMetricsServerExtraHandler("/debug/plugins/"+pluginDebugHandler.TypedName().Name, pluginDebugHandler); err != nil {
+			setupLog.Error(err, "Failed to setup plugin debug handler", "plugin", pluginDebugHandler.TypedName())
+			return err
+		}
+	}
+
 	// --- Initialize Core EPP Components ---
 	if r.schedulerConfig == nil {
 		err := errors.New("scheduler config must be set either by config api or through code")
@@ -247,8 +286,13 @@ func (r *Runner) Run(ctx context.Context) error {
 
 	scheduler := scheduling.NewSchedulerWithConfig(r.schedulerConfig)
 
-	saturationDetector := saturationdetector.NewDetector(sdConfig, datastore, setupLog)
+	// The saturation detector configured in the EndpointPickerConfig takes precedence over the environment variables.
+	var saturationDetector saturationdetector.SaturationDetector = r.saturationDetector
+	if saturationDetector == nil {
+		saturationDetector = saturationdetector.NewDetector(sdConfig, datastore, setupLog)
+	}
 
+	r.requestControlConfig.WithStreamUsageInjection(*injectStreamUsage)
 	director := requestcontrol.NewDirectorWithConfig(datastore, scheduler, saturationDetector, r.requestControlConfig)
 
 	// --- Setup ExtProc Server Runner ---
@@ -262,6 +306,8 @@ func (r *Runner) Run(ctx context.Context) error {
 		CertPath:                         *certPath,
 		RefreshPrometheusMetricsInterval: *refreshPrometheusMetricsInterval,
 		MetricsStalenessThreshold:        *metricsStalenessThreshold,
+		StatusRefreshInterval:            *statusRefreshInterval,
+		ConfigLoadResult:                 r.configLoadResult,
 		Director:                         director,
 		SaturationDetector:               saturationDetector,
 		UseExperimentalDatalayerV2:       useDatalayerV2, // pluggable data layer feature flag
@@ -294,20 +340,31 @@ func (r *Runner) Run(ctx context.Context) error {
 }
 
 // registerInTreePlugins registers the factory functions of all known plugins
-func (r *Runner) registerInTreePlugins() {
+func (r *Runner) registerInTreePlugins(ds datastore.Datastore) {
 	plugins.Register(prefix.PrefixCachePluginType, prefix.PrefixCachePluginFactory)
+	plugins.Register(filter.OutlierEjectionFilterType, filter.OutlierEjectionFilterFactory)
+	plugins.Register(filter.ModelAwareFilterType, filter.ModelAwareFilterFactory)
+	plugins.Register(lora.AdapterLoaderType, lora.AdapterLoaderFactory)
+	plugins.Register(saturationdetector.ThresholdSaturationDetectorType, saturationdetector.ThresholdSaturationDetectorFactory(ds))
+	plugins.Register(saturationdetector.HysteresisSaturationDetectorType, saturationdetector.HysteresisSaturationDetectorFactory(ds))
+	plugins.Register(saturationdetector.WeightedSaturationDetectorType, saturationdetector.WeightedSaturationDetectorFactory(ds))
+	plugins.Register(saturationdetector.LatencySaturationDetectorType, saturationdetector.LatencySaturationDetectorFactory(ds))
+	plugins.Register(saturationdetector.TieredSaturationDetectorType, saturationdetector.TieredSaturationDetectorFactory(ds))
 	plugins.Register(picker.MaxScorePickerType, picker.MaxScorePickerFactory)
 	plugins.Register(picker.RandomPickerType, picker.RandomPickerFactory)
 	plugins.Register(profile.SingleProfileHandlerType, profile.SingleProfileHandlerFactory)
+	plugins.Register(profile.PoolingProfileHandlerType, profile.PoolingProfileHandlerFactory)
 	plugins.Register(scorer.KvCacheUtilizationScorerType, scorer.KvCacheUtilizationScorerFactory)
 	plugins.Register(scorer.QueueScorerType, scorer.QueueScorerFactory)
 	plugins.Register(scorer.LoraAffinityScorerType, scorer.LoraAffinityScorerFactory)
+	plugins.Register(scorer.SessionAffinityScorerType, scorer.SessionAffinityScorerFactory)
 	// register filter for test purpose only (used in conformance tests)
 	plugins.Register(testfilter.HeaderBasedTestingFilterType, testfilter.HeaderBasedTestingFilterFactory)
 }
 
-func (r *Runner) parsePluginsConfiguration(ctx context.Context) error {
+func (r *Runner) parsePluginsConfiguration(ctx context.Context, ds datastore.Datastore) error {
 	if *configText == "" && *configFile == "" {
+		r.configLoadResult = "configured through code"
 		return nil // configuring through code, not through file
 	}
 
@@ -324,7 +381,7 @@ func (r *Runner) parsePluginsConfiguration(ctx context.Context) error {
 		}
 	}
 
-	r.registerInTreePlugins()
+	r.registerInTreePlugins(ds)
 	handle := plugins.NewEppHandle(ctx)
 	config, err := loader.LoadConfig(configBytes, handle, logger)
 	if err != nil {
@@ -332,10 +389,25 @@ func (r *Runner) parsePluginsConfiguration(ctx context.Context) error {
 	}
 
 	r.schedulerConfig = config.SchedulerConfig
+	r.saturationDetector = config.SaturationDetector
 
 	// Add requestControl plugins
 	r.requestControlConfig.AddPlugins(handle.GetAllPlugins()...)
 
+	for _, plugin := range handle.GetAllPlugins() {
+		if pluginRunnable, ok := plugin.(manager.Runnable); ok {
+			r.pluginRunnables = append(r.pluginRunnables, pluginRunnable)
+		}
+		if pluginDebugHandler, ok := plugin.(plugins.DebugHandler); ok {
+			r.pluginDebugHandlers = append(r.pluginDebugHandlers, pluginDebugHandler)
+		}
+	}
+
+	if *configText != "" {
+		r.configLoadResult = fmt.Sprintf("loaded from text with %d plugins", len(handle.GetAllPlugins()))
+	} else {
+		r.configLoadResult = fmt.Sprintf("loaded from the file %s with %d plugins", *configFile, len(handle.GetAllPlugins()))
+	}
 	logger.Info("loaded configuration from file/text successfully")
 	return nil
 }
@@ -411,6 +483,26 @@ func setupDatalayer() (datalayer.EndpointFactory, error) {
 		return nil, err
 	}
 
+	if *modelServerHealthProbing {
+		probePaths := health.ModelServerProbePaths[*modelServerType]
+		if *modelServerHealthPath != "" {
+			probePaths.HealthPath = *modelServerHealthPath
+		}
+		if *modelServerModelsPath != "" {
+			probePaths.ModelsPath = *modelServerModelsPath
+		}
+		healthSource := health.NewDataSource(health.Config{
+			ProbePaths:         probePaths,
+			Scheme:             *modelServerMetricsScheme,
+			InsecureSkipVerify: *modelServerMetricsHttpsInsecureSkipVerify,
+			Interval:           *modelServerProbeInterval,
+			DiscoverModels:     *modelDiscovery,
+		})
+		if err := datalayer.RegisterSource(healthSource); err != nil {
+			return nil, err
+		}
+	}
+
 	factory := datalayer.NewEndpointFactory(datalayer.GetSources(), *refreshMetricsInterval)
 	return factory, nil
 }
@@ -460,7 +552,7 @@ func registerHealthServer(mgr manager.Manager, logger logr.Logger, ds datastore.
 	return nil
 }
 
-func validateFlags() error {
+func validateFlags(useDatalayerV2 bool) error {
 	if *poolName == "" {
 		return fmt.Errorf("required %q flag not set", "poolName")
 	}
@@ -470,6 +562,15 @@ func validateFlags() error {
 	if *modelServerMetricsScheme != "http" && *modelServerMetricsScheme != "https" {
 		return fmt.Errorf("unexpected %q value for %q flag, it can only be set to 'http' or 'https'", *modelServerMetricsScheme, "model-server-metrics-scheme")
 	}
+	if _, found := health.ModelServerProbePaths[*modelServerType]; !found {
+		return fmt.Errorf("unexpected %q value for %q flag, it can only be set to 'vllm', 'sglang' or 'triton-tensorrt-llm'", *modelServerType, "model-server-type")
+	}
+	if *modelServerHealthProbing && !useDatalayerV2 {
+		return fmt.Errorf("the %q flag requires the %s environment variable to be set", "model-server-health-probing", enableExperimentalDatalayerV2)
+	}
+	if *modelDiscovery && !*modelServerHealthProbing {
+		return fmt.Errorf("the %q flag requires the %q flag to be set", "model-discovery", "model-server-health-probing")
+	}
 
 	return nil
 }
@@ -29,6 +29,7 @@ import (
 	configapi "sigs.k8s.io/gateway-api-inference-extension/apix/config/v1alpha1"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/config"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/plugins"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/saturationdetector"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework"
 )
@@ -70,6 +71,11 @@ func LoadConfig(configBytes []byte, handle plugins.Handle, logger logr.Logger) (
 		return nil, err
 	}
 
+	config.SaturationDetector, err = loadSaturationDetector(handle)
+	if err != nil {
+		return nil, err
+	}
+
 	return config, nil
 }
 
@@ -116,6 +122,20 @@ func loadSchedulerConfig(configProfiles []configapi.SchedulingProfile, handle pl
 	return scheduling.NewSchedulerConfig(profileHandler, profiles), nil
 }
 
+func loadSaturationDetector(handle plugins.Handle) (saturationdetector.SaturationDetector, error) {
+	var saturationDetector saturationdetector.SaturationDetector
+	for pluginName, plugin := range handle.GetAllPluginsWithNames() {
+		if theSaturationDetector, ok := plugin.(saturationdetector.SaturationDetector); ok {
+			if saturationDetector != nil {
+				return nil, fmt.Errorf("only one saturation detector is allowed. Both %s and %s are saturation detectors",
+					saturationDetector.TypedName().Name, pluginName)
+			}
+			saturationDetector = theSaturationDetector
+		}
+	}
+	return saturationDetector, nil
+}
+
 func instantiatePlugins(configuredPlugins []configapi.PluginSpec, handle plugins.Handle) error {
 	pluginNames := sets.New[string]() // set of plugin names, a name must be unique
 
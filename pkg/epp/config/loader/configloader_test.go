@@ -29,10 +29,12 @@ import (
 	configapi "sigs.k8s.io/gateway-api-inference-extension/apix/config/v1alpha1"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/config"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/plugins"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/saturationdetector"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework/plugins/multi/prefix"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework/plugins/picker"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework/plugins/profile"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework/plugins/scorer"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/logging"
 	"sigs.k8s.io/gateway-api-inference-extension/test/utils"
@@ -236,6 +238,57 @@ func TestLoadRawConfigurationWithDefaults(t *testing.T) {
 		},
 	}
 
+	goodConfigPoolingProfileHandler := &configapi.EndpointPickerConfig{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "EndpointPickerConfig",
+			APIVersion: "inference.networking.x-k8s.io/v1alpha1",
+		},
+		Plugins: []configapi.PluginSpec{
+			{
+				Name:       "test1",
+				Type:       test1Type,
+				Parameters: json.RawMessage("{\"threshold\":10}"),
+			},
+			{
+				Name: profile.PoolingProfileHandlerType,
+				Type: profile.PoolingProfileHandlerType,
+			},
+			{
+				Name: scorer.QueueScorerType,
+				Type: scorer.QueueScorerType,
+			},
+			{
+				Name: picker.MaxScorePickerType,
+				Type: picker.MaxScorePickerType,
+			},
+		},
+		SchedulingProfiles: []configapi.SchedulingProfile{
+			{
+				Name: "default",
+				Plugins: []configapi.SchedulingPlugin{
+					{
+						PluginRef: "test1",
+					},
+					{
+						PluginRef: "max-score-picker",
+					},
+				},
+			},
+			{
+				Name: profile.DefaultPoolingProfile,
+				Plugins: []configapi.SchedulingPlugin{
+					{
+						PluginRef: scorer.QueueScorerType,
+						Weight:    ptr.To(DefaultScorerWeight),
+					},
+					{
+						PluginRef: "max-score-picker",
+					},
+				},
+			},
+		},
+	}
+
 	tests := []testStruct{
 		{
 			name:       "success",
@@ -251,6 +304,13 @@ func TestLoadRawConfigurationWithDefaults(t *testing.T) {
 			want:       goodConfigNoProfiles,
 			wantErr:    false,
 		},
+		{
+			name:       "successPoolingProfileHandler",
+			configText: successPoolingProfileHandlerText,
+			configFile: "",
+			want:       goodConfigPoolingProfileHandler,
+			wantErr:    false,
+		},
 		{
 			name:       "errorBadPluginReferenceText",
 			configText: errorBadPluginReferenceText,
@@ -420,6 +480,16 @@ func TestLoadConfig(t *testing.T) {
 			configText: errorNoProfileHandlersText,
 			wantErr:    true,
 		},
+		{
+			name:       "successWithSaturationDetector",
+			configText: successWithSaturationDetectorText,
+			wantErr:    false,
+		},
+		{
+			name:       "errorTwoSaturationDetectors",
+			configText: errorTwoSaturationDetectorsText,
+			wantErr:    true,
+		},
 	}
 
 	registerNeededPlgugins()
@@ -444,6 +514,30 @@ func registerNeededPlgugins() {
 	plugins.Register(picker.MaxScorePickerType, picker.MaxScorePickerFactory)
 	plugins.Register(picker.RandomPickerType, picker.RandomPickerFactory)
 	plugins.Register(profile.SingleProfileHandlerType, profile.SingleProfileHandlerFactory)
+	plugins.Register(saturationdetector.ThresholdSaturationDetectorType, saturationdetector.ThresholdSaturationDetectorFactory(nil))
+	plugins.Register(saturationdetector.HysteresisSaturationDetectorType, saturationdetector.HysteresisSaturationDetectorFactory(nil))
+}
+
+func TestLoadConfigSaturationDetector(t *testing.T) {
+	registerNeededPlgugins()
+
+	handle := utils.NewTestHandle(context.Background())
+	cfg, err := LoadConfig([]byte(successWithSaturationDetectorText), handle, logging.NewTestLogger())
+	if err != nil {
+		t.Fatalf("LoadConfig returned an unexpected error. error %v", err)
+	}
+	if cfg.SaturationDetector == nil || cfg.SaturationDetector.TypedName().Name != "saturationDetector" {
+		t.Errorf("LoadConfig did not load the configured saturation detector, got %v", cfg.SaturationDetector)
+	}
+
+	handle = utils.NewTestHandle(context.Background())
+	cfg, err = LoadConfig([]byte(successWithNoProfileHandlersText), handle, logging.NewTestLogger())
+	if err != nil {
+		t.Fatalf("LoadConfig returned an unexpected error. error %v", err)
+	}
+	if cfg.SaturationDetector != nil {
+		t.Errorf("LoadConfig loaded a saturation detector that was not configured, got %v", cfg.SaturationDetector)
+	}
 }
 
 // The following multi-line string constants, cause false positive lint errors (dupword)
@@ -488,6 +582,20 @@ plugins:
     threshold: 10
 `
 
+// success with the pooling profile handler and missing scheduling profiles
+//
+//nolint:dupword
+const successPoolingProfileHandlerText = `
+apiVersion: inference.networking.x-k8s.io/v1alpha1
+kind: EndpointPickerConfig
+plugins:
+- name: test1
+  type: test-one
+  parameters:
+    threshold: 10
+- type: pooling-profile-handler
+`
+
 // YAML does not follow expected structure of config
 //
 //nolint:dupword
@@ -754,6 +862,8 @@ func registerTestPlugins() {
 			return newTestProfileHandler(), nil
 		},
 	)
+
+	plugins.Register(profile.PoolingProfileHandlerType, profile.PoolingProfileHandlerFactory)
 }
 
 // valid configuration
@@ -872,6 +982,44 @@ schedulingProfiles:
   - pluginRef: maxScore
 `
 
+// valid configuration with a saturation detector
+//
+//nolint:dupword
+const successWithSaturationDetectorText = `
+apiVersion: inference.networking.x-k8s.io/v1alpha1
+kind: EndpointPickerConfig
+plugins:
+- name: maxScore
+  type: max-score-picker
+- name: saturationDetector
+  type: hysteresis-saturation-detector
+  parameters:
+    minHoldTime: 10s
+schedulingProfiles:
+- name: default
+  plugins:
+  - pluginRef: maxScore
+`
+
+// multiple saturation detectors when only one is allowed
+//
+//nolint:dupword
+const errorTwoSaturationDetectorsText = `
+apiVersion: inference.networking.x-k8s.io/v1alpha1
+kind: EndpointPickerConfig
+plugins:
+- name: maxScore
+  type: max-score-picker
+- name: thresholdDetector
+  type: threshold-saturation-detector
+- name: hysteresisDetector
+  type: hysteresis-saturation-detector
+schedulingProfiles:
+- name: default
+  plugins:
+  - pluginRef: maxScore
+`
+
 // missing required profile handler
 //
 //nolint:dupword
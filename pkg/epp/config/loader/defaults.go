@@ -22,6 +22,7 @@ import (
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework/plugins/picker"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework/plugins/profile"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework/plugins/scorer"
 )
 
 const (
@@ -55,8 +56,10 @@ func setDefaultsPhaseOne(cfg *configapi.EndpointPickerConfig) {
 //  1. Adds a default SchedulingProfile if one wasn't specified.
 //  2. Adds an instance of the SingleProfileHandler, if no profile handler was
 //     specified and the configuration has only one SchedulingProfile
-//  3. Sets a default weight for all scorers without a weight
-//  4. Adds a picker (MaxScorePicker) to all SchedulingProfiles that don't have a picker
+//  3. Adds the pooling SchedulingProfile, if the PoolingProfileHandler was
+//     specified and its profile wasn't
+//  4. Sets a default weight for all scorers without a weight
+//  5. Adds a picker (MaxScorePicker) to all SchedulingProfiles that don't have a picker
 func setDefaultsPhaseTwo(cfg *configapi.EndpointPickerConfig, handle plugins.Handle) {
 	allPlugins := handle.GetAllPluginsWithNames()
 
@@ -99,6 +102,8 @@ func setDefaultsPhaseTwo(cfg *configapi.EndpointPickerConfig, handle plugins.Han
 		}
 	}
 
+	addPoolingProfile(cfg, handle)
+
 	var maxScorePicker string
 	for pluginName, plugin := range allPlugins {
 		if _, ok := plugin.(framework.Picker); ok {
@@ -131,3 +136,37 @@ func setDefaultsPhaseTwo(cfg *configapi.EndpointPickerConfig, handle plugins.Han
 		}
 	}
 }
+
+// addPoolingProfile adds the profile of the PoolingProfileHandler, if the handler was specified and its
+// profile wasn't. The profile is tuned for throughput: pooling requests have no decode phase and are
+// spread by the waiting queue size of the pods alone, using an existing QueueScorer if there is one.
+func addPoolingProfile(cfg *configapi.EndpointPickerConfig, handle plugins.Handle) {
+	var poolingProfile string
+	queueScorer := ""
+	for pluginName, plugin := range handle.GetAllPluginsWithNames() {
+		switch thePlugin := plugin.(type) {
+		case *profile.PoolingProfileHandler:
+			poolingProfile = thePlugin.PoolingProfile()
+		case *scorer.QueueScorer:
+			queueScorer = pluginName
+		}
+	}
+	if poolingProfile == "" {
+		return
+	}
+	for _, theProfile := range cfg.SchedulingProfiles {
+		if theProfile.Name == poolingProfile {
+			return
+		}
+	}
+
+	if queueScorer == "" {
+		handle.AddPlugin(scorer.QueueScorerType, scorer.NewQueueScorer())
+		queueScorer = scorer.QueueScorerType
+		cfg.Plugins = append(cfg.Plugins, configapi.PluginSpec{Name: queueScorer, Type: queueScorer})
+	}
+	cfg.SchedulingProfiles = append(cfg.SchedulingProfiles, configapi.SchedulingProfile{
+		Name:    poolingProfile,
+		Plugins: []configapi.SchedulingPlugin{{PluginRef: queueScorer}},
+	})
+}
@@ -18,8 +18,15 @@ package controller
 
 import (
 	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
 
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
@@ -36,6 +43,10 @@ type InferenceObjectiveReconciler struct {
 	client.Reader
 	Datastore datastore.Datastore
 	PoolGKNN  common.GKNN
+	// StatusWriter writes the status of the InferenceObjectives of the pool. The status is not written if nil.
+	StatusWriter client.StatusWriter
+	// StatusRefreshInterval is the interval the status is refreshed at, to keep the usage current.
+	StatusRefreshInterval time.Duration
 }
 
 func (c *InferenceObjectiveReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -65,7 +76,107 @@ func (c *InferenceObjectiveReconciler) Reconcile(ctx context.Context, req ctrl.R
 	c.Datastore.ObjectiveSet(infObjective)
 	logger.Info("Added/Updated InferenceObjective")
 
-	return ctrl.Result{}, nil
+	if c.StatusWriter == nil {
+		return ctrl.Result{}, nil
+	}
+	if err := c.updateStatus(ctx, infObjective); err != nil {
+		logger.Error(err, "Unable to update InferenceObjective status")
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: c.StatusRefreshInterval}, nil
+}
+
+// updateStatus writes the conditions and the usage of the InferenceObjective, if they changed.
+func (c *InferenceObjectiveReconciler) updateStatus(ctx context.Context, infObjective *v1alpha2.InferenceObjective) error {
+	status := infObjective.Status.DeepCopy()
+	// InferenceObjectives created with an earlier version of the CRD default to a pending "Ready" condition.
+	meta.RemoveStatusCondition(&status.Conditions, "Ready")
+
+	accepted := metav1.Condition{
+		Type:               string(v1alpha2.ObjectiveConditionAccepted),
+		Status:             metav1.ConditionTrue,
+		Reason:             string(v1alpha2.ObjectiveReasonAccepted),
+		Message:            "The InferenceObjective is accepted by the Endpoint Picker of the pool",
+		ObservedGeneration: infObjective.Generation,
+	}
+	if conflicting := c.conflictingObjective(infObjective); conflicting != nil {
+		accepted.Status = metav1.ConditionFalse
+		accepted.Reason = string(v1alpha2.ObjectiveReasonMatchConflict)
+		accepted.Message = fmt.Sprintf("The match rules are the same as those of the older InferenceObjective %s, "+
+			"which gets the requests that match them", conflicting.Name)
+	}
+	meta.SetStatusCondition(&status.Conditions, accepted)
+
+	resolvedRefs := metav1.Condition{
+		Type:               string(v1alpha2.ObjectiveConditionResolvedRefs),
+		Status:             metav1.ConditionTrue,
+		Reason:             string(v1alpha2.ObjectiveReasonResolvedRefs),
+		Message:            fmt.Sprintf("The InferencePool %s is found", c.PoolGKNN.Name),
+		ObservedGeneration: infObjective.Generation,
+	}
+	if !c.Datastore.PoolHasSynced() {
+		resolvedRefs.Status = metav1.ConditionFalse
+		resolvedRefs.Reason = string(v1alpha2.ObjectiveReasonPoolNotFound)
+		resolvedRefs.Message = fmt.Sprintf("The InferencePool %s is not found", c.PoolGKNN.Name)
+	}
+	meta.SetStatusCondition(&status.Conditions, resolvedRefs)
+
+	usage := c.Datastore.ObjectiveUsageGet(infObjective.Name)
+	status.Usage = &usage
+
+	if equality.Semantic.DeepEqual(status, &infObjective.Status) {
+		return nil
+	}
+	updated := infObjective.DeepCopy()
+	updated.Status = *status
+	return c.StatusWriter.Patch(ctx, updated, client.MergeFrom(infObjective))
+}
+
+// conflictingObjective returns the oldest InferenceObjective of the pool whose match rules are the same as
+// those of the given InferenceObjective and that is older than it, if any.
+func (c *InferenceObjectiveReconciler) conflictingObjective(infObjective *v1alpha2.InferenceObjective) *v1alpha2.InferenceObjective {
+	if infObjective.Spec.Match == nil {
+		return nil
+	}
+	var conflicting *v1alpha2.InferenceObjective
+	for _, other := range c.Datastore.ObjectiveGetAll() {
+		if other.Name == infObjective.Name || !sameMatch(other.Spec.Match, infObjective.Spec.Match) ||
+			!createdBefore(other, infObjective) {
+			continue
+		}
+		if conflicting == nil || createdBefore(other, conflicting) {
+			conflicting = other
+		}
+	}
+	return conflicting
+}
+
+// sameMatch returns whether the match rules match the same requests.
+func sameMatch(a, b *v1alpha2.ObjectiveMatch) bool {
+	if a == nil || b == nil {
+		return false
+	}
+	headers := func(match *v1alpha2.ObjectiveMatch) []string {
+		result := make([]string, 0, len(match.Headers))
+		for _, header := range match.Headers {
+			result = append(result, strings.ToLower(header.Name)+"="+header.Value)
+		}
+		slices.Sort(result)
+		return result
+	}
+	sortedModels := func(match *v1alpha2.ObjectiveMatch) []string {
+		return slices.Sorted(slices.Values(match.Models))
+	}
+	return slices.Equal(sortedModels(a), sortedModels(b)) && slices.Equal(headers(a), headers(b))
+}
+
+// createdBefore returns whether the InferenceObjective a was created before b, ordering by name on equal
+// creation times, as the datastore does when several InferenceObjectives match a request.
+func createdBefore(a, b *v1alpha2.InferenceObjective) bool {
+	if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.CreationTimestamp.Before(&b.CreationTimestamp)
+	}
+	return a.Name < b.Name
 }
 
 func (c *InferenceObjectiveReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) error {
@@ -74,6 +185,10 @@ func (c *InferenceObjectiveReconciler) SetupWithManager(ctx context.Context, mgr
 		WithEventFilter(predicate.Funcs{
 			CreateFunc: func(e event.CreateEvent) bool { return c.eventPredicate(e.Object.(*v1alpha2.InferenceObjective)) },
 			UpdateFunc: func(e event.UpdateEvent) bool {
+				// Updates of the status only, including the ones written by this reconciler, are ignored.
+				if e.ObjectOld.GetGeneration() == e.ObjectNew.GetGeneration() {
+					return false
+				}
 				return c.eventPredicate(e.ObjectOld.(*v1alpha2.InferenceObjective)) || c.eventPredicate(e.ObjectNew.(*v1alpha2.InferenceObjective))
 			},
 			DeleteFunc:  func(e event.DeleteEvent) bool { return c.eventPredicate(e.Object.(*v1alpha2.InferenceObjective)) },
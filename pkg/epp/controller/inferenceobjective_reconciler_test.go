@@ -22,6 +22,7 @@ import (
 	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -198,3 +199,134 @@ func TestInferenceObjectiveReconciler(t *testing.T) {
 		})
 	}
 }
+
+func TestInferenceObjectiveReconcilerStatus(t *testing.T) {
+	makeObjective := func(name string, created int64) *utiltest.InferenceObjectiveWrapper {
+		return utiltest.MakeInferenceObjective(name).
+			Namespace(pool.Namespace).
+			CreationTimestamp(metav1.Unix(created, 0)).
+			PoolName(pool.Name).
+			PoolGroup("inference.networking.k8s.io")
+	}
+	older := makeObjective("older", 1000).MatchModels("llama", "qwen").ObjRef()
+	conflicting := makeObjective("conflicting", 1001).MatchModels("qwen", "llama").ObjRef()
+	moreSpecific := makeObjective("more-specific", 1002).MatchModels("llama", "qwen").MatchHeader("x-tenant", "acme").ObjRef()
+	withoutMatch := makeObjective("without-match", 1003).ObjRef()
+
+	tests := []struct {
+		name          string
+		objective     *v1alpha2.InferenceObjective
+		poolSynced    bool
+		requests      int
+		rejected      int
+		wantAccepted  v1alpha2.InferenceObjectiveConditionReason
+		wantResolved  v1alpha2.InferenceObjectiveConditionReason
+		wantRequests  int64
+		wantRejected  int64
+		wantLastUsage bool
+	}{
+		{
+			name:         "oldest of objectives with the same match rules",
+			objective:    older,
+			poolSynced:   true,
+			wantAccepted: v1alpha2.ObjectiveReasonAccepted,
+			wantResolved: v1alpha2.ObjectiveReasonResolvedRefs,
+		},
+		{
+			name:         "same match rules as an older objective",
+			objective:    conflicting,
+			poolSynced:   true,
+			wantAccepted: v1alpha2.ObjectiveReasonMatchConflict,
+			wantResolved: v1alpha2.ObjectiveReasonResolvedRefs,
+		},
+		{
+			name:         "more specific match rules than an older objective",
+			objective:    moreSpecific,
+			poolSynced:   true,
+			wantAccepted: v1alpha2.ObjectiveReasonAccepted,
+			wantResolved: v1alpha2.ObjectiveReasonResolvedRefs,
+		},
+		{
+			name:          "usage",
+			objective:     withoutMatch,
+			poolSynced:    true,
+			requests:      3,
+			rejected:      1,
+			wantAccepted:  v1alpha2.ObjectiveReasonAccepted,
+			wantResolved:  v1alpha2.ObjectiveReasonResolvedRefs,
+			wantRequests:  3,
+			wantRejected:  1,
+			wantLastUsage: true,
+		},
+		{
+			name:         "pool not found",
+			objective:    withoutMatch,
+			wantAccepted: v1alpha2.ObjectiveReasonAccepted,
+			wantResolved: v1alpha2.ObjectiveReasonPoolNotFound,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			_ = clientgoscheme.AddToScheme(scheme)
+			_ = v1alpha2.Install(scheme)
+			_ = v1.Install(scheme)
+			objectives := []client.Object{older.DeepCopy(), conflicting.DeepCopy(), moreSpecific.DeepCopy(), withoutMatch.DeepCopy()}
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(objectives...).
+				WithStatusSubresource(objectives...).
+				Build()
+			pmf := backendmetrics.NewPodMetricsFactory(&backendmetrics.FakePodMetricsClient{}, time.Second)
+			ds := datastore.NewDatastore(t.Context(), pmf)
+			for _, objective := range []*v1alpha2.InferenceObjective{older, conflicting, moreSpecific, withoutMatch} {
+				ds.ObjectiveSet(objective)
+			}
+			if test.poolSynced {
+				_ = ds.PoolSet(context.Background(), fakeClient, pool)
+			}
+			for i := range test.requests {
+				ds.ObjectiveRecordRequest(test.objective.Name, i < test.rejected)
+			}
+			// The requests of other objectives are not counted.
+			ds.ObjectiveRecordRequest("other", true)
+			reconciler := &InferenceObjectiveReconciler{
+				Reader:    fakeClient,
+				Datastore: ds,
+				PoolGKNN: common.GKNN{
+					NamespacedName: types.NamespacedName{Name: pool.Name, Namespace: pool.Namespace},
+					GroupKind:      schema.GroupKind{Group: pool.GroupVersionKind().Group, Kind: pool.GroupVersionKind().Kind},
+				},
+				StatusWriter:          fakeClient.Status(),
+				StatusRefreshInterval: 30 * time.Second,
+			}
+
+			namespacedName := types.NamespacedName{Name: test.objective.Name, Namespace: test.objective.Namespace}
+			result, err := reconciler.Reconcile(context.Background(), ctrl.Request{NamespacedName: namespacedName})
+			if err != nil {
+				t.Fatalf("Unexpected InferenceObjective reconcile error: %v", err)
+			}
+			if diff := cmp.Diff(ctrl.Result{RequeueAfter: 30 * time.Second}, result); diff != "" {
+				t.Errorf("Unexpected result diff (+got/-want): %s", diff)
+			}
+
+			got := &v1alpha2.InferenceObjective{}
+			if err := fakeClient.Get(context.Background(), namespacedName, got); err != nil {
+				t.Fatalf("Unexpected InferenceObjective get error: %v", err)
+			}
+			accepted := meta.FindStatusCondition(got.Status.Conditions, string(v1alpha2.ObjectiveConditionAccepted))
+			if accepted == nil || accepted.Reason != string(test.wantAccepted) {
+				t.Errorf("Unexpected Accepted condition %v, want reason %s", accepted, test.wantAccepted)
+			}
+			resolved := meta.FindStatusCondition(got.Status.Conditions, string(v1alpha2.ObjectiveConditionResolvedRefs))
+			if resolved == nil || resolved.Reason != string(test.wantResolved) {
+				t.Errorf("Unexpected ResolvedRefs condition %v, want reason %s", resolved, test.wantResolved)
+			}
+			usage := got.Status.Usage
+			if usage == nil || usage.Requests != test.wantRequests || usage.RejectedRequests != test.wantRejected ||
+				(usage.LastRequestTime != nil) != test.wantLastUsage {
+				t.Errorf("Unexpected usage %+v, want %d requests and %d rejected", usage, test.wantRequests, test.wantRejected)
+			}
+		})
+	}
+}
@@ -19,19 +19,42 @@ package controller
 import (
 	"context"
 	"fmt"
+	"time"
 
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	v1 "sigs.k8s.io/gateway-api-inference-extension/api/v1"
 	"sigs.k8s.io/gateway-api-inference-extension/apix/v1alpha2"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/common"
+	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datastore"
 	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/logging"
 )
 
+const (
+	// EndpointPickerConditionReady is the type of the condition the Endpoint Picker reports in the status of
+	// the InferencePool, under a parent entry whose parentRef is the endpointPickerRef of the InferencePool.
+	EndpointPickerConditionReady = "EndpointPickerReady"
+
+	// EndpointPickerReasonReady is used when the Endpoint Picker has ready endpoints with fresh metrics.
+	EndpointPickerReasonReady = "Ready"
+	// EndpointPickerReasonNoReadyEndpoints is used when the InferencePool has no ready endpoints.
+	EndpointPickerReasonNoReadyEndpoints = "NoReadyEndpoints"
+	// EndpointPickerReasonMetricsStale is used when the metrics of none of the ready endpoints are fresh.
+	EndpointPickerReasonMetricsStale = "MetricsStale"
+
+	// maxPoolParents is the maximum number of parents in the status of an InferencePool.
+	maxPoolParents = 32
+)
+
 // InferencePoolReconciler utilizes the controller runtime to reconcile Instance Gateway resources
 // This implementation is just used for reading & maintaining data sync. The Gateway implementation
 // will have the proper controller that will create/manage objects on behalf of the server pool.
@@ -39,6 +62,14 @@ type InferencePoolReconciler struct {
 	client.Reader
 	Datastore datastore.Datastore
 	PoolGKNN  common.GKNN
+	// StatusWriter writes the status of the InferencePool. The status is not written if nil.
+	StatusWriter client.StatusWriter
+	// StatusRefreshInterval is the interval the status is refreshed at, to keep the endpoint counts current.
+	StatusRefreshInterval time.Duration
+	// MetricsStalenessThreshold is the age beyond which the metrics of an endpoint are reported as stale.
+	MetricsStalenessThreshold time.Duration
+	// ConfigLoadResult describes the result of loading the configuration of the Endpoint Picker.
+	ConfigLoadResult string
 }
 
 func (c *InferencePoolReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -102,18 +133,112 @@ func (c *InferencePoolReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		return ctrl.Result{}, err
 	}
 
-	return ctrl.Result{}, nil
+	if c.StatusWriter == nil {
+		return ctrl.Result{}, nil
+	}
+	if err := c.updateStatus(ctx, obj, v1infPool); err != nil {
+		logger.Error(err, "Unable to update InferencePool status")
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: c.StatusRefreshInterval}, nil
+}
+
+// updateStatus writes the condition of the Endpoint Picker in the status of the InferencePool, if it changed.
+// The condition is written under the parent entry of the Endpoint Picker, leaving the entries of the other
+// parents, typically Gateways, untouched.
+func (c *InferencePoolReconciler) updateStatus(ctx context.Context, obj client.Object, v1infPool *v1.InferencePool) error {
+	pool := v1infPool.DeepCopy()
+	parentRef := endpointPickerParentRef(pool)
+	index := -1
+	for i, parent := range pool.Status.Parents {
+		if sameParentRef(parent.ParentRef, parentRef) {
+			index = i
+			break
+		}
+	}
+	if index == -1 {
+		if len(pool.Status.Parents) >= maxPoolParents {
+			log.FromContext(ctx).V(logutil.DEFAULT).Info("InferencePool status has no room for the Endpoint Picker", "parents", len(pool.Status.Parents))
+			return nil
+		}
+		pool.Status.Parents = append(pool.Status.Parents, v1.ParentStatus{ParentRef: parentRef})
+		index = len(pool.Status.Parents) - 1
+	}
+	meta.SetStatusCondition(&pool.Status.Parents[index].Conditions, c.endpointPickerCondition(pool.Generation))
+
+	if equality.Semantic.DeepEqual(pool.Status, v1infPool.Status) {
+		return nil
+	}
+	// A merge patch replaces the whole list of parents, so it is only applied to the version of the pool it was
+	// computed from, not to one where a Gateway has updated its own entry since.
+	patch := client.MergeFromWithOptions(obj.DeepCopyObject().(client.Object), client.MergeFromWithOptimisticLock{})
+	switch obj.(type) {
+	case *v1alpha2.InferencePool:
+		xPool := &v1alpha2.InferencePool{}
+		if err := xPool.ConvertFrom(pool); err != nil {
+			return fmt.Errorf("failed to convert InferencePool to XInferencePool - %w", err)
+		}
+		return c.StatusWriter.Patch(ctx, xPool, patch)
+	default:
+		return c.StatusWriter.Patch(ctx, pool, patch)
+	}
+}
+
+// endpointPickerCondition returns the condition of the Endpoint Picker, from the ready endpoints of the pool,
+// the freshness of their metrics and the result of loading the configuration.
+func (c *InferencePoolReconciler) endpointPickerCondition(generation int64) metav1.Condition {
+	ready := len(c.Datastore.PodList(backendmetrics.AllPodsPredicate))
+	fresh := len(c.Datastore.PodList(backendmetrics.PodsWithFreshMetrics(c.MetricsStalenessThreshold)))
+	condition := metav1.Condition{
+		Type:               EndpointPickerConditionReady,
+		Status:             metav1.ConditionTrue,
+		Reason:             EndpointPickerReasonReady,
+		ObservedGeneration: generation,
+		Message: fmt.Sprintf("%d ready endpoints, %d with metrics fresher than %s; configuration: %s",
+			ready, fresh, c.MetricsStalenessThreshold, c.ConfigLoadResult),
+	}
+	switch {
+	case ready == 0:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = EndpointPickerReasonNoReadyEndpoints
+	case fresh == 0:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = EndpointPickerReasonMetricsStale
+	}
+	return condition
+}
+
+// endpointPickerParentRef returns the parentRef the Endpoint Picker reports its condition under, which refers
+// to the Endpoint Picker by the endpointPickerRef of the pool.
+func endpointPickerParentRef(pool *v1.InferencePool) v1.ParentReference {
+	ref := pool.Spec.EndpointPickerRef
+	return v1.ParentReference{
+		Group: ptr.To(ptr.Deref(ref.Group, "")),
+		Kind:  ptr.To(ptr.Deref(ref.Kind, "Service")),
+		Name:  ref.Name,
+	}
+}
+
+// sameParentRef returns whether the parentRefs refer to the same object, applying the defaults of the API.
+func sameParentRef(a, b v1.ParentReference) bool {
+	const defaultGroup, defaultKind = "gateway.networking.k8s.io", "Gateway"
+	return ptr.Deref(a.Group, defaultGroup) == ptr.Deref(b.Group, defaultGroup) &&
+		ptr.Deref(a.Kind, defaultKind) == ptr.Deref(b.Kind, defaultKind) &&
+		a.Name == b.Name && ptr.Deref(a.Namespace, "") == ptr.Deref(b.Namespace, "")
 }
 
 func (c *InferencePoolReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// Updates of the status only, including the ones written by this reconciler, are ignored.
 	switch c.PoolGKNN.Group {
 	case v1alpha2.GroupName:
 		return ctrl.NewControllerManagedBy(mgr).
 			For(&v1alpha2.InferencePool{}).
+			WithEventFilter(predicate.GenerationChangedPredicate{}).
 			Complete(c)
 	case v1.GroupName:
 		return ctrl.NewControllerManagedBy(mgr).
 			For(&v1.InferencePool{}).
+			WithEventFilter(predicate.GenerationChangedPredicate{}).
 			Complete(c)
 	default:
 		return fmt.Errorf("unknown group %s", c.PoolGKNN.Group)
@@ -18,16 +18,20 @@ package controller
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -361,3 +365,148 @@ func xDiffStore(t *testing.T, datastore datastore.Datastore, params xDiffStorePa
 	}
 	return ""
 }
+
+func TestInferencePoolReconcilerStatus(t *testing.T) {
+	gatewayParent := v1.ParentStatus{
+		ParentRef: v1.ParentReference{Name: "gateway", Namespace: ptr.To(v1.Namespace("pool1-ns"))},
+		Conditions: []metav1.Condition{{
+			Type:               string(v1.InferencePoolConditionResolvedRefs),
+			Status:             metav1.ConditionTrue,
+			Reason:             string(v1.InferencePoolReasonResolvedRefs),
+			LastTransitionTime: metav1.Unix(1000, 0),
+		}},
+	}
+	eppParentRef := v1.ParentReference{Group: ptr.To(v1.Group("")), Kind: ptr.To(v1.Kind("Service")), Name: "epp-service"}
+
+	tests := []struct {
+		name       string
+		alpha      bool
+		selector   map[string]string
+		wantStatus metav1.ConditionStatus
+		wantReason string
+	}{
+		{
+			name:       "ready endpoints with stale metrics",
+			selector:   selector_v1,
+			wantStatus: metav1.ConditionFalse,
+			wantReason: EndpointPickerReasonMetricsStale,
+		},
+		{
+			name:       "no ready endpoints",
+			selector:   map[string]string{"app": "none"},
+			wantStatus: metav1.ConditionFalse,
+			wantReason: EndpointPickerReasonNoReadyEndpoints,
+		},
+		{
+			name:       "XInferencePool",
+			alpha:      true,
+			selector:   selector_v1,
+			wantStatus: metav1.ConditionFalse,
+			wantReason: EndpointPickerReasonMetricsStale,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pool := utiltest.MakeInferencePool("pool1").
+				Namespace("pool1-ns").
+				Selector(test.selector).
+				TargetPorts(8080).
+				EndpointPickerRef("epp-service").ObjRef()
+			pool.Status.Parents = []v1.ParentStatus{gatewayParent}
+			var obj client.Object = pool
+			group := v1.GroupName
+			if test.alpha {
+				xPool := &v1alpha2.InferencePool{}
+				if err := xPool.ConvertFrom(pool); err != nil {
+					t.Fatalf("Unexpected conversion error: %v", err)
+				}
+				obj, group = xPool, v1alpha2.GroupName
+			}
+
+			scheme := runtime.NewScheme()
+			_ = clientgoscheme.AddToScheme(scheme)
+			_ = v1alpha2.Install(scheme)
+			_ = v1.Install(scheme)
+			initialObjects := []client.Object{obj}
+			for i := range pods {
+				initialObjects = append(initialObjects, pods[i])
+			}
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(initialObjects...).
+				WithStatusSubresource(obj).
+				Build()
+
+			namespacedName := types.NamespacedName{Name: pool.Name, Namespace: pool.Namespace}
+			ctx := context.Background()
+			pmf := backendmetrics.NewPodMetricsFactory(&backendmetrics.FakePodMetricsClient{}, time.Second)
+			reconciler := &InferencePoolReconciler{
+				Reader:    fakeClient,
+				Datastore: datastore.NewDatastore(ctx, pmf),
+				PoolGKNN: common.GKNN{
+					NamespacedName: namespacedName,
+					GroupKind:      schema.GroupKind{Group: group, Kind: "InferencePool"},
+				},
+				StatusWriter:          fakeClient.Status(),
+				StatusRefreshInterval: 30 * time.Second,
+				// The metrics of the endpoints are never fresh.
+				MetricsStalenessThreshold: 0,
+				ConfigLoadResult:          "configured through code",
+			}
+
+			result, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: namespacedName})
+			if err != nil {
+				t.Fatalf("Unexpected InferencePool reconcile error: %v", err)
+			}
+			if diff := cmp.Diff(ctrl.Result{RequeueAfter: 30 * time.Second}, result); diff != "" {
+				t.Errorf("Unexpected result diff (+got/-want): %s", diff)
+			}
+
+			getPool := func() *v1.InferencePool {
+				got := &v1.InferencePool{}
+				if test.alpha {
+					xPool := &v1alpha2.InferencePool{}
+					if err := fakeClient.Get(ctx, namespacedName, xPool); err != nil {
+						t.Fatalf("Unexpected pool get error: %v", err)
+					}
+					if err := xPool.ConvertTo(got); err != nil {
+						t.Fatalf("Unexpected conversion error: %v", err)
+					}
+				} else if err := fakeClient.Get(ctx, namespacedName, got); err != nil {
+					t.Fatalf("Unexpected pool get error: %v", err)
+				}
+				return got
+			}
+			got := getPool()
+			if len(got.Status.Parents) != 2 {
+				t.Fatalf("Unexpected parents %v, want the gateway and the Endpoint Picker", got.Status.Parents)
+			}
+			if diff := cmp.Diff(gatewayParent, got.Status.Parents[0]); diff != "" {
+				t.Errorf("Unexpected gateway parent status diff (+got/-want): %s", diff)
+			}
+			if diff := cmp.Diff(eppParentRef, got.Status.Parents[1].ParentRef); diff != "" {
+				t.Errorf("Unexpected Endpoint Picker parentRef diff (+got/-want): %s", diff)
+			}
+			condition := meta.FindStatusCondition(got.Status.Parents[1].Conditions, EndpointPickerConditionReady)
+			if condition == nil {
+				t.Fatalf("Missing %s condition in %v", EndpointPickerConditionReady, got.Status.Parents[1].Conditions)
+			}
+			if condition.Status != test.wantStatus || condition.Reason != test.wantReason {
+				t.Errorf("Unexpected condition %s/%s, want %s/%s", condition.Status, condition.Reason, test.wantStatus, test.wantReason)
+			}
+			if !strings.Contains(condition.Message, "configured through code") {
+				t.Errorf("Expected the condition message %q to report the configuration", condition.Message)
+			}
+
+			// The status is not written again when it didn't change.
+			resourceVersion := got.ResourceVersion
+			if _, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: namespacedName}); err != nil {
+				t.Fatalf("Unexpected InferencePool reconcile error: %v", err)
+			}
+			if got := getPool(); got.ResourceVersion != resourceVersion {
+				t.Errorf("Unexpected status update, resource version %s, want %s", got.ResourceVersion, resourceVersion)
+			}
+		})
+	}
+}
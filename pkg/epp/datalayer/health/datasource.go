@@ -0,0 +1,289 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datalayer"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datalayer/models"
+	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/logging"
+)
+
+const (
+	DataSourceName = "health-data-source"
+
+	// DefaultProbeInterval is the default interval between the probes of an endpoint.
+	DefaultProbeInterval = 5 * time.Second
+	// DefaultFailureThreshold is the default number of consecutive failed probes that mark an endpoint as not healthy.
+	DefaultFailureThreshold = 2
+
+	// maxModelsResponseSize bounds the size of the model list response that is read.
+	maxModelsResponseSize = 1 << 20
+)
+
+// ProbePaths are the paths of the health and model-list endpoints of a model server.
+type ProbePaths struct {
+	// HealthPath is the path of the endpoint that returns 200 when the model server is healthy.
+	HealthPath string
+	// ModelsPath is the path of the endpoint that returns the OpenAI list of the served models.
+	// If empty, the model list is not probed.
+	ModelsPath string
+}
+
+// ModelServerProbePaths are the probe paths of the known model-server types.
+var ModelServerProbePaths = map[string]ProbePaths{
+	"vllm":                {HealthPath: "/health", ModelsPath: "/v1/models"},
+	"sglang":              {HealthPath: "/health", ModelsPath: "/v1/models"},
+	"triton-tensorrt-llm": {HealthPath: "/v2/health/ready"},
+}
+
+// Config is the configuration of the health DataSource.
+type Config struct {
+	ProbePaths
+	// Scheme is the scheme of the probe URLs, http or https.
+	Scheme string
+	// InsecureSkipVerify skips the verification of the model server certificate, when the scheme is https.
+	InsecureSkipVerify bool
+	// Interval is the interval between the probes of an endpoint.
+	Interval time.Duration
+	// FailureThreshold is the number of consecutive failed probes that mark an endpoint as not healthy.
+	// A single successful probe marks it as healthy.
+	FailureThreshold int
+	// DiscoverModels stores the base models and adapters of the probed model list as the ServedModels attribute
+	// of the endpoint. The model list is probed on models.DefaultModelsPath if ModelsPath is not set.
+	DiscoverModels bool
+}
+
+// DataSource actively probes the health and model-list endpoints of the model servers, on its own interval,
+// and stores the result as the Health attribute of the endpoint, and the discovered models as its ServedModels
+// attribute if configured.
+type DataSource struct {
+	config Config
+	port   atomic.Pointer[string] // the port of the model server, the target port of the pool
+
+	client     *http.Client
+	extractors sync.Map // key: name, value: extractor
+}
+
+// NewDataSource returns a new health DataSource with the given configuration.
+func NewDataSource(config Config) *DataSource {
+	if config.Interval <= 0 {
+		config.Interval = DefaultProbeInterval
+	}
+	if config.FailureThreshold <= 0 {
+		config.FailureThreshold = DefaultFailureThreshold
+	}
+	if config.DiscoverModels && config.ModelsPath == "" {
+		config.ModelsPath = models.DefaultModelsPath
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if config.Scheme == "https" {
+		transport.TLSClientConfig = &tls.Config{
+			InsecureSkipVerify: config.InsecureSkipVerify,
+		}
+	}
+	return &DataSource{
+		config: config,
+		client: &http.Client{Transport: transport},
+	}
+}
+
+// SetPort updates the port of the model servers. The port value can only be set once. A port value of 0 is ignored.
+func (dataSrc *DataSource) SetPort(port int32) {
+	if dataSrc.port.Load() != nil || port == 0 {
+		return
+	}
+	portStr := strconv.Itoa(int(port))
+	dataSrc.port.Store(&portStr)
+}
+
+// Name returns the health data source name.
+func (dataSrc *DataSource) Name() string {
+	return DataSourceName
+}
+
+// AddExtractor adds an extractor to the data source, validating it can process the probed Health.
+func (dataSrc *DataSource) AddExtractor(extractor datalayer.Extractor) error {
+	if err := datalayer.ValidateExtractorType(HealthType, extractor.ExpectedInputType()); err != nil {
+		return err
+	}
+	if _, loaded := dataSrc.extractors.LoadOrStore(extractor.Name(), extractor); loaded {
+		return fmt.Errorf("attempt to add extractor with duplicate name %s to %s", extractor.Name(), dataSrc.Name())
+	}
+	return nil
+}
+
+// Collect probes the endpoint if its probe interval passed, and stores the updated Health on the endpoint.
+// The previously discovered models are kept if the probe fails.
+func (dataSrc *DataSource) Collect(ctx context.Context, ep datalayer.Endpoint) error {
+	port := dataSrc.port.Load()
+	if port == nil {
+		return nil // the pool was not synced yet
+	}
+	previous, probed := GetHealth(ep)
+	now := time.Now()
+	if probed && now.Sub(previous.LastProbeTime) < dataSrc.config.Interval {
+		return nil
+	}
+
+	health := &Health{Healthy: true, LastProbeTime: now}
+	list, err := dataSrc.probe(ctx, ep.GetPod(), *port)
+	if err == nil {
+		health.Models = list.ids()
+		if dataSrc.config.DiscoverModels {
+			dataSrc.storeServedModels(ctx, ep, list.servedModels(now))
+		}
+	} else {
+		health.ConsecutiveFailures = 1
+		health.Reason = err.Error()
+		if probed {
+			health.ConsecutiveFailures = previous.ConsecutiveFailures + 1
+			health.Models = previous.Models
+		}
+		// an endpoint that was never found healthy, e.g. still loading its model, is not healthy on the first failure.
+		health.Healthy = probed && previous.Healthy && health.ConsecutiveFailures < dataSrc.config.FailureThreshold
+	}
+	ep.Put(HealthAttributeKey, health)
+
+	if !probed || previous.Healthy != health.Healthy {
+		log.FromContext(ctx).V(logutil.DEFAULT).Info("Model server health changed", "pod", ep.GetPod().GetNamespacedName(),
+			"healthy", health.Healthy, "reason", health.Reason)
+	}
+
+	var errs []error
+	dataSrc.extractors.Range(func(_, val any) bool {
+		if ex, ok := val.(datalayer.Extractor); ok {
+			if err := ex.Extract(ctx, health, ep); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return true // continue iteration
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// storeServedModels stores the discovered models on the endpoint, logging when they changed.
+func (dataSrc *DataSource) storeServedModels(ctx context.Context, ep datalayer.Endpoint, served *models.ServedModels) {
+	previous, discovered := models.GetServedModels(ep)
+	ep.Put(models.ServedModelsAttributeKey, served)
+	if !discovered || !served.EqualModels(previous) {
+		log.FromContext(ctx).V(logutil.DEFAULT).Info("Model server models changed", "pod", ep.GetPod().GetNamespacedName(),
+			"baseModels", served.BaseModels, "adapters", served.Adapters)
+	}
+}
+
+// modelList is the OpenAI list of models. Adapters have the base model they're loaded on as their parent.
+type modelList struct {
+	Data []struct {
+		ID     string  `json:"id"`
+		Parent *string `json:"parent"`
+	} `json:"data"`
+}
+
+// ids returns the names of the listed models, or nil if the model list is not probed.
+func (l *modelList) ids() []string {
+	if l == nil {
+		return nil
+	}
+	ids := make([]string, len(l.Data))
+	for i, model := range l.Data {
+		ids[i] = model.ID
+	}
+	return ids
+}
+
+// servedModels returns the base models and adapters of the listed models.
+func (l *modelList) servedModels(updateTime time.Time) *models.ServedModels {
+	served := &models.ServedModels{Adapters: map[string]string{}, LastUpdateTime: updateTime}
+	for _, model := range l.Data {
+		if model.Parent != nil && *model.Parent != "" {
+			served.Adapters[model.ID] = *model.Parent
+		} else {
+			served.BaseModels = append(served.BaseModels, model.ID)
+		}
+	}
+	return served
+}
+
+// probe gets the health endpoint of the model server, and its model list if configured, returning the model list.
+func (dataSrc *DataSource) probe(ctx context.Context, ep datalayer.Addressable, port string) (*modelList, error) {
+	if dataSrc.config.HealthPath != "" {
+		resp, err := dataSrc.get(ctx, ep, port, dataSrc.config.HealthPath)
+		if err != nil {
+			return nil, err
+		}
+		_ = resp.Body.Close()
+	}
+	if dataSrc.config.ModelsPath == "" {
+		return nil, nil
+	}
+
+	resp, err := dataSrc.get(ctx, ep, port, dataSrc.config.ModelsPath)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	list := &modelList{}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxModelsResponseSize)).Decode(list); err != nil {
+		return nil, fmt.Errorf("failed to decode the model list of %s: %w", ep.GetNamespacedName(), err)
+	}
+	if len(list.Data) == 0 {
+		return nil, fmt.Errorf("no models served by %s", ep.GetNamespacedName())
+	}
+	return list, nil
+}
+
+func (dataSrc *DataSource) get(ctx context.Context, ep datalayer.Addressable, port string, path string) (*http.Response, error) {
+	target := &url.URL{
+		Scheme: dataSrc.config.Scheme,
+		Host:   net.JoinHostPort(ep.GetIPAddress(), port),
+		Path:   path,
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+	resp, err := dataSrc.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to probe %s of %s: %w", path, ep.GetNamespacedName(), err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("unexpected status code from %s of %s: %v", path, ep.GetNamespacedName(), resp.StatusCode)
+	}
+	return resp, nil
+}
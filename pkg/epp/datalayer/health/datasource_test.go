@@ -0,0 +1,188 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datalayer"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datalayer/models"
+)
+
+func TestDataSourceCollect(t *testing.T) {
+	healthStatus := http.StatusOK
+	modelList := `{"object":"list","data":[{"id":"base-model"},{"id":"lora-adapter"}]}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/health":
+			w.WriteHeader(healthStatus)
+		case "/v1/models":
+			_, _ = w.Write([]byte(modelList))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	host, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+	portNumber, _ := strconv.Atoi(port)
+
+	source := NewDataSource(Config{ProbePaths: ModelServerProbePaths["vllm"], Scheme: "http", Interval: time.Nanosecond})
+	ep := datalayer.NewEndpoint()
+	ep.UpdatePod(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod1"}, Status: corev1.PodStatus{PodIP: host}})
+	ctx := context.Background()
+
+	// endpoints are not probed before the pool is synced.
+	if err := source.Collect(ctx, ep); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, found := GetHealth(ep); found || !IsServing(ep) {
+		t.Fatalf("Expected an endpoint that was not probed to be serving")
+	}
+	source.SetPort(int32(portNumber))
+
+	steps := []struct {
+		name         string
+		healthStatus int
+		modelList    string
+		wantHealthy  bool
+		wantModels   []string
+		wantFailures int
+	}{
+		{name: "healthy", healthStatus: http.StatusOK, modelList: modelList, wantHealthy: true, wantModels: []string{"base-model", "lora-adapter"}},
+		{name: "first failure", healthStatus: http.StatusServiceUnavailable, modelList: modelList, wantHealthy: true,
+			wantModels: []string{"base-model", "lora-adapter"}, wantFailures: 1},
+		{name: "failure threshold", healthStatus: http.StatusServiceUnavailable, modelList: modelList, wantHealthy: false,
+			wantModels: []string{"base-model", "lora-adapter"}, wantFailures: 2},
+		{name: "recovered", healthStatus: http.StatusOK, modelList: modelList, wantHealthy: true, wantModels: []string{"base-model", "lora-adapter"}},
+		{name: "no models served", healthStatus: http.StatusOK, modelList: `{"object":"list","data":[]}`, wantHealthy: true,
+			wantModels: []string{"base-model", "lora-adapter"}, wantFailures: 1},
+	}
+	for _, step := range steps {
+		healthStatus = step.healthStatus
+		modelList = step.modelList
+		err := source.Collect(ctx, ep)
+		if gotErr := err != nil; gotErr != (step.wantFailures > 0) {
+			t.Errorf("%s: unexpected error: %v", step.name, err)
+		}
+		health, found := GetHealth(ep)
+		if !found {
+			t.Fatalf("%s: expected the endpoint health to be stored", step.name)
+		}
+		if health.Healthy != step.wantHealthy || IsServing(ep) != step.wantHealthy {
+			t.Errorf("%s: unexpected health, want healthy %t, got %v", step.name, step.wantHealthy, health)
+		}
+		if health.ConsecutiveFailures != step.wantFailures {
+			t.Errorf("%s: unexpected consecutive failures, want %d, got %d", step.name, step.wantFailures, health.ConsecutiveFailures)
+		}
+		if diff := cmp.Diff(step.wantModels, health.Models); diff != "" {
+			t.Errorf("%s: unexpected models (-want +got): %v", step.name, diff)
+		}
+	}
+	if _, found := models.GetServedModels(ep); found {
+		t.Errorf("Expected the served models not to be stored without model discovery")
+	}
+}
+
+func TestDataSourceCollectDiscoverModels(t *testing.T) {
+	status := http.StatusOK
+	body := `{"object":"list","data":[
+		{"id":"base-model","object":"model","root":"base-model","parent":null},
+		{"id":"sql-lora","object":"model","root":"/adapters/sql-lora","parent":"base-model"}]}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != models.DefaultModelsPath {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+	host, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+	portNumber, _ := strconv.Atoi(port)
+
+	// the model list is probed on the default path, when the model-server type has no model-list endpoint.
+	source := NewDataSource(Config{Scheme: "http", Interval: time.Nanosecond, DiscoverModels: true})
+	source.SetPort(int32(portNumber))
+	ep := datalayer.NewEndpoint()
+	ep.UpdatePod(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod1"}, Status: corev1.PodStatus{PodIP: host}})
+	ctx := context.Background()
+
+	want := &models.ServedModels{BaseModels: []string{"base-model"}, Adapters: map[string]string{"sql-lora": "base-model"}}
+	if err := source.Collect(ctx, ep); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	got, _ := models.GetServedModels(ep)
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreFields(models.ServedModels{}, "LastUpdateTime")); diff != "" {
+		t.Errorf("Unexpected served models (-want +got): %v", diff)
+	}
+	for model, wantServed := range map[string]bool{"base-model": true, "sql-lora": true, "other-model": false} {
+		if served := models.CanServe(ep, model); served != wantServed {
+			t.Errorf("Unexpected CanServe(%s), want %t, got %t", model, wantServed, served)
+		}
+	}
+
+	// the discovered models are kept when the probe fails.
+	status = http.StatusInternalServerError
+	if err := source.Collect(ctx, ep); err == nil {
+		t.Errorf("Expected a probe error")
+	}
+	got, _ = models.GetServedModels(ep)
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreFields(models.ServedModels{}, "LastUpdateTime")); diff != "" {
+		t.Errorf("Unexpected served models after a failed probe (-want +got): %v", diff)
+	}
+}
+
+func TestDataSourceCollectNeverHealthy(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable) // e.g. the model is still loading
+	}))
+	defer server.Close()
+	host, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+	portNumber, _ := strconv.Atoi(port)
+
+	source := NewDataSource(Config{ProbePaths: ModelServerProbePaths["vllm"], Scheme: "http"})
+	source.SetPort(int32(portNumber))
+	ep := datalayer.NewEndpoint()
+	ep.UpdatePod(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod1"}, Status: corev1.PodStatus{PodIP: host}})
+
+	if err := source.Collect(context.Background(), ep); err == nil {
+		t.Errorf("Expected a probe error")
+	}
+	if IsServing(ep) {
+		t.Errorf("Expected an endpoint that was never healthy not to be serving after a failed probe")
+	}
+
+	// the endpoint is not probed again within the interval.
+	health, _ := GetHealth(ep)
+	if err := source.Collect(context.Background(), ep); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if again, _ := GetHealth(ep); !again.LastProbeTime.Equal(health.LastProbeTime) {
+		t.Errorf("Expected the endpoint not to be probed within the probe interval")
+	}
+}
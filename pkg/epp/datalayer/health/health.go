@@ -0,0 +1,84 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"time"
+
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datalayer"
+)
+
+const (
+	// HealthAttributeKey is the endpoint attribute key the probed health of the model server is stored under.
+	HealthAttributeKey = "model-server-health"
+)
+
+var (
+	HealthType = reflect.TypeOf(&Health{})
+)
+
+// Health is the health of a model server, as probed by the health DataSource.
+type Health struct {
+	// Healthy is set if the model server is ready to serve requests.
+	Healthy bool
+	// Reason describes why the model server is not healthy.
+	Reason string
+	// Models are the models served by the model server, if its model list is probed.
+	Models []string
+	// ConsecutiveFailures is the number of consecutive failed probes.
+	ConsecutiveFailures int
+	// LastProbeTime is the time of the last probe.
+	LastProbeTime time.Time
+}
+
+// String returns a string with the health information.
+func (h *Health) String() string {
+	if h == nil {
+		return ""
+	}
+	return fmt.Sprintf("%+v", *h)
+}
+
+// Clone creates a copy of Health and returns its pointer.
+func (h *Health) Clone() datalayer.Cloneable {
+	if h == nil {
+		return nil
+	}
+	clone := *h
+	clone.Models = slices.Clone(h.Models)
+	return &clone
+}
+
+// GetHealth returns the probed health of the endpoint, or false if the endpoint was not probed.
+func GetHealth(ep datalayer.AttributeMap) (*Health, bool) {
+	value, found := ep.Get(HealthAttributeKey)
+	if !found {
+		return nil, false
+	}
+	health, ok := value.(*Health)
+	return health, ok
+}
+
+// IsServing returns false if the endpoint was probed and found not healthy. Endpoints that were not probed yet,
+// or are not probed at all, are assumed to be serving.
+func IsServing(ep datalayer.AttributeMap) bool {
+	health, found := GetHealth(ep)
+	return !found || health.Healthy
+}
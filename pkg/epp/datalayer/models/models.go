@@ -0,0 +1,102 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"time"
+
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datalayer"
+)
+
+const (
+	// ServedModelsAttributeKey is the endpoint attribute key the discovered models of the model server are stored under.
+	ServedModelsAttributeKey = "served-models"
+
+	// DefaultModelsPath is the default path of the OpenAI model list endpoint of the model servers.
+	DefaultModelsPath = "/v1/models"
+)
+
+var (
+	ServedModelsType = reflect.TypeOf(&ServedModels{})
+)
+
+// ServedModels are the base models and adapters served by a model server, as discovered from its model list
+// by the health DataSource.
+type ServedModels struct {
+	// BaseModels are the names of the base models served by the model server.
+	BaseModels []string
+	// Adapters maps the names of the (LoRA) adapters loaded by the model server to the name of their base model.
+	Adapters map[string]string
+	// LastUpdateTime is the time the models were last discovered.
+	LastUpdateTime time.Time
+}
+
+// String returns a string with the served models information.
+func (m *ServedModels) String() string {
+	if m == nil {
+		return ""
+	}
+	return fmt.Sprintf("%+v", *m)
+}
+
+// Clone creates a copy of ServedModels and returns its pointer.
+func (m *ServedModels) Clone() datalayer.Cloneable {
+	if m == nil {
+		return nil
+	}
+	clone := *m
+	clone.BaseModels = slices.Clone(m.BaseModels)
+	clone.Adapters = maps.Clone(m.Adapters)
+	return &clone
+}
+
+// EqualModels returns true if both have the same base models and adapters, in the same order.
+func (m *ServedModels) EqualModels(other *ServedModels) bool {
+	return slices.Equal(m.BaseModels, other.BaseModels) && maps.Equal(m.Adapters, other.Adapters)
+}
+
+// Serves returns true if the model is one of the base models or adapters served by the model server.
+func (m *ServedModels) Serves(model string) bool {
+	if _, found := m.Adapters[model]; found {
+		return true
+	}
+	return slices.Contains(m.BaseModels, model)
+}
+
+// GetServedModels returns the discovered models of the endpoint, or false if its models were not discovered.
+func GetServedModels(ep datalayer.AttributeMap) (*ServedModels, bool) {
+	if ep == nil {
+		return nil, false
+	}
+	value, found := ep.Get(ServedModelsAttributeKey)
+	if !found {
+		return nil, false
+	}
+	served, ok := value.(*ServedModels)
+	return served, ok
+}
+
+// CanServe returns true if the endpoint serves the model. Endpoints whose models were not discovered yet,
+// or are not discovered at all, are assumed to serve every model.
+func CanServe(ep datalayer.AttributeMap, model string) bool {
+	served, found := GetServedModels(ep)
+	return !found || served.Serves(model)
+}
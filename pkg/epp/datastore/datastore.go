@@ -21,9 +21,12 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"slices"
+	"strings"
 	"sync"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -33,6 +36,7 @@ import (
 	"sigs.k8s.io/gateway-api-inference-extension/apix/v1alpha2"
 	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datalayer"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datalayer/health"
 	dlmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datalayer/metrics"
 	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/logging"
 	podutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/pod"
@@ -58,6 +62,12 @@ type Datastore interface {
 	ObjectiveGet(objectiveName string) *v1alpha2.InferenceObjective
 	ObjectiveDelete(namespacedName types.NamespacedName)
 	ObjectiveGetAll() []*v1alpha2.InferenceObjective
+	// ObjectiveMatch returns the InferenceObjective that matches a request for the model with the headers, if any.
+	ObjectiveMatch(model string, headers map[string]string) *v1alpha2.InferenceObjective
+	// ObjectiveRecordRequest records a request of the InferenceObjective, and whether it was rejected.
+	ObjectiveRecordRequest(objectiveName string, rejected bool)
+	// ObjectiveUsageGet returns the usage of the InferenceObjective recorded since it was added to the datastore.
+	ObjectiveUsageGet(objectiveName string) v1alpha2.ObjectiveUsage
 
 	// PodList lists pods matching the given predicate.
 	PodList(predicate func(backendmetrics.PodMetrics) bool) []backendmetrics.PodMetrics
@@ -73,6 +83,7 @@ func NewDatastore(parentCtx context.Context, epFactory datalayer.EndpointFactory
 		parentCtx:           parentCtx,
 		poolAndObjectivesMu: sync.RWMutex{},
 		objectives:          make(map[string]*v1alpha2.InferenceObjective),
+		usage:               make(map[string]*v1alpha2.ObjectiveUsage),
 		pods:                &sync.Map{},
 		epf:                 epFactory,
 	}
@@ -87,6 +98,11 @@ type datastore struct {
 	pool                *v1.InferencePool
 	// key: InferenceObjective.Spec.ModelName, value: *InferenceObjective
 	objectives map[string]*v1alpha2.InferenceObjective
+	// usageMu is used to synchronize access to the usage map, separately from the objectives as it is updated
+	// on every request.
+	usageMu sync.Mutex
+	// key: InferenceObjective name, value: the usage of the InferenceObjective
+	usage map[string]*v1alpha2.ObjectiveUsage
 	// key: types.NamespacedName, value: backendmetrics.PodMetrics
 	pods *sync.Map
 	epf  datalayer.EndpointFactory
@@ -97,6 +113,9 @@ func (ds *datastore) Clear() {
 	defer ds.poolAndObjectivesMu.Unlock()
 	ds.pool = nil
 	ds.objectives = make(map[string]*v1alpha2.InferenceObjective)
+	ds.usageMu.Lock()
+	ds.usage = make(map[string]*v1alpha2.ObjectiveUsage)
+	ds.usageMu.Unlock()
 	// stop all pods go routines before clearing the pods map.
 	ds.pods.Range(func(_, v any) bool {
 		ds.epf.ReleaseEndpoint(v.(backendmetrics.PodMetrics))
@@ -121,6 +140,9 @@ func (ds *datastore) PoolSet(ctx context.Context, reader client.Reader, pool *v1
 		if source, found := datalayer.GetNamedSource[*dlmetrics.DataSource](dlmetrics.DataSourceName); found {
 			source.SetPort(int32(pool.Spec.TargetPorts[0].Number))
 		}
+		if source, found := datalayer.GetNamedSource[*health.DataSource](health.DataSourceName); found {
+			source.SetPort(int32(pool.Spec.TargetPorts[0].Number))
+		}
 	}
 	if oldPool == nil || !reflect.DeepEqual(pool.Spec.Selector, oldPool.Spec.Selector) {
 		logger.V(logutil.DEFAULT).Info("Updating inference pool endpoints", "selector", pool.Spec.Selector)
@@ -185,6 +207,9 @@ func (ds *datastore) ObjectiveDelete(namespacedName types.NamespacedName) {
 	ds.poolAndObjectivesMu.Lock()
 	defer ds.poolAndObjectivesMu.Unlock()
 	delete(ds.objectives, namespacedName.Name)
+	ds.usageMu.Lock()
+	defer ds.usageMu.Unlock()
+	delete(ds.usage, namespacedName.Name)
 }
 
 func (ds *datastore) ObjectiveGetAll() []*v1alpha2.InferenceObjective {
@@ -197,6 +222,84 @@ func (ds *datastore) ObjectiveGetAll() []*v1alpha2.InferenceObjective {
 	return res
 }
 
+// ObjectiveMatch returns the InferenceObjective whose match rules a request for the model with the headers
+// matches. When several InferenceObjectives match, the one with the most match conditions is returned, and
+// among those the oldest one. The header names are expected in lower case, as Envoy passes them.
+func (ds *datastore) ObjectiveMatch(model string, headers map[string]string) *v1alpha2.InferenceObjective {
+	ds.poolAndObjectivesMu.RLock()
+	defer ds.poolAndObjectivesMu.RUnlock()
+
+	var matched *v1alpha2.InferenceObjective
+	matchedConditions := 0
+	for _, objective := range ds.objectives {
+		conditions, ok := objectiveMatches(objective, model, headers)
+		if !ok {
+			continue
+		}
+		if matched == nil || conditions > matchedConditions ||
+			(conditions == matchedConditions && olderObjective(objective, matched)) {
+			matched, matchedConditions = objective, conditions
+		}
+	}
+	return matched
+}
+
+// objectiveMatches returns whether the request matches the match rules of the objective, and the number of
+// conditions it matched.
+func objectiveMatches(objective *v1alpha2.InferenceObjective, model string, headers map[string]string) (int, bool) {
+	match := objective.Spec.Match
+	if match == nil {
+		return 0, false
+	}
+	conditions := 0
+	if len(match.Models) > 0 {
+		if !slices.Contains(match.Models, model) {
+			return 0, false
+		}
+		conditions++
+	}
+	for _, header := range match.Headers {
+		if value, ok := headers[strings.ToLower(header.Name)]; !ok || value != header.Value {
+			return 0, false
+		}
+		conditions++
+	}
+	return conditions, conditions > 0
+}
+
+// olderObjective returns whether the objective a was created before b, ordering by name on equal creation times.
+func olderObjective(a, b *v1alpha2.InferenceObjective) bool {
+	if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.CreationTimestamp.Before(&b.CreationTimestamp)
+	}
+	return a.Name < b.Name
+}
+
+func (ds *datastore) ObjectiveRecordRequest(objectiveName string, rejected bool) {
+	ds.usageMu.Lock()
+	defer ds.usageMu.Unlock()
+	usage, ok := ds.usage[objectiveName]
+	if !ok {
+		usage = &v1alpha2.ObjectiveUsage{}
+		ds.usage[objectiveName] = usage
+	}
+	usage.Requests++
+	if rejected {
+		usage.RejectedRequests++
+	}
+	now := metav1.Now()
+	usage.LastRequestTime = &now
+}
+
+func (ds *datastore) ObjectiveUsageGet(objectiveName string) v1alpha2.ObjectiveUsage {
+	ds.usageMu.Lock()
+	defer ds.usageMu.Unlock()
+	if usage, ok := ds.usage[objectiveName]; ok {
+		return *usage.DeepCopy()
+	}
+	return v1alpha2.ObjectiveUsage{}
+}
+
 // /// Pods/endpoints APIs ///
 // TODO: add a flag for callers to specify the staleness threshold for metrics.
 // ref: https://github.com/kubernetes-sigs/gateway-api-inference-extension/pull/1046#discussion_r2246351694
@@ -106,6 +106,109 @@ func TestPool(t *testing.T) {
 	}
 }
 
+func TestObjectiveMatch(t *testing.T) {
+	older := metav1.NewTime(time.Now().Add(-time.Hour))
+	newer := metav1.NewTime(time.Now())
+	byModel := testutil.MakeInferenceObjective("by-model").MatchModels("llama", "qwen").CreationTimestamp(newer).ObjRef()
+	byOlderModel := testutil.MakeInferenceObjective("by-older-model").MatchModels("llama").CreationTimestamp(older).ObjRef()
+	byHeader := testutil.MakeInferenceObjective("by-header").MatchHeader("X-Tenant", "acme").ObjRef()
+	byModelAndHeader := testutil.MakeInferenceObjective("by-model-and-header").MatchModels("qwen").MatchHeader("x-tenant", "acme").ObjRef()
+	withoutMatch := testutil.MakeInferenceObjective("without-match").ObjRef()
+
+	tests := []struct {
+		name       string
+		objectives []*v1alpha2.InferenceObjective
+		model      string
+		headers    map[string]string
+		want       *v1alpha2.InferenceObjective
+	}{
+		{
+			name:       "no objectives with match rules",
+			objectives: []*v1alpha2.InferenceObjective{withoutMatch},
+			model:      "llama",
+		},
+		{
+			name:       "match by model",
+			objectives: []*v1alpha2.InferenceObjective{withoutMatch, byModel},
+			model:      "qwen",
+			want:       byModel,
+		},
+		{
+			name:       "no match for another model",
+			objectives: []*v1alpha2.InferenceObjective{byModel},
+			model:      "mistral",
+		},
+		{
+			name:       "oldest of equally specific matches",
+			objectives: []*v1alpha2.InferenceObjective{byModel, byOlderModel},
+			model:      "llama",
+			want:       byOlderModel,
+		},
+		{
+			name:       "match by header",
+			objectives: []*v1alpha2.InferenceObjective{byModel, byHeader},
+			model:      "mistral",
+			headers:    map[string]string{"x-tenant": "acme"},
+			want:       byHeader,
+		},
+		{
+			name:       "no match for another header value",
+			objectives: []*v1alpha2.InferenceObjective{byHeader},
+			model:      "mistral",
+			headers:    map[string]string{"x-tenant": "other"},
+		},
+		{
+			name:       "most specific match",
+			objectives: []*v1alpha2.InferenceObjective{byModel, byHeader, byModelAndHeader},
+			model:      "qwen",
+			headers:    map[string]string{"x-tenant": "acme"},
+			want:       byModelAndHeader,
+		},
+		{
+			name:       "all conditions must match",
+			objectives: []*v1alpha2.InferenceObjective{byModelAndHeader},
+			model:      "llama",
+			headers:    map[string]string{"x-tenant": "acme"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pmf := backendmetrics.NewPodMetricsFactory(&backendmetrics.FakePodMetricsClient{}, time.Second)
+			ds := NewDatastore(t.Context(), pmf)
+			for _, objective := range test.objectives {
+				ds.ObjectiveSet(objective)
+			}
+			if diff := cmp.Diff(test.want, ds.ObjectiveMatch(test.model, test.headers)); diff != "" {
+				t.Errorf("Unexpected objective (-want +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestObjectiveUsage(t *testing.T) {
+	pmf := backendmetrics.NewPodMetricsFactory(&backendmetrics.FakePodMetricsClient{}, time.Second)
+	ds := NewDatastore(t.Context(), pmf)
+	objective := testutil.MakeInferenceObjective("objective").ObjRef()
+	ds.ObjectiveSet(objective)
+
+	if diff := cmp.Diff(v1alpha2.ObjectiveUsage{}, ds.ObjectiveUsageGet(objective.Name)); diff != "" {
+		t.Errorf("Unexpected usage before any request (-want +got): %s", diff)
+	}
+
+	ds.ObjectiveRecordRequest(objective.Name, false)
+	ds.ObjectiveRecordRequest(objective.Name, true)
+	ds.ObjectiveRecordRequest(objective.Name, false)
+	usage := ds.ObjectiveUsageGet(objective.Name)
+	if usage.Requests != 3 || usage.RejectedRequests != 1 || usage.LastRequestTime == nil {
+		t.Errorf("Unexpected usage %+v, want 3 requests, 1 rejected and the time of the last request", usage)
+	}
+
+	ds.ObjectiveDelete(types.NamespacedName{Namespace: objective.Namespace, Name: objective.Name})
+	if diff := cmp.Diff(v1alpha2.ObjectiveUsage{}, ds.ObjectiveUsageGet(objective.Name)); diff != "" {
+		t.Errorf("Unexpected usage after the objective was deleted (-want +got): %s", diff)
+	}
+}
+
 func TestObjective(t *testing.T) {
 	chatModel := "chat"
 	tsModel := "food-review"
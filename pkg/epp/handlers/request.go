@@ -26,6 +26,8 @@ import (
 	"google.golang.org/protobuf/types/known/structpb"
 
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/metadata"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/metrics"
+	schedulingtypes "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
 	errutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/error"
 )
 
@@ -73,6 +75,9 @@ func (s *StreamingServer) HandleRequestHeaders(reqCtx *RequestContext, req *extP
 			// remove the objective header from the request headers,
 			// this is not data that should be manipulated or sent to the backend.
 			delete(reqCtx.Request.Headers, header.Key)
+		case metadata.DestinationEndpointKey:
+			// the destination endpoint is set by the EPP, a value set by the client is not trusted.
+			delete(reqCtx.Request.Headers, header.Key)
 		case metadata.ModelNameRewriteKey:
 			reqCtx.TargetModelName = reqCtx.Request.Headers[header.Key]
 			// remove the rewrite header from the request headers,
@@ -114,7 +119,7 @@ func (s *StreamingServer) generateRequestHeaderResponse(reqCtx *RequestContext)
 				},
 			},
 		},
-		DynamicMetadata: s.generateMetadata(reqCtx.TargetEndpoint),
+		DynamicMetadata: s.generateMetadata(reqCtx),
 	}
 }
 
@@ -151,22 +156,50 @@ func (s *StreamingServer) generateHeaders(reqCtx *RequestContext) []*configPb.He
 	return headers
 }
 
-func (s *StreamingServer) generateMetadata(endpoint string) *structpb.Struct {
+func (s *StreamingServer) generateMetadata(reqCtx *RequestContext) *structpb.Struct {
+	endpointFields := map[string]*structpb.Value{
+		metadata.DestinationEndpointKey: {
+			Kind: &structpb.Value_StringValue{
+				StringValue: reqCtx.TargetEndpoint,
+			},
+		},
+	}
+	if reqCtx.FallbackEndpoint != "" {
+		endpointFields[metadata.DestinationEndpointFallbackKey] = &structpb.Value{
+			Kind: &structpb.Value_StringValue{
+				StringValue: reqCtx.FallbackEndpoint,
+			},
+		}
+	}
 	return &structpb.Struct{
 		Fields: map[string]*structpb.Value{
 			metadata.DestinationEndpointNamespace: {
 				Kind: &structpb.Value_StructValue{
 					StructValue: &structpb.Struct{
-						Fields: map[string]*structpb.Value{
-							metadata.DestinationEndpointKey: {
-								Kind: &structpb.Value_StringValue{
-									StringValue: endpoint,
-								},
-							},
-						},
+						Fields: endpointFields,
 					},
 				},
 			},
 		},
 	}
 }
+
+// recordRequestMediaSizes records the total size of the inline media of every media type in the request.
+func recordRequestMediaSizes(reqCtx *RequestContext) {
+	if reqCtx.SchedulingRequest == nil || reqCtx.SchedulingRequest.Data == nil {
+		return
+	}
+	var media []schedulingtypes.MediaRef
+	if chat := reqCtx.SchedulingRequest.Data.ChatCompletions; chat != nil {
+		media = chat.Media()
+	} else if responses := reqCtx.SchedulingRequest.Data.Responses; responses != nil {
+		media = responses.Media()
+	}
+	sizes := map[string]int{}
+	for _, media := range media {
+		sizes[media.Type] += media.Size
+	}
+	for mediaType, size := range sizes {
+		metrics.RecordRequestMediaSizes(reqCtx.IncomingModelName, reqCtx.TargetModelName, mediaType, size)
+	}
+}
@@ -48,6 +48,10 @@ func TestHandleRequestHeaders(t *testing.T) {
 						Key:   metadata.FlowFairnessIDKey,
 						Value: "test-fairness-id-value",
 					},
+					{
+						Key:   metadata.DestinationEndpointKey,
+						Value: "10.0.0.1:8000",
+					},
 				},
 			},
 			EndOfStream: false,
@@ -65,4 +69,7 @@ func TestHandleRequestHeaders(t *testing.T) {
 	if reqCtx.Request.Headers[metadata.FlowFairnessIDKey] == "test-fairness-id-value" {
 		t.Errorf("expected fairness ID header to be removed from request headers, but it was not")
 	}
+	if _, found := reqCtx.Request.Headers[metadata.DestinationEndpointKey]; found {
+		t.Errorf("expected the destination endpoint header set by the client to be removed from request headers, but it was not")
+	}
 }
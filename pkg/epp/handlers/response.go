@@ -20,6 +20,7 @@ import (
 	"context"
 	"encoding/json"
 	"strings"
+	"time"
 
 	configPb "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	extProcPb "github.com/envoyproxy/go-control-plane/envoy/service/ext_proc/v3"
@@ -29,11 +30,6 @@ import (
 	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/logging"
 )
 
-const (
-	streamingRespPrefix = "data: "
-	streamingEndMsg     = "data: [DONE]"
-)
-
 // HandleResponseBody always returns the requestContext even in the error case, as the request context is used in error handling.
 func (s *StreamingServer) HandleResponseBody(ctx context.Context, reqCtx *RequestContext, response map[string]any) (*RequestContext, error) {
 	logger := log.FromContext(ctx)
@@ -60,20 +56,90 @@ func (s *StreamingServer) HandleResponseBody(ctx context.Context, reqCtx *Reques
 	// will add the processing for streaming case.
 	reqCtx.ResponseComplete = true
 
-	reqCtx.respBodyResp = generateResponseBodyResponses(responseBytes, true)
+	reqCtx.respBodyResp = generateResponseBodyResponses(s.director.HandleResponseBody(ctx, reqCtx, responseBytes, false, true), true)
 	return reqCtx, nil
 }
 
-// The function is to handle streaming response if the modelServer is streaming.
-func (s *StreamingServer) HandleResponseBodyModelStreaming(ctx context.Context, reqCtx *RequestContext, responseText string) {
-	if strings.Contains(responseText, streamingEndMsg) {
-		resp := parseRespForUsage(ctx, responseText)
-		reqCtx.Usage = resp.Usage
-		metrics.RecordInputTokens(reqCtx.IncomingModelName, reqCtx.TargetModelName, resp.Usage.PromptTokens)
-		metrics.RecordOutputTokens(reqCtx.IncomingModelName, reqCtx.TargetModelName, resp.Usage.CompletionTokens)
+// handleStreamingChunk handles a chunk of a streamed response that arrived at the given time, if the model
+// server is streaming. The last chunk of the body is marked by endOfStream.
+// The server-sent events of the response are parsed across chunks, recording the time to first token and
+// the inter-token latency as the tokens arrive, and the usage once the stream is done.
+func handleStreamingChunk(ctx context.Context, reqCtx *RequestContext, chunk []byte, endOfStream bool, now time.Time) {
+	logger := log.FromContext(ctx)
+	if reqCtx.streamingResponse == nil {
+		reqCtx.streamingResponse = &streamingResponse{}
+	}
+	stream := reqCtx.streamingResponse
+
+	data := stream.parser.feed(chunk)
+	if endOfStream {
+		data = append(data, stream.parser.flush()...)
+	}
+	tokenEvents := 0
+	for _, event := range data {
+		if event == streamingDoneData {
+			stream.done = true
+			continue
+		}
+		var streamed streamChunk
+		if err := json.Unmarshal([]byte(event), &streamed); err != nil {
+			logger.V(logutil.DEBUG).Error(err, "unmarshaling streamed response event")
+			continue
+		}
+		if streamed.Usage != nil {
+			reqCtx.Usage = *streamed.Usage
+		}
+		if streamed.hasTokens() {
+			tokenEvents++
+		}
+	}
+	recordStreamingTokens(reqCtx, tokenEvents, now)
+
+	if stream.done && !stream.usageRecorded {
+		stream.usageRecorded = true
+		metrics.RecordInputTokens(reqCtx.IncomingModelName, reqCtx.TargetModelName, reqCtx.Usage.PromptTokens)
+		metrics.RecordOutputTokens(reqCtx.IncomingModelName, reqCtx.TargetModelName, reqCtx.Usage.CompletionTokens)
 	}
 }
 
+// recordStreamingTokens records the time to first token and the inter-token latency of the token events
+// of a chunk. Events that arrive in the same chunk were batched on the way, so the time since the
+// previous token is divided evenly between them.
+func recordStreamingTokens(reqCtx *RequestContext, tokenEvents int, now time.Time) {
+	if tokenEvents == 0 {
+		return
+	}
+	stream := reqCtx.streamingResponse
+	targetPod := ""
+	if reqCtx.TargetPod != nil {
+		targetPod = reqCtx.TargetPod.NamespacedName.String()
+	}
+
+	stream.tokenEvents += tokenEvents
+	if stream.firstTokenTime.IsZero() {
+		stream.firstTokenTime, stream.lastTokenTime = now, now
+		if !reqCtx.RequestReceivedTimestamp.IsZero() {
+			metrics.RecordTimeToFirstToken(reqCtx.IncomingModelName, reqCtx.TargetModelName, targetPod, now.Sub(reqCtx.RequestReceivedTimestamp))
+		}
+		return // the other events of the first chunk arrived together with the first token
+	}
+
+	itl := now.Sub(stream.lastTokenTime) / time.Duration(tokenEvents)
+	for range tokenEvents {
+		metrics.RecordInterTokenLatency(reqCtx.IncomingModelName, reqCtx.TargetModelName, targetPod, itl)
+	}
+	stream.lastTokenTime = now
+}
+
+// outputTokens returns the number of output tokens of a response, from its usage, or for a streamed
+// response without usage, the number of streamed events that carried tokens.
+func outputTokens(reqCtx *RequestContext) int {
+	if reqCtx.Usage.CompletionTokens > 0 || reqCtx.streamingResponse == nil {
+		return reqCtx.Usage.CompletionTokens
+	}
+	return reqCtx.streamingResponse.tokenEvents
+}
+
 func (s *StreamingServer) HandleResponseHeaders(ctx context.Context, reqCtx *RequestContext, resp *extProcPb.ProcessingRequest_ResponseHeaders) (*RequestContext, error) {
 	for _, header := range resp.ResponseHeaders.Headers.Headers {
 		if header.RawValue != nil {
@@ -83,8 +149,19 @@ func (s *StreamingServer) HandleResponseHeaders(ctx context.Context, reqCtx *Req
 		}
 	}
 
+	received := make([]string, 0, len(reqCtx.Response.Headers))
+	for key := range reqCtx.Response.Headers {
+		received = append(received, key)
+	}
+
 	reqCtx, err := s.director.HandleResponse(ctx, reqCtx)
 
+	// The headers removed by the director, e.g. by response mutators, are removed from the response.
+	for _, key := range received {
+		if _, found := reqCtx.Response.Headers[key]; !found && !strings.HasPrefix(key, ":") {
+			reqCtx.removedResponseHeaders = append(reqCtx.removedResponseHeaders, key)
+		}
+	}
 	return reqCtx, err
 }
 
@@ -94,7 +171,8 @@ func (s *StreamingServer) generateResponseHeaderResponse(reqCtx *RequestContext)
 			ResponseHeaders: &extProcPb.HeadersResponse{
 				Response: &extProcPb.CommonResponse{
 					HeaderMutation: &extProcPb.HeaderMutation{
-						SetHeaders: s.generateResponseHeaders(reqCtx),
+						SetHeaders:    s.generateResponseHeaders(reqCtx),
+						RemoveHeaders: reqCtx.removedResponseHeaders,
 					},
 				},
 			},
@@ -142,41 +220,6 @@ func (s *StreamingServer) generateResponseHeaders(reqCtx *RequestContext) []*con
 	return headers
 }
 
-// Example message if "stream_options": {"include_usage": "true"} is included in the request:
-// data: {"id":"...","object":"text_completion","created":1739400043,"model":"food-review-0","choices":[],
-// "usage":{"prompt_tokens":7,"total_tokens":17,"completion_tokens":10}}
-//
-// data: [DONE]
-//
-// Noticed that vLLM returns two entries in one response.
-// We need to strip the `data:` prefix and next Data: [DONE] from the message to fetch response data.
-//
-// If include_usage is not included in the request, `data: [DONE]` is returned separately, which
-// indicates end of streaming.
-func parseRespForUsage(ctx context.Context, responseText string) ResponseBody {
-	response := ResponseBody{}
-	logger := log.FromContext(ctx)
-
-	lines := strings.Split(responseText, "\n")
-	for _, line := range lines {
-		if !strings.HasPrefix(line, streamingRespPrefix) {
-			continue
-		}
-		content := strings.TrimPrefix(line, streamingRespPrefix)
-		if content == "[DONE]" {
-			continue
-		}
-
-		byteSlice := []byte(content)
-		if err := json.Unmarshal(byteSlice, &response); err != nil {
-			logger.Error(err, "unmarshaling response body")
-			continue
-		}
-	}
-
-	return response
-}
-
 type ResponseBody struct {
 	Usage Usage `json:"usage"`
 }
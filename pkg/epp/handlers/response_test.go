@@ -20,9 +20,13 @@ import (
 	"context"
 	"encoding/json"
 	"testing"
+	"time"
 
+	configPb "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	extProcPb "github.com/envoyproxy/go-control-plane/envoy/service/ext_proc/v3"
 	"github.com/google/go-cmp/cmp"
 
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend"
 	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/logging"
 )
 
@@ -82,7 +86,7 @@ func TestHandleResponseBody(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			server := &StreamingServer{}
+			server := &StreamingServer{director: &testDirector{}}
 			reqCtx := test.reqCtx
 			if reqCtx == nil {
 				reqCtx = &RequestContext{}
@@ -142,12 +146,11 @@ func TestHandleStreamedResponseBody(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			server := &StreamingServer{}
 			reqCtx := test.reqCtx
 			if reqCtx == nil {
 				reqCtx = &RequestContext{}
 			}
-			server.HandleResponseBodyModelStreaming(ctx, reqCtx, test.body)
+			handleStreamingChunk(ctx, reqCtx, []byte(test.body), false, time.Now())
 
 			if diff := cmp.Diff(test.want, reqCtx.Usage); diff != "" {
 				t.Errorf("HandleResponseBody returned unexpected response, diff(-want, +got): %v", diff)
@@ -155,3 +158,59 @@ func TestHandleStreamedResponseBody(t *testing.T) {
 		})
 	}
 }
+
+func TestHandleResponseHeadersRemovedHeaders(t *testing.T) {
+	ctx := logutil.NewTestLoggerIntoContext(context.Background())
+	server := &StreamingServer{director: &testDirector{removedHeaders: []string{"content-length", ":status"}}}
+	reqCtx := &RequestContext{Response: &Response{Headers: map[string]string{}}}
+
+	reqCtx, err := server.HandleResponseHeaders(ctx, reqCtx, &extProcPb.ProcessingRequest_ResponseHeaders{
+		ResponseHeaders: &extProcPb.HttpHeaders{Headers: &configPb.HeaderMap{Headers: []*configPb.HeaderValue{
+			{Key: ":status", RawValue: []byte("200")},
+			{Key: "content-length", RawValue: []byte("42")},
+			{Key: "content-type", RawValue: []byte("application/json")},
+		}}},
+	})
+	if err != nil {
+		t.Fatalf("HandleResponseHeaders returned unexpected error: %v", err)
+	}
+
+	// Pseudo headers can't be removed.
+	mutation := server.generateResponseHeaderResponse(reqCtx).GetResponseHeaders().GetResponse().GetHeaderMutation()
+	if diff := cmp.Diff([]string{"content-length"}, mutation.GetRemoveHeaders()); diff != "" {
+		t.Errorf("Unexpected removed headers (-want +got): %v", diff)
+	}
+}
+
+type testDirector struct {
+	completed int
+	cancelled bool
+	// requestErr is returned by HandleRequest.
+	requestErr error
+	// removedHeaders are removed from the response headers by HandleResponse.
+	removedHeaders []string
+}
+
+func (d *testDirector) HandleRequest(_ context.Context, reqCtx *RequestContext) (*RequestContext, error) {
+	return reqCtx, d.requestErr
+}
+
+func (d *testDirector) HandleResponse(_ context.Context, reqCtx *RequestContext) (*RequestContext, error) {
+	for _, key := range d.removedHeaders {
+		delete(reqCtx.Response.Headers, key)
+	}
+	return reqCtx, nil
+}
+
+func (d *testDirector) HandleResponseBody(_ context.Context, _ *RequestContext, body []byte, _ bool, _ bool) []byte {
+	return body
+}
+
+func (d *testDirector) HandleResponseComplete(_ context.Context, reqCtx *RequestContext, _ []byte, cancelled bool) {
+	d.completed++
+	d.cancelled = cancelled
+}
+
+func (d *testDirector) GetRandomPod() *backend.Pod {
+	return nil
+}
@@ -19,10 +19,13 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"io"
+	"strconv"
 	"strings"
 	"time"
 
+	configPb "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	extProcPb "github.com/envoyproxy/go-control-plane/envoy/service/ext_proc/v3"
 	envoyTypePb "github.com/envoyproxy/go-control-plane/envoy/type/v3"
 	"github.com/go-logr/logr"
@@ -32,6 +35,7 @@ import (
 
 	v1 "sigs.k8s.io/gateway-api-inference-extension/api/v1"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/metadata"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/metrics"
 	schedulingtypes "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
 	errutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/error"
@@ -54,6 +58,8 @@ func NewStreamingServer(datastore Datastore, director Director) *StreamingServer
 type Director interface {
 	HandleRequest(ctx context.Context, reqCtx *RequestContext) (*RequestContext, error)
 	HandleResponse(ctx context.Context, reqCtx *RequestContext) (*RequestContext, error)
+	HandleResponseBody(ctx context.Context, reqCtx *RequestContext, body []byte, isStreaming bool, endOfStream bool) []byte
+	HandleResponseComplete(ctx context.Context, reqCtx *RequestContext, body []byte, cancelled bool)
 	GetRandomPod() *backend.Pod
 }
 
@@ -75,6 +81,7 @@ type StreamingServer struct {
 type RequestContext struct {
 	TargetPod                 *backend.Pod
 	TargetEndpoint            string
+	FallbackEndpoint          string
 	IncomingModelName         string
 	TargetModelName           string
 	FairnessID                string
@@ -88,13 +95,27 @@ type RequestContext struct {
 	ResponseStatusCode        string
 	RequestRunning            bool
 	Request                   *Request
+	// StreamUsageInjected is set if the usage of the streamed response was requested on behalf of the client,
+	// in which case the usage chunk is stripped from the response the client receives.
+	StreamUsageInjected bool
+	// ObjectiveConcurrencySlot is set if the request holds a slot of the concurrency budget of its objective,
+	// which is released when the response is complete.
+	ObjectiveConcurrencySlot bool
+	// RequestHandled is set once the director handled the request, after which the director is notified that
+	// the response is complete, even if the request is never forwarded to the model server.
+	RequestHandled bool
 
 	SchedulingRequest *schedulingtypes.LLMRequest
 
 	RequestState         StreamRequestState
 	modelServerStreaming bool
+	streamingResponse    *streamingResponse
+	// responseCompleteHandled is set once the director was notified that the response is complete.
+	responseCompleteHandled bool
 
 	Response *Response
+	// removedResponseHeaders are the headers of the response removed by the director.
+	removedResponseHeaders []string
 
 	reqHeaderResp  *extProcPb.ProcessingResponse
 	reqBodyResp    []*extProcPb.ProcessingResponse
@@ -105,6 +126,20 @@ type RequestContext struct {
 	respTrailerResp *extProcPb.ProcessingResponse
 }
 
+// ModelServerStreaming returns true if the model server is streaming the response.
+func (r *RequestContext) ModelServerStreaming() bool {
+	return r.modelServerStreaming
+}
+
+// FirstTokenTimestamp returns the time at which the first generated token of a streamed response was
+// received, or zero if none was received.
+func (r *RequestContext) FirstTokenTimestamp() time.Time {
+	if r.streamingResponse == nil {
+		return time.Time{}
+	}
+	return r.streamingResponse.firstTokenTime
+}
+
 type Request struct {
 	Headers  map[string]string
 	Body     map[string]any
@@ -162,6 +197,11 @@ func (s *StreamingServer) Process(srv extProcPb.ExternalProcessor_ProcessServer)
 		if reqCtx.RequestRunning {
 			metrics.DecRunningRequests(reqCtx.IncomingModelName)
 		}
+		if reqCtx.RequestHandled {
+			// The stream ended before the response was complete, e.g. because the client disconnected or the
+			// request could not be forwarded.
+			s.completeResponse(context.WithoutCancel(ctx), reqCtx, nil, true)
+		}
 	}(err, reqCtx)
 
 	for {
@@ -218,6 +258,7 @@ func (s *StreamingServer) Process(srv extProcPb.ExternalProcessor_ProcessServer)
 					logger.V(logutil.DEFAULT).Error(err, "Error handling request")
 					break
 				}
+				reqCtx.RequestHandled = true
 
 				// Populate the ExtProc protocol responses for the request body.
 				requestBodyBytes, err := json.Marshal(reqCtx.Request.Body)
@@ -231,6 +272,7 @@ func (s *StreamingServer) Process(srv extProcPb.ExternalProcessor_ProcessServer)
 
 				metrics.RecordRequestCounter(reqCtx.IncomingModelName, reqCtx.TargetModelName)
 				metrics.RecordRequestSizes(reqCtx.IncomingModelName, reqCtx.TargetModelName, reqCtx.RequestSize)
+				recordRequestMediaSizes(reqCtx)
 			}
 		case *extProcPb.ProcessingRequest_RequestTrailers:
 			// This is currently unused.
@@ -263,17 +305,28 @@ func (s *StreamingServer) Process(srv extProcPb.ExternalProcessor_ProcessServer)
 			if reqCtx.modelServerStreaming {
 				// Currently we punt on response parsing if the modelServer is streaming, and we just passthrough.
 
-				responseText := string(v.ResponseBody.Body)
-				s.HandleResponseBodyModelStreaming(ctx, reqCtx, responseText)
+				handleStreamingChunk(ctx, reqCtx, v.ResponseBody.Body, v.ResponseBody.EndOfStream, time.Now())
 				if v.ResponseBody.EndOfStream {
 					loggerTrace.Info("stream completed")
 
+					reqCtx.ResponseComplete = true
 					reqCtx.ResponseCompleteTimestamp = time.Now()
 					metrics.RecordRequestLatencies(ctx, reqCtx.IncomingModelName, reqCtx.TargetModelName, reqCtx.RequestReceivedTimestamp, reqCtx.ResponseCompleteTimestamp)
 					metrics.RecordResponseSizes(reqCtx.IncomingModelName, reqCtx.TargetModelName, reqCtx.ResponseSize)
+					if tokens := outputTokens(reqCtx); tokens > 0 {
+						metrics.RecordNormalizedTimePerOutputToken(ctx, reqCtx.IncomingModelName, reqCtx.TargetModelName, reqCtx.RequestReceivedTimestamp, reqCtx.ResponseCompleteTimestamp, tokens)
+					}
 				}
 
-				reqCtx.respBodyResp = generateResponseBodyResponses(v.ResponseBody.Body, v.ResponseBody.EndOfStream)
+				responseBody := v.ResponseBody.Body
+				if reqCtx.StreamUsageInjected {
+					responseBody = reqCtx.streamingResponse.usageStripper.strip(responseBody, v.ResponseBody.EndOfStream)
+				}
+				responseBody = s.director.HandleResponseBody(ctx, reqCtx, responseBody, true, v.ResponseBody.EndOfStream)
+				reqCtx.respBodyResp = generateResponseBodyResponses(responseBody, v.ResponseBody.EndOfStream)
+				if v.ResponseBody.EndOfStream {
+					s.completeResponse(ctx, reqCtx, nil, false)
+				}
 			} else {
 				body = append(body, v.ResponseBody.Body...)
 
@@ -291,7 +344,8 @@ func (s *StreamingServer) Process(srv extProcPb.ExternalProcessor_ProcessServer)
 						} else {
 							logger.V(logutil.DEFAULT).Error(responseErr, "Error unmarshalling request body", "body", string(body))
 						}
-						reqCtx.respBodyResp = generateResponseBodyResponses(body, true)
+						reqCtx.respBodyResp = generateResponseBodyResponses(s.director.HandleResponseBody(ctx, reqCtx, body, false, true), true)
+						s.completeResponse(ctx, reqCtx, body, false)
 						break
 					}
 
@@ -308,7 +362,11 @@ func (s *StreamingServer) Process(srv extProcPb.ExternalProcessor_ProcessServer)
 						metrics.RecordResponseSizes(reqCtx.IncomingModelName, reqCtx.TargetModelName, reqCtx.ResponseSize)
 						metrics.RecordInputTokens(reqCtx.IncomingModelName, reqCtx.TargetModelName, reqCtx.Usage.PromptTokens)
 						metrics.RecordOutputTokens(reqCtx.IncomingModelName, reqCtx.TargetModelName, reqCtx.Usage.CompletionTokens)
+						if tokens := outputTokens(reqCtx); tokens > 0 {
+							metrics.RecordNormalizedTimePerOutputToken(ctx, reqCtx.IncomingModelName, reqCtx.TargetModelName, reqCtx.RequestReceivedTimestamp, reqCtx.ResponseCompleteTimestamp, tokens)
+						}
 					}
+					s.completeResponse(ctx, reqCtx, body, false)
 				}
 			}
 		case *extProcPb.ProcessingRequest_ResponseTrailers:
@@ -339,6 +397,19 @@ func (s *StreamingServer) Process(srv extProcPb.ExternalProcessor_ProcessServer)
 	}
 }
 
+// completeResponse notifies the director, once per request, that the response is complete or that the request
+// was cancelled before its response was complete.
+func (s *StreamingServer) completeResponse(ctx context.Context, reqCtx *RequestContext, body []byte, cancelled bool) {
+	if reqCtx.responseCompleteHandled {
+		return
+	}
+	reqCtx.responseCompleteHandled = true
+	if reqCtx.ResponseCompleteTimestamp.IsZero() {
+		reqCtx.ResponseCompleteTimestamp = time.Now()
+	}
+	s.director.HandleResponseComplete(ctx, reqCtx, body, cancelled)
+}
+
 // updateStateAndSendIfNeeded checks state and can send mutiple responses in a single pass, but only if ordered properly.
 // Order of requests matter in FULL_DUPLEX_STREAMING. For both request and response, the order of response sent back MUST be: Header->Body->Trailer, with trailer being optional.
 func (r *RequestContext) updateStateAndSendIfNeeded(srv extProcPb.ExternalProcessor_ProcessServer, logger logr.Logger) error {
@@ -403,74 +474,83 @@ func (r *RequestContext) updateStateAndSendIfNeeded(srv extProcPb.ExternalProces
 	return nil
 }
 
-func buildErrResponse(err error) (*extProcPb.ProcessingResponse, error) {
-	var resp *extProcPb.ProcessingResponse
-
-	switch errutil.CanonicalCode(err) {
-	// This code can be returned by scheduler when there is no capacity for sheddable
-	// requests.
-	case errutil.InferencePoolResourceExhausted:
-		resp = &extProcPb.ProcessingResponse{
-			Response: &extProcPb.ProcessingResponse_ImmediateResponse{
-				ImmediateResponse: &extProcPb.ImmediateResponse{
-					Status: &envoyTypePb.HttpStatus{
-						Code: envoyTypePb.StatusCode_TooManyRequests,
-					},
-				},
-			},
-		}
-	// This code can be returned by when EPP processes the request and run into server-side errors.
-	case errutil.Internal:
-		resp = &extProcPb.ProcessingResponse{
-			Response: &extProcPb.ProcessingResponse_ImmediateResponse{
-				ImmediateResponse: &extProcPb.ImmediateResponse{
-					Status: &envoyTypePb.HttpStatus{
-						Code: envoyTypePb.StatusCode_InternalServerError,
-					},
-				},
-			},
-		}
-	// This code can be returned by the director when there are no candidate pods for the request scheduling.
-	case errutil.ServiceUnavailable:
-		resp = &extProcPb.ProcessingResponse{
-			Response: &extProcPb.ProcessingResponse_ImmediateResponse{
-				ImmediateResponse: &extProcPb.ImmediateResponse{
-					Status: &envoyTypePb.HttpStatus{
-						Code: envoyTypePb.StatusCode_ServiceUnavailable,
-					},
-				},
-			},
-		}
+// errorResponse describes how an errutil.Error code is returned to the client.
+type errorResponse struct {
+	status envoyTypePb.StatusCode
+	// errorType is the type of the error in the OpenAI compatible error body.
+	errorType string
+}
+
+var errorResponses = map[string]errorResponse{
 	// This code can be returned when users provide invalid json request.
-	case errutil.BadRequest:
-		resp = &extProcPb.ProcessingResponse{
-			Response: &extProcPb.ProcessingResponse_ImmediateResponse{
-				ImmediateResponse: &extProcPb.ImmediateResponse{
-					Status: &envoyTypePb.HttpStatus{
-						Code: envoyTypePb.StatusCode_BadRequest,
-					},
-				},
-			},
-		}
-	case errutil.BadConfiguration:
-		resp = &extProcPb.ProcessingResponse{
-			Response: &extProcPb.ProcessingResponse_ImmediateResponse{
-				ImmediateResponse: &extProcPb.ImmediateResponse{
-					Status: &envoyTypePb.HttpStatus{
-						Code: envoyTypePb.StatusCode_NotFound,
-					},
-				},
-			},
-		}
-	default:
+	errutil.BadRequest: {status: envoyTypePb.StatusCode_BadRequest, errorType: "invalid_request_error"},
+	// This code can be returned by the director when none of the endpoints serves the requested model.
+	errutil.ModelNotFound: {status: envoyTypePb.StatusCode_NotFound, errorType: "invalid_request_error"},
+	// This code can be returned by scheduler when there is no capacity for sheddable requests.
+	errutil.InferencePoolResourceExhausted: {status: envoyTypePb.StatusCode_TooManyRequests, errorType: "rate_limit_error"},
+	// This code can be returned by the director when there are no candidate pods for the request scheduling.
+	errutil.ServiceUnavailable: {status: envoyTypePb.StatusCode_ServiceUnavailable, errorType: "service_unavailable_error"},
+	// This code can be returned by when EPP processes the request and run into server-side errors.
+	errutil.Internal: {status: envoyTypePb.StatusCode_InternalServerError, errorType: "server_error"},
+	// This code can be returned when the EPP or the InferencePool is misconfigured.
+	errutil.BadConfiguration: {status: envoyTypePb.StatusCode_NotFound, errorType: "invalid_request_error"},
+}
+
+// defaultRetryAfter is the Retry-After of 429 responses, when the error has no estimate of its own.
+const defaultRetryAfter = time.Second
+
+// errorBody is an OpenAI compatible error response body.
+type errorBody struct {
+	Error struct {
+		Message string `json:"message"`
+		Type    string `json:"type"`
+		Code    string `json:"code"`
+	} `json:"error"`
+}
+
+// buildErrResponse returns the immediate response for an error, with an OpenAI compatible error body and the
+// EPP error code header. 429 responses also have a Retry-After header.
+func buildErrResponse(err error) (*extProcPb.ProcessingResponse, error) {
+	var e errutil.Error
+	if !errors.As(err, &e) {
+		e = errutil.Error{Code: errutil.Unknown}
+	}
+	errResp, ok := errorResponses[e.Code]
+	if !ok {
 		return nil, status.Errorf(status.Code(err), "failed to handle request: %v", err)
 	}
 
-	if err.Error() != "" {
-		resp.Response.(*extProcPb.ProcessingResponse_ImmediateResponse).ImmediateResponse.Body = []byte(err.Error())
+	var body errorBody
+	body.Error.Message = e.Msg
+	body.Error.Type = errResp.errorType
+	body.Error.Code = e.Code
+	bodyBytes, _ := json.Marshal(body) // the body only holds strings
+
+	headers := []*configPb.HeaderValueOption{
+		{Header: &configPb.HeaderValue{Key: "content-type", RawValue: []byte("application/json")}},
+		{Header: &configPb.HeaderValue{Key: metadata.ErrorCodeKey, RawValue: []byte(e.Code)}},
+	}
+	if errResp.status == envoyTypePb.StatusCode_TooManyRequests {
+		retryAfter := e.RetryAfter
+		if retryAfter <= 0 {
+			retryAfter = defaultRetryAfter
+		}
+		// Retry-After is in whole seconds, round up so that clients don't retry too early.
+		seconds := int64((retryAfter + time.Second - 1) / time.Second)
+		headers = append(headers, &configPb.HeaderValueOption{
+			Header: &configPb.HeaderValue{Key: "retry-after", RawValue: []byte(strconv.FormatInt(seconds, 10))},
+		})
 	}
 
-	return resp, nil
+	return &extProcPb.ProcessingResponse{
+		Response: &extProcPb.ProcessingResponse_ImmediateResponse{
+			ImmediateResponse: &extProcPb.ImmediateResponse{
+				Status:  &envoyTypePb.HttpStatus{Code: errResp.status},
+				Headers: &extProcPb.HeaderMutation{SetHeaders: headers},
+				Body:    bodyBytes,
+			},
+		},
+	}, nil
 }
 
 func buildCommonResponses(bodyBytes []byte, byteLimit int, setEos bool) []*extProcPb.CommonResponse {
@@ -17,8 +17,22 @@ limitations under the License.
 package handlers
 
 import (
+	"context"
 	"crypto/rand"
+	"errors"
+	"io"
 	"testing"
+	"time"
+
+	configPb "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	extProcPb "github.com/envoyproxy/go-control-plane/envoy/service/ext_proc/v3"
+	envoyTypePb "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc"
+
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/metadata"
+	errutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/error"
+	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/logging"
 )
 
 func TestBuildCommonResponses(t *testing.T) {
@@ -78,6 +92,202 @@ func TestBuildCommonResponses(t *testing.T) {
 	}
 }
 
+func TestBuildErrResponse(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		wantStatus     envoyTypePb.StatusCode
+		wantBody       string
+		wantRetryAfter string
+		wantErr        bool
+	}{
+		{
+			name:       "bad request",
+			err:        errutil.Error{Code: errutil.BadRequest, Msg: "model not found in request body"},
+			wantStatus: envoyTypePb.StatusCode_BadRequest,
+			wantBody:   `{"error":{"message":"model not found in request body","type":"invalid_request_error","code":"BadRequest"}}`,
+		},
+		{
+			name:       "model not found",
+			err:        errutil.Error{Code: errutil.ModelNotFound, Msg: "model my-adapter is not served by any endpoint"},
+			wantStatus: envoyTypePb.StatusCode_NotFound,
+			wantBody:   `{"error":{"message":"model my-adapter is not served by any endpoint","type":"invalid_request_error","code":"ModelNotFound"}}`,
+		},
+		{
+			name:           "saturated with a retry estimate",
+			err:            errutil.Error{Code: errutil.InferencePoolResourceExhausted, Msg: "system saturated", RetryAfter: 2500 * time.Millisecond},
+			wantStatus:     envoyTypePb.StatusCode_TooManyRequests,
+			wantBody:       `{"error":{"message":"system saturated","type":"rate_limit_error","code":"InferencePoolResourceExhausted"}}`,
+			wantRetryAfter: "3",
+		},
+		{
+			name:           "saturated without a retry estimate",
+			err:            errutil.Error{Code: errutil.InferencePoolResourceExhausted, Msg: "no target pod"},
+			wantStatus:     envoyTypePb.StatusCode_TooManyRequests,
+			wantBody:       `{"error":{"message":"no target pod","type":"rate_limit_error","code":"InferencePoolResourceExhausted"}}`,
+			wantRetryAfter: "1",
+		},
+		{
+			name:       "bad configuration",
+			err:        errutil.Error{Code: errutil.BadConfiguration, Msg: "targetPorts should have length 1"},
+			wantStatus: envoyTypePb.StatusCode_NotFound,
+			wantBody:   `{"error":{"message":"targetPorts should have length 1","type":"invalid_request_error","code":"BadConfiguration"}}`,
+		},
+		{
+			name:    "unknown error",
+			err:     errors.New("unknown"),
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resp, err := buildErrResponse(test.err)
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("buildErrResponse() should have returned an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("buildErrResponse() returned unexpected error: %v", err)
+			}
+
+			immediate := resp.GetImmediateResponse()
+			if immediate.GetStatus().GetCode() != test.wantStatus {
+				t.Errorf("status = %v, want %v", immediate.GetStatus().GetCode(), test.wantStatus)
+			}
+			if diff := cmp.Diff(test.wantBody, string(immediate.GetBody())); diff != "" {
+				t.Errorf("unexpected body, diff(-want, +got): %v", diff)
+			}
+			headers := map[string]string{}
+			for _, header := range immediate.GetHeaders().GetSetHeaders() {
+				headers[header.GetHeader().GetKey()] = string(header.GetHeader().GetRawValue())
+			}
+			wantHeaders := map[string]string{
+				"content-type":        "application/json",
+				metadata.ErrorCodeKey: test.err.(errutil.Error).Code,
+			}
+			if test.wantRetryAfter != "" {
+				wantHeaders["retry-after"] = test.wantRetryAfter
+			}
+			if diff := cmp.Diff(wantHeaders, headers); diff != "" {
+				t.Errorf("unexpected headers, diff(-want, +got): %v", diff)
+			}
+		})
+	}
+}
+
+func TestProcessSendFailure(t *testing.T) {
+	director := &testDirector{}
+	server := &StreamingServer{director: director}
+	srv := &testProcessServer{
+		ctx:      logutil.NewTestLoggerIntoContext(context.Background()),
+		requests: completionsRequest(),
+		sendErr:  errors.New("stream closed"),
+	}
+
+	if err := server.Process(srv); err == nil {
+		t.Fatalf("Process() should have returned an error")
+	}
+	// The request was handled by the director, which gives back what it took for the request, such as the slot
+	// of the concurrency budget of its objective, when it is notified of the cancelled response.
+	if director.completed != 1 || !director.cancelled {
+		t.Errorf("director notified %d times with cancelled %t, want once with cancelled true", director.completed, director.cancelled)
+	}
+}
+
+func TestProcessResponseComplete(t *testing.T) {
+	tests := []struct {
+		name          string
+		requestErr    error
+		sendErr       error
+		sendLimit     int
+		wantCompleted int
+	}{
+		{
+			name:          "the request body response fails to be sent",
+			sendErr:       errors.New("stream closed"),
+			sendLimit:     1,
+			wantCompleted: 1,
+		},
+		{
+			name:          "the stream ends after the request is forwarded",
+			wantCompleted: 1,
+		},
+		{
+			name:       "the request is rejected by the director",
+			requestErr: errutil.Error{Code: errutil.BadRequest, Msg: "bad request"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			director := &testDirector{requestErr: test.requestErr}
+			server := &StreamingServer{director: director}
+			srv := &testProcessServer{
+				ctx:       logutil.NewTestLoggerIntoContext(context.Background()),
+				requests:  completionsRequest(),
+				sendErr:   test.sendErr,
+				sendLimit: test.sendLimit,
+			}
+
+			_ = server.Process(srv)
+			// The ResponseComplete plugins run exactly once per scheduled request, with the request cancelled when
+			// its response is never complete.
+			if director.completed != test.wantCompleted {
+				t.Errorf("director notified %d times, want %d", director.completed, test.wantCompleted)
+			}
+			if director.completed > 0 && !director.cancelled {
+				t.Errorf("director notified with cancelled false, want true")
+			}
+		})
+	}
+}
+
+// completionsRequest returns the ext-proc messages of the headers and body of a completions request.
+func completionsRequest() []*extProcPb.ProcessingRequest {
+	return []*extProcPb.ProcessingRequest{
+		{Request: &extProcPb.ProcessingRequest_RequestHeaders{RequestHeaders: &extProcPb.HttpHeaders{
+			Headers: &configPb.HeaderMap{Headers: []*configPb.HeaderValue{{Key: "content-type", RawValue: []byte("application/json")}}},
+		}}},
+		{Request: &extProcPb.ProcessingRequest_RequestBody{RequestBody: &extProcPb.HttpBody{
+			Body:        []byte(`{"model":"my-model","prompt":"hello"}`),
+			EndOfStream: true,
+		}}},
+	}
+}
+
+// testProcessServer is an ext-proc stream that receives the given requests, and fails to send responses with
+// sendErr once sendLimit responses were sent.
+type testProcessServer struct {
+	grpc.ServerStream
+	ctx       context.Context
+	requests  []*extProcPb.ProcessingRequest
+	sendErr   error
+	sendLimit int
+	sent      int
+}
+
+func (s *testProcessServer) Context() context.Context {
+	return s.ctx
+}
+
+func (s *testProcessServer) Recv() (*extProcPb.ProcessingRequest, error) {
+	if len(s.requests) == 0 {
+		return nil, io.EOF
+	}
+	req := s.requests[0]
+	s.requests = s.requests[1:]
+	return req, nil
+}
+
+func (s *testProcessServer) Send(*extProcPb.ProcessingResponse) error {
+	if s.sendErr != nil && s.sent >= s.sendLimit {
+		return s.sendErr
+	}
+	s.sent++
+	return nil
+}
+
 func generateBytes(count int) []byte {
 	arr := make([]byte, count)
 	_, _ = rand.Read(arr)
@@ -0,0 +1,183 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+const streamingDoneData = "[DONE]"
+
+// sseParser incrementally parses the server-sent events of a streamed response body. The body arrives in
+// chunks that are not aligned with the events, so a line that is split across chunks is buffered until
+// the rest of it arrives.
+// OpenAI compatible model servers send every event as a single `data:` line, so each data line is
+// handled as a complete event, while other fields (e.g. `event:`), comments and blank lines are ignored.
+type sseParser struct {
+	partialLine []byte
+}
+
+// feed parses the next chunk of the body, and returns the data of the data lines completed by it.
+func (p *sseParser) feed(chunk []byte) []string {
+	var data []string
+	for len(chunk) > 0 {
+		end := bytes.IndexByte(chunk, '\n')
+		if end < 0 {
+			p.partialLine = append(p.partialLine, chunk...)
+			break
+		}
+		line := chunk[:end]
+		if len(p.partialLine) > 0 {
+			line = append(p.partialLine, line...)
+			p.partialLine = p.partialLine[:0]
+		}
+		chunk = chunk[end+1:]
+		if value, ok := dataLineValue(line); ok {
+			data = append(data, value)
+		}
+	}
+	return data
+}
+
+// flush returns the data of the last line of the body, if it isn't terminated by a new line.
+func (p *sseParser) flush() []string {
+	line := p.partialLine
+	p.partialLine = nil
+	if value, ok := dataLineValue(line); ok {
+		return []string{value}
+	}
+	return nil
+}
+
+// dataLineValue returns the value of a data line.
+func dataLineValue(line []byte) (string, bool) {
+	line = bytes.TrimRight(line, "\r")
+	line = bytes.TrimLeft(line, " \t") // tolerate indented lines
+	value, ok := bytes.CutPrefix(line, []byte("data:"))
+	if !ok {
+		return "", false
+	}
+	return strings.TrimPrefix(string(value), " "), true
+}
+
+// streamChunk holds the fields parsed out of a streamed event of the completions, chat-completions and
+// responses APIs.
+type streamChunk struct {
+	Choices []struct {
+		// Text is set on completions chunks.
+		Text string `json:"text"`
+		// Delta is set on chat-completions chunks.
+		Delta struct {
+			Content          string `json:"content"`
+			ReasoningContent string `json:"reasoning_content"`
+			ToolCalls        []any  `json:"tool_calls"`
+		} `json:"delta"`
+	} `json:"choices"`
+	Usage *Usage `json:"usage"`
+	// Type and Delta are set on responses API events, e.g. `response.output_text.delta`.
+	Type  string `json:"type"`
+	Delta string `json:"delta"`
+}
+
+// hasTokens returns true if the chunk carries generated output.
+func (c *streamChunk) hasTokens() bool {
+	for _, choice := range c.Choices {
+		if choice.Text != "" || choice.Delta.Content != "" || choice.Delta.ReasoningContent != "" || len(choice.Delta.ToolCalls) > 0 {
+			return true
+		}
+	}
+	return strings.HasSuffix(c.Type, ".delta") && c.Delta != ""
+}
+
+// streamingResponse tracks a streamed response across the chunks of its body.
+type streamingResponse struct {
+	parser sseParser
+	// firstTokenTime and lastTokenTime are the arrival times of the first and of the last chunk carrying
+	// generated output.
+	firstTokenTime time.Time
+	lastTokenTime  time.Time
+	// tokenEvents is the number of events carrying generated output, which is the number of output tokens
+	// for model servers that stream one token per event.
+	tokenEvents int
+	// done is set once the `[DONE]` event was received.
+	done bool
+	// usageRecorded is set once the token usage of the response was recorded.
+	usageRecorded bool
+	// usageStripper removes the usage chunk from the response, if it was requested on behalf of the client.
+	usageStripper usageStripper
+}
+
+// usageStripper removes the usage chunk, which was requested on behalf of the client, from a streamed
+// response body. Complete lines are forwarded as they arrive, while a line that is split across chunks is
+// held back until the rest of it arrives.
+type usageStripper struct {
+	partialLine []byte
+	// skipBlankLine is set once a usage event was removed, to remove the blank line that terminates it.
+	skipBlankLine bool
+}
+
+// strip returns the next chunk of the body without the usage event.
+func (s *usageStripper) strip(chunk []byte, endOfStream bool) []byte {
+	stripped := make([]byte, 0, len(s.partialLine)+len(chunk))
+	for len(chunk) > 0 {
+		end := bytes.IndexByte(chunk, '\n')
+		if end < 0 {
+			s.partialLine = append(s.partialLine, chunk...)
+			break
+		}
+		line := chunk[:end+1]
+		if len(s.partialLine) > 0 {
+			line = append(s.partialLine, line...)
+			s.partialLine = s.partialLine[:0]
+		}
+		chunk = chunk[end+1:]
+		stripped = s.appendLine(stripped, line)
+	}
+	if endOfStream && len(s.partialLine) > 0 {
+		stripped = s.appendLine(stripped, s.partialLine)
+		s.partialLine = nil
+	}
+	return stripped
+}
+
+// appendLine appends a line of the body to the stripped body, unless it belongs to the usage event.
+func (s *usageStripper) appendLine(stripped []byte, line []byte) []byte {
+	if s.skipBlankLine {
+		s.skipBlankLine = false
+		if len(bytes.TrimSpace(line)) == 0 {
+			return stripped
+		}
+	}
+	if value, ok := dataLineValue(bytes.TrimRight(line, "\n")); ok && isUsageEvent(value) {
+		s.skipBlankLine = true
+		return stripped
+	}
+	return append(stripped, line...)
+}
+
+// isUsageEvent returns true if the data of an event is the usage chunk, which is sent after the last
+// choice with an empty list of choices.
+func isUsageEvent(data string) bool {
+	var streamed streamChunk
+	if err := json.Unmarshal([]byte(data), &streamed); err != nil {
+		return false
+	}
+	return streamed.Usage != nil && len(streamed.Choices) == 0
+}
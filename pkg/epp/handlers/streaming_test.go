@@ -0,0 +1,125 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handlers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/logging"
+)
+
+func TestSSEParser(t *testing.T) {
+	parser := &sseParser{}
+	var got []string
+	// Events split at arbitrary positions across chunks, with comments, event fields and CRLF line endings.
+	for _, chunk := range []string{
+		`: keep-alive` + "\n\n" + `data: {"a":`,
+		`1}` + "\r\n\r\n" + `event: message` + "\n" + `da`,
+		`ta: {"b":2}` + "\n\n" + `data: [DONE]`,
+	} {
+		got = append(got, parser.feed([]byte(chunk))...)
+	}
+	got = append(got, parser.flush()...)
+
+	want := []string{`{"a":1}`, `{"b":2}`, "[DONE]"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("sseParser returned unexpected data, diff(-want, +got): %v", diff)
+	}
+}
+
+func TestUsageStripper(t *testing.T) {
+	stripper := &usageStripper{}
+	chunks := []string{
+		`data: {"choices":[{"delta":{"content":"Hi"}}],"usage":null}` + "\n\n" + `data: {"choices":[],"us`,
+		`age":{"prompt_tokens":7,"total_tokens":8,"completion_tokens":1}}` + "\r\n\r\n" + `data: [DO`,
+		`NE]`,
+	}
+	var got string
+	for i, chunk := range chunks {
+		got += string(stripper.strip([]byte(chunk), i == len(chunks)-1))
+	}
+
+	want := `data: {"choices":[{"delta":{"content":"Hi"}}],"usage":null}` + "\n\n" + `data: [DONE]`
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("usageStripper returned unexpected body, diff(-want, +got): %v", diff)
+	}
+}
+
+func TestHandleStreamingChunks(t *testing.T) {
+	ctx := logutil.NewTestLoggerIntoContext(context.Background())
+	received := time.Now()
+	reqCtx := &RequestContext{modelServerStreaming: true, RequestReceivedTimestamp: received}
+
+	chunks := []string{
+		`data: {"choices":[{"delta":{"role":"assistant"}}]}` + "\n\n" + `data: {"choices":[{"delta":{"content":"Hel"}}]}` + "\n\n",
+		`data: {"choices":[{"delta":{"content":"lo"}}]}` + "\n\n" + `data: {"choices":[{"delta":{"con`,
+		`tent":" world"}}]}` + "\n\n",
+		`data: {"choices":[],"usage":{"prompt_tokens":7,"total_tokens":10,"completion_tokens":3}}` + "\n\n" + `data: [DONE]` + "\n\n",
+	}
+	for i, chunk := range chunks {
+		handleStreamingChunk(ctx, reqCtx, []byte(chunk), i == len(chunks)-1, received.Add(time.Duration(i+1)*100*time.Millisecond))
+	}
+
+	stream := reqCtx.streamingResponse
+	if got, want := stream.firstTokenTime, received.Add(100*time.Millisecond); !got.Equal(want) {
+		t.Errorf("first token time = %v, want %v", got, want)
+	}
+	if got, want := stream.lastTokenTime, received.Add(300*time.Millisecond); !got.Equal(want) {
+		t.Errorf("last token time = %v, want %v", got, want)
+	}
+	if stream.tokenEvents != 3 {
+		t.Errorf("token events = %d, want 3", stream.tokenEvents)
+	}
+	if !stream.done || !stream.usageRecorded {
+		t.Errorf("stream should be done and its usage recorded")
+	}
+	if diff := cmp.Diff(Usage{PromptTokens: 7, TotalTokens: 10, CompletionTokens: 3}, reqCtx.Usage); diff != "" {
+		t.Errorf("unexpected usage, diff(-want, +got): %v", diff)
+	}
+}
+
+func TestOutputTokens(t *testing.T) {
+	withUsage := &RequestContext{Usage: Usage{CompletionTokens: 5}, streamingResponse: &streamingResponse{tokenEvents: 3}}
+	if got := outputTokens(withUsage); got != 5 {
+		t.Errorf("outputTokens with usage = %d, want 5", got)
+	}
+	withoutUsage := &RequestContext{streamingResponse: &streamingResponse{tokenEvents: 3}}
+	if got := outputTokens(withoutUsage); got != 3 {
+		t.Errorf("outputTokens without usage = %d, want 3", got)
+	}
+}
+
+func TestCompleteResponse(t *testing.T) {
+	ctx := logutil.NewTestLoggerIntoContext(context.Background())
+	director := &testDirector{}
+	server := &StreamingServer{director: director}
+	reqCtx := &RequestContext{}
+
+	server.completeResponse(ctx, reqCtx, nil, true)
+	server.completeResponse(ctx, reqCtx, nil, false)
+
+	if director.completed != 1 || !director.cancelled {
+		t.Errorf("director notified %d times with cancelled %t, want once with cancelled true", director.completed, director.cancelled)
+	}
+	if reqCtx.ResponseCompleteTimestamp.IsZero() {
+		t.Errorf("response complete timestamp should be set")
+	}
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lora
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+const (
+	loadAdapterPath   = "/v1/load_lora_adapter"
+	unloadAdapterPath = "/v1/unload_lora_adapter"
+
+	// maxErrorResponseSize bounds the size of the error response body that is read.
+	maxErrorResponseSize = 4096
+)
+
+// AdapterClient loads and unloads LoRA adapters on a model server, given as its host:port address.
+type AdapterClient interface {
+	LoadAdapter(ctx context.Context, address string, adapter string, path string) error
+	UnloadAdapter(ctx context.Context, address string, adapter string) error
+}
+
+// httpAdapterClient is an AdapterClient for the vLLM dynamic LoRA adapter API.
+type httpAdapterClient struct {
+	scheme string
+	client *http.Client
+}
+
+// NewHTTPAdapterClient returns an AdapterClient that calls the vLLM load_lora_adapter and unload_lora_adapter
+// endpoints of the model servers.
+func NewHTTPAdapterClient(scheme string) AdapterClient {
+	return &httpAdapterClient{scheme: scheme, client: &http.Client{}}
+}
+
+type loadAdapterRequest struct {
+	LoraName string `json:"lora_name"`
+	LoraPath string `json:"lora_path,omitempty"`
+}
+
+// LoadAdapter loads the adapter from its path on the model server.
+func (c *httpAdapterClient) LoadAdapter(ctx context.Context, address string, adapter string, path string) error {
+	return c.post(ctx, address, loadAdapterPath, loadAdapterRequest{LoraName: adapter, LoraPath: path})
+}
+
+// UnloadAdapter unloads the adapter from the model server.
+func (c *httpAdapterClient) UnloadAdapter(ctx context.Context, address string, adapter string) error {
+	return c.post(ctx, address, unloadAdapterPath, loadAdapterRequest{LoraName: adapter})
+}
+
+func (c *httpAdapterClient) post(ctx context.Context, address string, path string, body loadAdapterRequest) error {
+	target := &url.URL{Scheme: c.scheme, Host: address, Path: path}
+	bodyBytes, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %v", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target.String(), bytes.NewReader(bodyBytes))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call %s of %s: %w", path, address, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorResponseSize))
+		return fmt.Errorf("unexpected status code from %s of %s: %v - %s", path, address, resp.StatusCode, message)
+	}
+	return nil
+}
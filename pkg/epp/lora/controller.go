@@ -0,0 +1,258 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lora
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/metrics"
+	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/logging"
+)
+
+const (
+	loadOperation   = "load"
+	unloadOperation = "unload"
+)
+
+// AdapterController tracks the LoRA adapters it loaded on the model servers, loads adapters on demand, and
+// unloads the least recently used adapters to make room for new ones, or when they are idle. Concurrent
+// requests for an adapter that is being loaded on a model server wait for the same load.
+type AdapterController struct {
+	client AdapterClient
+	now    func() time.Time
+
+	mu   sync.Mutex
+	pods map[string]*podAdapters // by the namespaced name of the pod
+}
+
+// podAdapters are the adapters tracked on a model server.
+type podAdapters struct {
+	address  string
+	adapters map[string]*adapterState // by the adapter name
+}
+
+// adapterState is the state of an adapter on a model server.
+type adapterState struct {
+	loading  chan struct{} // closed when the load completes, nil when the adapter is loaded
+	loadErr  error
+	loadedAt time.Time
+	lastUsed time.Time
+	inFlight int // the number of requests being served with the adapter
+}
+
+// PodAdapterRequest describes the adapter a request needs on a model server.
+type PodAdapterRequest struct {
+	// Pod is the namespaced name of the pod.
+	Pod string
+	// Address is the host:port address of the model server.
+	Address string
+	// Adapter is the name of the adapter.
+	Adapter string
+	// Path is the path the adapter is loaded from.
+	Path string
+	// Observed are the adapters observed on the model server, e.g. from its metrics.
+	Observed []string
+	// ObservedAt is the time Observed listed every adapter loaded on the model server, e.g. from its model list.
+	// A tracked adapter that is missing from a later complete list is loaded again, e.g. after the model server
+	// restarted. Zero if Observed may be partial.
+	ObservedAt time.Time
+	// MaxAdapters is the maximum number of adapters loaded on the model server. 0 is unlimited.
+	MaxAdapters int
+}
+
+// NewAdapterController returns a new AdapterController using the given client.
+func NewAdapterController(client AdapterClient) *AdapterController {
+	return &AdapterController{
+		client: client,
+		now:    time.Now,
+		pods:   map[string]*podAdapters{},
+	}
+}
+
+// Acquire ensures the adapter is loaded on the model server for a request, loading it if it is neither tracked
+// nor observed on the model server, and waiting for a load that is in progress. The least recently used adapters
+// without in-flight requests are unloaded first, if the model server has no room for the adapter. Every successful
+// Acquire must be followed by a Release when the request completes.
+func (c *AdapterController) Acquire(ctx context.Context, request PodAdapterRequest) error {
+	c.mu.Lock()
+	pod, found := c.pods[request.Pod]
+	if !found {
+		pod = &podAdapters{adapters: map[string]*adapterState{}}
+		c.pods[request.Pod] = pod
+	}
+	pod.address = request.Address
+
+	if state, found := pod.adapters[request.Adapter]; found && state.isGone(request) {
+		delete(pod.adapters, request.Adapter)
+	}
+	if state, found := pod.adapters[request.Adapter]; found {
+		state.lastUsed = c.now()
+		state.inFlight++
+		loading := state.loading
+		c.mu.Unlock()
+		if loading == nil {
+			return nil
+		}
+		select {
+		case <-loading:
+		case <-ctx.Done():
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			state.inFlight--
+			return ctx.Err()
+		}
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return state.loadErr // a failed load is no longer tracked
+	}
+
+	state := &adapterState{loadedAt: c.now(), lastUsed: c.now(), inFlight: 1}
+	pod.adapters[request.Adapter] = state
+	if slices.Contains(request.Observed, request.Adapter) {
+		c.mu.Unlock()
+		return nil
+	}
+	state.loading = make(chan struct{})
+	victims := pod.leastRecentlyUsed(request)
+	c.mu.Unlock()
+
+	logger := log.FromContext(ctx)
+	for _, victim := range victims {
+		c.unload(ctx, request.Pod, request.Address, victim)
+	}
+	err := c.client.LoadAdapter(ctx, request.Address, request.Adapter, request.Path)
+	metrics.RecordLoraAdapterOperation(request.Adapter, loadOperation, err == nil)
+	if err != nil {
+		logger.Error(err, "Failed to load LoRA adapter", "pod", request.Pod, "adapter", request.Adapter)
+	} else {
+		logger.V(logutil.DEFAULT).Info("Loaded LoRA adapter", "pod", request.Pod, "adapter", request.Adapter)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	state.loadErr = err
+	state.loadedAt = c.now()
+	close(state.loading)
+	state.loading = nil
+	if err != nil && pod.adapters[request.Adapter] == state {
+		delete(pod.adapters, request.Adapter) // the next request retries the load
+	}
+	return err
+}
+
+// Release marks the end of a request that acquired the adapter on the model server.
+func (c *AdapterController) Release(pod string, adapter string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if state, found := c.pods[pod].getAdapter(adapter); found {
+		state.lastUsed = c.now()
+		state.inFlight = max(state.inFlight-1, 0)
+	}
+}
+
+// UnloadIdle unloads the adapters without in-flight requests that were not used for the idle timeout.
+func (c *AdapterController) UnloadIdle(ctx context.Context, idleTimeout time.Duration) {
+	type idleAdapter struct{ pod, address, adapter string }
+	var idle []idleAdapter
+
+	c.mu.Lock()
+	now := c.now()
+	for name, pod := range c.pods {
+		for adapter, state := range pod.adapters {
+			if state.loading == nil && state.inFlight == 0 && now.Sub(state.lastUsed) >= idleTimeout {
+				idle = append(idle, idleAdapter{pod: name, address: pod.address, adapter: adapter})
+				delete(pod.adapters, adapter)
+			}
+		}
+		if len(pod.adapters) == 0 {
+			delete(c.pods, name)
+		}
+	}
+	c.mu.Unlock()
+
+	for _, adapter := range idle {
+		c.unload(ctx, adapter.pod, adapter.address, adapter.adapter)
+	}
+}
+
+// unload unloads an adapter that is no longer tracked. Failures are logged, as the adapter may be gone with
+// its model server.
+func (c *AdapterController) unload(ctx context.Context, pod string, address string, adapter string) {
+	logger := log.FromContext(ctx)
+	err := c.client.UnloadAdapter(ctx, address, adapter)
+	metrics.RecordLoraAdapterOperation(adapter, unloadOperation, err == nil)
+	if err != nil {
+		logger.Error(err, "Failed to unload LoRA adapter", "pod", pod, "adapter", adapter)
+		return
+	}
+	logger.V(logutil.DEFAULT).Info("Unloaded LoRA adapter", "pod", pod, "adapter", adapter)
+}
+
+// isGone returns true if the loaded adapter is missing from a complete list of the adapters on the model server
+// that was observed after it was loaded.
+func (s *adapterState) isGone(request PodAdapterRequest) bool {
+	return s.loading == nil && s.inFlight == 0 && request.ObservedAt.After(s.loadedAt) &&
+		!slices.Contains(request.Observed, request.Adapter)
+}
+
+func (p *podAdapters) getAdapter(adapter string) (*adapterState, bool) {
+	if p == nil {
+		return nil, false
+	}
+	state, found := p.adapters[adapter]
+	return state, found
+}
+
+// leastRecentlyUsed removes and returns the least recently used adapters without in-flight requests, to make
+// room for the requested adapter, which is already tracked.
+func (p *podAdapters) leastRecentlyUsed(request PodAdapterRequest) []string {
+	if request.MaxAdapters <= 0 {
+		return nil
+	}
+	loaded := len(p.adapters)
+	for _, observed := range request.Observed {
+		if _, tracked := p.adapters[observed]; !tracked {
+			loaded++ // adapters loaded by others take room too
+		}
+	}
+
+	var candidates []string
+	for adapter, state := range p.adapters {
+		if state.loading == nil && state.inFlight == 0 {
+			candidates = append(candidates, adapter)
+		}
+	}
+	slices.SortFunc(candidates, func(a, b string) int {
+		return p.adapters[a].lastUsed.Compare(p.adapters[b].lastUsed)
+	})
+
+	var victims []string
+	for _, adapter := range candidates {
+		if loaded <= request.MaxAdapters {
+			break
+		}
+		victims = append(victims, adapter)
+		delete(p.adapters, adapter)
+		loaded--
+	}
+	return victims
+}
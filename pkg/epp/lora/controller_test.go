@@ -0,0 +1,242 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lora
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// fakeAdapterClient records the adapter operations, and fails or blocks the loads on demand.
+type fakeAdapterClient struct {
+	mu         sync.Mutex
+	operations []string
+	loadErr    error
+	block      chan struct{} // if set, loads wait for it to be closed
+}
+
+func (c *fakeAdapterClient) LoadAdapter(_ context.Context, address string, adapter string, _ string) error {
+	if c.block != nil {
+		<-c.block
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.operations = append(c.operations, "load "+adapter+" on "+address)
+	return c.loadErr
+}
+
+func (c *fakeAdapterClient) UnloadAdapter(_ context.Context, address string, adapter string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.operations = append(c.operations, "unload "+adapter+" from "+address)
+	return nil
+}
+
+func (c *fakeAdapterClient) takeOperations() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	operations := c.operations
+	c.operations = nil
+	return operations
+}
+
+func newTestAdapterController() (*AdapterController, *fakeAdapterClient, *time.Time) {
+	client := &fakeAdapterClient{}
+	controller := NewAdapterController(client)
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	controller.now = func() time.Time { return now }
+	return controller, client, &now
+}
+
+func adapterRequest(adapter string, maxAdapters int, observed ...string) PodAdapterRequest {
+	return PodAdapterRequest{Pod: "default/pod1", Address: "10.0.0.1:8000", Adapter: adapter, Path: "/adapters/" + adapter,
+		Observed: observed, MaxAdapters: maxAdapters}
+}
+
+func TestAdapterControllerAcquire(t *testing.T) {
+	ctx := context.Background()
+	controller, client, _ := newTestAdapterController()
+
+	// the adapter is loaded once.
+	for range 2 {
+		if err := controller.Acquire(ctx, adapterRequest("sql-lora", 0)); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	// an adapter observed on the model server is not loaded.
+	if err := controller.Acquire(ctx, adapterRequest("tweet-lora", 0, "tweet-lora")); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if diff := cmp.Diff([]string{"load sql-lora on 10.0.0.1:8000"}, client.takeOperations()); diff != "" {
+		t.Errorf("Unexpected operations (-want +got): %v", diff)
+	}
+
+	// a failed load is retried by the next request.
+	client.loadErr = errors.New("failed to load")
+	if err := controller.Acquire(ctx, adapterRequest("news-lora", 0)); err == nil {
+		t.Errorf("Expected a load error")
+	}
+	client.loadErr = nil
+	if err := controller.Acquire(ctx, adapterRequest("news-lora", 0)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if diff := cmp.Diff([]string{"load news-lora on 10.0.0.1:8000", "load news-lora on 10.0.0.1:8000"}, client.takeOperations()); diff != "" {
+		t.Errorf("Unexpected operations (-want +got): %v", diff)
+	}
+}
+
+func TestAdapterControllerConcurrentLoads(t *testing.T) {
+	ctx := context.Background()
+	controller, client, _ := newTestAdapterController()
+	client.block = make(chan struct{})
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 5)
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- controller.Acquire(ctx, adapterRequest("sql-lora", 0))
+		}()
+	}
+	// wait for all the requests to acquire the adapter before the load completes.
+	for {
+		controller.mu.Lock()
+		inFlight := 0
+		if state, found := controller.pods["default/pod1"].getAdapter("sql-lora"); found {
+			inFlight = state.inFlight
+		}
+		controller.mu.Unlock()
+		if inFlight == 5 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	close(client.block)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	}
+	if diff := cmp.Diff([]string{"load sql-lora on 10.0.0.1:8000"}, client.takeOperations()); diff != "" {
+		t.Errorf("Unexpected operations (-want +got): %v", diff)
+	}
+}
+
+func TestAdapterControllerLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	controller, client, now := newTestAdapterController()
+
+	for _, adapter := range []string{"lora1", "lora2", "lora3"} {
+		if err := controller.Acquire(ctx, adapterRequest(adapter, 3)); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		*now = now.Add(time.Second)
+	}
+	controller.Release("default/pod1", "lora2")
+	*now = now.Add(time.Second)
+	controller.Release("default/pod1", "lora1")
+	_ = client.takeOperations()
+
+	// lora3 is in flight, lora2 was used the least recently.
+	if err := controller.Acquire(ctx, adapterRequest("lora4", 3)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := []string{"unload lora2 from 10.0.0.1:8000", "load lora4 on 10.0.0.1:8000"}
+	if diff := cmp.Diff(want, client.takeOperations()); diff != "" {
+		t.Errorf("Unexpected operations (-want +got): %v", diff)
+	}
+
+	// adapters loaded by others take room too.
+	if err := controller.Acquire(ctx, adapterRequest("lora5", 4, "other-lora")); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want = []string{"unload lora1 from 10.0.0.1:8000", "load lora5 on 10.0.0.1:8000"}
+	if diff := cmp.Diff(want, client.takeOperations()); diff != "" {
+		t.Errorf("Unexpected operations (-want +got): %v", diff)
+	}
+}
+
+func TestAdapterControllerUnloadIdle(t *testing.T) {
+	ctx := context.Background()
+	controller, client, now := newTestAdapterController()
+
+	for _, adapter := range []string{"lora1", "lora2"} {
+		if err := controller.Acquire(ctx, adapterRequest(adapter, 0)); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	controller.Release("default/pod1", "lora1")
+	_ = client.takeOperations()
+
+	*now = now.Add(time.Minute)
+	controller.UnloadIdle(ctx, time.Minute)
+	// lora2 is in flight.
+	if diff := cmp.Diff([]string{"unload lora1 from 10.0.0.1:8000"}, client.takeOperations()); diff != "" {
+		t.Errorf("Unexpected operations (-want +got): %v", diff)
+	}
+
+	controller.Release("default/pod1", "lora2")
+	controller.UnloadIdle(ctx, time.Minute)
+	if diff := cmp.Diff([]string(nil), client.takeOperations()); diff != "" {
+		t.Errorf("Unexpected operations (-want +got): %v", diff)
+	}
+	*now = now.Add(time.Minute)
+	controller.UnloadIdle(ctx, time.Minute)
+	if diff := cmp.Diff([]string{"unload lora2 from 10.0.0.1:8000"}, client.takeOperations()); diff != "" {
+		t.Errorf("Unexpected operations (-want +got): %v", diff)
+	}
+	if len(controller.pods) != 0 {
+		t.Errorf("Expected pods without adapters not to be tracked, got %v", controller.pods)
+	}
+}
+
+func TestAdapterControllerReloadGoneAdapter(t *testing.T) {
+	ctx := context.Background()
+	controller, client, now := newTestAdapterController()
+
+	if err := controller.Acquire(ctx, adapterRequest("sql-lora", 0)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	controller.Release("default/pod1", "sql-lora")
+
+	// a model list discovered before the load doesn't list the adapter yet.
+	request := adapterRequest("sql-lora", 0)
+	request.ObservedAt = now.Add(-time.Second)
+	if err := controller.Acquire(ctx, request); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	controller.Release("default/pod1", "sql-lora")
+	// the model server restarted without the adapter.
+	request.ObservedAt = now.Add(time.Second)
+	if err := controller.Acquire(ctx, request); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := []string{"load sql-lora on 10.0.0.1:8000", "load sql-lora on 10.0.0.1:8000"}
+	if diff := cmp.Diff(want, client.takeOperations()); diff != "" {
+		t.Errorf("Unexpected operations (-want +got): %v", diff)
+	}
+}
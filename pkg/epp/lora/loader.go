@@ -0,0 +1,243 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lora
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datalayer/models"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/plugins"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/requestcontrol"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+)
+
+const (
+	AdapterLoaderType = "lora-adapter-loader"
+
+	// DefaultIdleTimeout is the default time after which an adapter that was not used is unloaded.
+	DefaultIdleTimeout = 10 * time.Minute
+	// DefaultLoadTimeout is the default time a request waits for its adapter to load.
+	DefaultLoadTimeout = 30 * time.Second
+
+	// staleRequestTimeout is the time after which a request that did not complete no longer holds its adapter.
+	// Requests that fail to be forwarded release it when they are cancelled, so this only covers requests whose
+	// completion is never reported.
+	staleRequestTimeout = time.Hour
+)
+
+// AdapterLoaderConfig is the configuration of the AdapterLoader.
+type AdapterLoaderConfig struct {
+	// Adapters maps the names of the adapters that are loaded on demand to the path they're loaded from.
+	// Requests for other models don't load adapters.
+	Adapters map[string]string `json:"adapters"`
+	// Scheme is the scheme of the adapter API of the model servers, http or https.
+	Scheme string `json:"scheme"`
+	// MaxLoadedAdapters is the maximum number of adapters loaded on a model server. If 0, the max_lora value
+	// reported by the model server metrics is used, if any.
+	MaxLoadedAdapters int `json:"maxLoadedAdapters"`
+	// IdleTimeout is the time after which an adapter that was not used is unloaded. 0 disables it.
+	IdleTimeout metav1.Duration `json:"idleTimeout"`
+	// LoadTimeout is the time a request waits for its adapter to load.
+	LoadTimeout metav1.Duration `json:"loadTimeout"`
+}
+
+// compile-time type assertion
+var (
+	_ requestcontrol.PreRequest       = &AdapterLoader{}
+	_ requestcontrol.ResponseComplete = &AdapterLoader{}
+	_ requestcontrol.ModelProvider    = &AdapterLoader{}
+	_ manager.Runnable                = &AdapterLoader{}
+)
+
+// AdapterLoaderFactory defines the factory function for AdapterLoader.
+func AdapterLoaderFactory(name string, rawParameters json.RawMessage, _ plugins.Handle) (plugins.Plugin, error) {
+	config := AdapterLoaderConfig{
+		Scheme:      "http",
+		IdleTimeout: metav1.Duration{Duration: DefaultIdleTimeout},
+		LoadTimeout: metav1.Duration{Duration: DefaultLoadTimeout},
+	}
+	if rawParameters != nil {
+		if err := json.Unmarshal(rawParameters, &config); err != nil {
+			return nil, fmt.Errorf("failed to parse the parameters of the '%s' plugin - %w", AdapterLoaderType, err)
+		}
+	}
+	if err := validateAdapterLoaderConfig(config); err != nil {
+		return nil, fmt.Errorf("invalid parameters of the '%s' plugin - %w", AdapterLoaderType, err)
+	}
+
+	return NewAdapterLoader(config, NewHTTPAdapterClient(config.Scheme)).WithName(name), nil
+}
+
+func validateAdapterLoaderConfig(config AdapterLoaderConfig) error {
+	switch {
+	case len(config.Adapters) == 0:
+		return errors.New("adapters must not be empty")
+	case config.Scheme != "http" && config.Scheme != "https":
+		return fmt.Errorf("scheme %q must be http or https", config.Scheme)
+	case config.MaxLoadedAdapters < 0:
+		return fmt.Errorf("maxLoadedAdapters %d must not be negative", config.MaxLoadedAdapters)
+	case config.IdleTimeout.Duration < 0:
+		return fmt.Errorf("idleTimeout %v must not be negative", config.IdleTimeout.Duration)
+	case config.LoadTimeout.Duration <= 0:
+		return fmt.Errorf("loadTimeout %v must be positive", config.LoadTimeout.Duration)
+	}
+	return nil
+}
+
+// NewAdapterLoader initializes a new AdapterLoader and returns its pointer.
+func NewAdapterLoader(config AdapterLoaderConfig, client AdapterClient) *AdapterLoader {
+	return &AdapterLoader{
+		typedName:  plugins.TypedName{Type: AdapterLoaderType, Name: AdapterLoaderType},
+		config:     config,
+		controller: NewAdapterController(client),
+	}
+}
+
+// AdapterLoader loads the LoRA adapter a request targets on the pod the request is scheduled to, before the
+// request is sent, if the pod doesn't have it loaded. Concurrent requests wait for the same load. The least
+// recently used adapters are unloaded to make room for new ones, and when they are idle. The adapters
+// loaded by each EPP replica are tracked separately.
+type AdapterLoader struct {
+	typedName  plugins.TypedName
+	config     AdapterLoaderConfig
+	controller *AdapterController
+
+	requests sync.Map // key: request ID, value: *acquiredAdapter
+}
+
+// acquiredAdapter is the adapter a request acquired on a pod.
+type acquiredAdapter struct {
+	pod        string
+	adapter    string
+	acquiredAt time.Time
+}
+
+// WithName sets the name of the plugin.
+func (l *AdapterLoader) WithName(name string) *AdapterLoader {
+	l.typedName.Name = name
+	return l
+}
+
+// TypedName returns the type and name tuple of this plugin instance.
+func (l *AdapterLoader) TypedName() plugins.TypedName {
+	return l.typedName
+}
+
+// PreRequest loads the target adapter of the request on the pod it is scheduled to, waiting for the load.
+func (l *AdapterLoader) PreRequest(ctx context.Context, request *types.LLMRequest, schedulingResult *types.SchedulingResult, targetPort int) {
+	path, found := l.config.Adapters[request.TargetModel]
+	if !found {
+		return
+	}
+	targetPod := schedulingResult.ProfileResults[schedulingResult.PrimaryProfileName].TargetPods[0]
+	pod := targetPod.GetPod()
+	adapterRequest := PodAdapterRequest{
+		Pod:         pod.NamespacedName.String(),
+		Address:     net.JoinHostPort(pod.Address, strconv.Itoa(targetPort)),
+		Adapter:     request.TargetModel,
+		Path:        path,
+		MaxAdapters: l.config.MaxLoadedAdapters,
+	}
+	adapterRequest.Observed, adapterRequest.ObservedAt = observedAdapters(targetPod)
+	if adapterRequest.MaxAdapters == 0 && targetPod.GetMetrics() != nil {
+		adapterRequest.MaxAdapters = targetPod.GetMetrics().MaxActiveModels
+	}
+
+	loadCtx, cancel := context.WithTimeout(ctx, l.config.LoadTimeout.Duration)
+	defer cancel()
+	if err := l.controller.Acquire(loadCtx, adapterRequest); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to acquire LoRA adapter", "pod", adapterRequest.Pod, "adapter", request.TargetModel)
+		return
+	}
+	l.requests.Store(request.RequestId, &acquiredAdapter{pod: adapterRequest.Pod, adapter: request.TargetModel, acquiredAt: time.Now()})
+}
+
+// ProvidesModel returns true if the model is one of the adapters that are loaded on demand.
+func (l *AdapterLoader) ProvidesModel(_ context.Context, model string) bool {
+	_, found := l.config.Adapters[model]
+	return found
+}
+
+// ResponseComplete releases the adapter the request acquired, also when the request was cancelled.
+func (l *AdapterLoader) ResponseComplete(_ context.Context, request *types.LLMRequest, _ *requestcontrol.Response, _ *backend.Pod) {
+	if acquired, found := l.requests.LoadAndDelete(request.RequestId); found {
+		l.controller.Release(acquired.(*acquiredAdapter).pod, acquired.(*acquiredAdapter).adapter)
+	}
+}
+
+// Start periodically unloads the idle adapters, until the context is cancelled.
+func (l *AdapterLoader) Start(ctx context.Context) error {
+	interval := min(l.config.IdleTimeout.Duration, time.Minute)
+	if interval <= 0 {
+		interval = time.Minute // stale requests are released even if idle adapters are not unloaded
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			l.releaseStaleRequests()
+			if l.config.IdleTimeout.Duration > 0 {
+				l.controller.UnloadIdle(ctx, l.config.IdleTimeout.Duration)
+			}
+		}
+	}
+}
+
+// releaseStaleRequests releases the adapters of the requests that did not complete within staleRequestTimeout.
+func (l *AdapterLoader) releaseStaleRequests() {
+	l.requests.Range(func(requestID, value any) bool {
+		if acquired := value.(*acquiredAdapter); time.Since(acquired.acquiredAt) > staleRequestTimeout {
+			l.requests.Delete(requestID)
+			l.controller.Release(acquired.pod, acquired.adapter)
+		}
+		return true
+	})
+}
+
+// observedAdapters returns the adapters reported by the metrics of the pod and by its discovered models, and the
+// time the models were discovered, if they were.
+func observedAdapters(pod types.Pod) ([]string, time.Time) {
+	var observed []string
+	var observedAt time.Time
+	if podMetrics := pod.GetMetrics(); podMetrics != nil {
+		observed = append(observed, slices.Collect(maps.Keys(podMetrics.ActiveModels))...)
+		observed = append(observed, slices.Collect(maps.Keys(podMetrics.WaitingModels))...)
+	}
+	if served, found := models.GetServedModels(pod.GetAttributes()); found {
+		observed = append(observed, slices.Collect(maps.Keys(served.Adapters))...)
+		observedAt = served.LastUpdateTime
+	}
+	slices.Sort(observed)
+	return slices.Compact(observed), observedAt
+}
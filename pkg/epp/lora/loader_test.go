@@ -0,0 +1,148 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lora
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend"
+	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/requestcontrol"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+)
+
+func TestAdapterLoaderFactory(t *testing.T) {
+	tests := []struct {
+		name          string
+		rawParameters string
+		wantErr       bool
+	}{
+		{name: "defaults", rawParameters: `{"adapters": {"sql-lora": "/adapters/sql-lora"}}`},
+		{name: "all parameters", rawParameters: `{"adapters": {"sql-lora": "/adapters/sql-lora"}, "scheme": "https",
+			"maxLoadedAdapters": 4, "idleTimeout": "0s", "loadTimeout": "1m"}`},
+		{name: "no adapters", rawParameters: `{}`, wantErr: true},
+		{name: "invalid scheme", rawParameters: `{"adapters": {"sql-lora": "/adapters/sql-lora"}, "scheme": "grpc"}`, wantErr: true},
+		{name: "negative max loaded adapters", rawParameters: `{"adapters": {"sql-lora": "/adapters/sql-lora"}, "maxLoadedAdapters": -1}`, wantErr: true},
+		{name: "zero load timeout", rawParameters: `{"adapters": {"sql-lora": "/adapters/sql-lora"}, "loadTimeout": "0s"}`, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := AdapterLoaderFactory("loader", json.RawMessage(test.rawParameters), nil)
+			if gotErr := err != nil; gotErr != test.wantErr {
+				t.Errorf("Unexpected error, want error %t, got %v", test.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestAdapterLoader(t *testing.T) {
+	var mu sync.Mutex
+	var calls []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		calls = append(calls, r.URL.Path+" "+string(body))
+		mu.Unlock()
+	}))
+	defer server.Close()
+	host, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+	portNumber, _ := strconv.Atoi(port)
+
+	plugin, err := AdapterLoaderFactory("loader", json.RawMessage(`{"adapters": {"sql-lora": "/adapters/sql-lora", "tweet-lora": "/adapters/tweet-lora"}}`), nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	loader := plugin.(*AdapterLoader)
+	if !loader.ProvidesModel(context.Background(), "sql-lora") || loader.ProvidesModel(context.Background(), "llama") {
+		t.Errorf("Expected only the configured adapters to be provided")
+	}
+
+	pod := &types.PodMetrics{
+		Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Namespace: "default", Name: "pod1"}, Address: host},
+		MetricsState: &backendmetrics.MetricsState{
+			ActiveModels:    map[string]int{"tweet-lora": 1},
+			WaitingModels:   map[string]int{},
+			MaxActiveModels: 2,
+		},
+	}
+	result := &types.SchedulingResult{
+		ProfileResults:     map[string]*types.ProfileRunResult{"default": {TargetPods: []types.Pod{pod}}},
+		PrimaryProfileName: "default",
+	}
+	ctx := context.Background()
+	for i, model := range []string{"sql-lora", "tweet-lora", "base-model", "sql-lora"} {
+		request := &types.LLMRequest{RequestId: strconv.Itoa(i), TargetModel: model}
+		loader.PreRequest(ctx, request, result, portNumber)
+		loader.ResponseComplete(ctx, request, nil, pod.GetPod())
+	}
+
+	want := []string{`/v1/load_lora_adapter {"lora_name":"sql-lora","lora_path":"/adapters/sql-lora"}`}
+	if diff := cmp.Diff(want, calls); diff != "" {
+		t.Errorf("Unexpected adapter API calls (-want +got): %v", diff)
+	}
+	loader.requests.Range(func(requestID, _ any) bool {
+		t.Errorf("Expected request %v to release its adapter", requestID)
+		return true
+	})
+}
+
+func TestAdapterLoaderCancelledRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	defer server.Close()
+	host, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+	portNumber, _ := strconv.Atoi(port)
+
+	plugin, err := AdapterLoaderFactory("loader", json.RawMessage(`{"adapters": {"sql-lora": "/adapters/sql-lora"}}`), nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	loader := plugin.(*AdapterLoader)
+
+	pod := &types.PodMetrics{
+		Pod:          &backend.Pod{NamespacedName: k8stypes.NamespacedName{Namespace: "default", Name: "pod1"}, Address: host},
+		MetricsState: &backendmetrics.MetricsState{MaxActiveModels: 2},
+	}
+	result := &types.SchedulingResult{
+		ProfileResults:     map[string]*types.ProfileRunResult{"default": {TargetPods: []types.Pod{pod}}},
+		PrimaryProfileName: "default",
+	}
+	ctx := context.Background()
+	request := &types.LLMRequest{RequestId: "1", TargetModel: "sql-lora"}
+	loader.PreRequest(ctx, request, result, portNumber)
+	if state, _ := loader.controller.pods["default/pod1"].getAdapter("sql-lora"); state == nil || state.inFlight != 1 {
+		t.Fatalf("Expected the request to acquire the adapter")
+	}
+
+	// A request that is cancelled before its response is complete, e.g. because it failed to be forwarded,
+	// releases the adapter right away rather than after staleRequestTimeout.
+	loader.ResponseComplete(ctx, request, &requestcontrol.Response{EndOfStream: false}, pod.GetPod())
+	if state, _ := loader.controller.pods["default/pod1"].getAdapter("sql-lora"); state.inFlight != 0 {
+		t.Errorf("Expected the cancelled request to release the adapter, %d requests in flight", state.inFlight)
+	}
+}
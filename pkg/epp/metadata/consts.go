@@ -26,10 +26,26 @@ const (
 	DestinationEndpointNamespace = "envoy.lb"
 	// DestinationEndpointKey is the header and response metadata key used by Envoy to route to the appropriate pod.
 	DestinationEndpointKey = "x-gateway-destination-endpoint"
+	// DestinationEndpointFallbackKey is the response metadata key used to pass Envoy the ordered list of endpoints
+	// to fail over to if the destination endpoints fail to serve the request.
+	DestinationEndpointFallbackKey = "x-gateway-destination-endpoint-fallback"
+	// DestinationEndpointServedKey is the response metadata key used by Envoy to report the endpoint that served the request.
+	DestinationEndpointServedKey = "x-gateway-destination-endpoint-served"
+	// AttemptNamespace is the key for the outer namespace struct in the metadata field of the extproc request that is
+	// used by the gateway to describe the attempt of a request it retries.
+	AttemptNamespace = "envoy.lb.attempt"
+	// AttemptCountKey is the request metadata key used by the gateway to pass the number of the attempt of the request,
+	// starting at 1 for the first attempt.
+	AttemptCountKey = "x-gateway-attempt-count"
+	// AttemptedEndpointsKey is the request metadata key used by the gateway to pass the array of endpoints that failed
+	// to serve the previous attempts of the request.
+	AttemptedEndpointsKey = "x-gateway-attempted-endpoints"
 	// FlowFairnessIDKey is the header key used to pass the fairness ID to be used in Flow Control.
 	FlowFairnessIDKey = "x-gateway-inference-fairness-id"
 	// ObjectiveKey is the header key used to specify the objective of an incoming request.
 	ObjectiveKey = "x-gateway-inference-objective"
 	// ModelNameRewriteKey is the header key used to specify the model name to be used when the request is forwarded to the model server.
 	ModelNameRewriteKey = "x-gateway-model-name-rewrite"
+	// ErrorCodeKey is the header key used to return the EPP error code of a request that the EPP rejected.
+	ErrorCodeKey = "x-gateway-inference-error-code"
 )
@@ -18,6 +18,7 @@ package metrics
 
 import (
 	"context"
+	"strconv"
 	"sync"
 	"time"
 
@@ -84,6 +85,19 @@ var (
 		[]string{"model_name", "target_model_name"},
 	)
 
+	requestMediaSizes = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Subsystem: InferenceModelComponent,
+			Name:      "request_media_sizes",
+			Help:      metricsutil.HelpMsgWithStability("Inference model requests inline media size distribution in bytes for each model, target model and media type.", compbasemetrics.ALPHA),
+			// Use buckets ranging from 1KB to 1GB.
+			Buckets: []float64{
+				1024, 4096, 16384, 65536, 262144, 1048576, 4194304, 16777216, 67108864, 268435456, 1073741824,
+			},
+		},
+		[]string{"model_name", "target_model_name", "media_type"},
+	)
+
 	responseSizes = prometheus.NewHistogramVec(
 		prometheus.HistogramOpts{
 			Subsystem: InferenceModelComponent,
@@ -141,6 +155,32 @@ var (
 		[]string{"model_name", "target_model_name"},
 	)
 
+	// TTFT - Time To First Token
+	timeToFirstToken = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Subsystem: InferenceModelComponent,
+			Name:      "time_to_first_token_seconds",
+			Help:      metricsutil.HelpMsgWithStability("Inference model streamed response time to first token distribution in seconds for each model, target model and target pod.", compbasemetrics.ALPHA),
+			Buckets: []float64{
+				0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 0.75, 1, 2.5, 5, 7.5, 10, 20, 40, 60,
+			},
+		},
+		[]string{"model_name", "target_model_name", "target_pod"},
+	)
+
+	// ITL - Inter-Token Latency
+	interTokenLatency = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Subsystem: InferenceModelComponent,
+			Name:      "inter_token_latency_seconds",
+			Help:      metricsutil.HelpMsgWithStability("Inference model streamed response inter-token latency distribution in seconds for each model, target model and target pod.", compbasemetrics.ALPHA),
+			Buckets: []float64{
+				0.001, 0.0025, 0.005, 0.01, 0.015, 0.02, 0.03, 0.04, 0.05, 0.075, 0.1, 0.25, 0.5, 1, 2.5,
+			},
+		},
+		[]string{"model_name", "target_model_name", "target_pod"},
+	)
+
 	// Inference Pool Metrics
 	inferencePoolAvgKVCache = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
@@ -169,6 +209,24 @@ var (
 		[]string{"name"},
 	)
 
+	inferencePoolEndpointEjected = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: InferencePoolComponent,
+			Name:      "endpoint_ejected",
+			Help:      metricsutil.HelpMsgWithStability("Whether the endpoint is ejected from scheduling by outlier detection (1) or not (0).", compbasemetrics.ALPHA),
+		},
+		[]string{"target_pod"},
+	)
+
+	inferencePoolEndpointEjections = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: InferencePoolComponent,
+			Name:      "endpoint_ejections_total",
+			Help:      metricsutil.HelpMsgWithStability("Counter of the ejections of an endpoint from scheduling by outlier detection, broken out for each reason.", compbasemetrics.ALPHA),
+		},
+		[]string{"target_pod", "reason"},
+	)
+
 	// Scheduler Metrics
 	SchedulerE2ELatency = prometheus.NewHistogramVec(
 		prometheus.HistogramOpts{
@@ -226,6 +284,26 @@ var (
 		[]string{},
 	)
 
+	// LoRA adapter Metrics
+	loraAdapterOperations = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: InferenceExtension,
+			Name:      "lora_adapter_operations_total",
+			Help:      metricsutil.HelpMsgWithStability("Counter of the LoRA adapter load and unload operations on the model servers, broken out for each adapter, operation and result.", compbasemetrics.ALPHA),
+		},
+		[]string{"adapter", "operation", "result"},
+	)
+
+	// Load shedding Metrics
+	shedRequests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: InferenceExtension,
+			Name:      "shed_requests_total",
+			Help:      metricsutil.HelpMsgWithStability("Counter of the requests rejected by admission control because the system is saturated, broken out for each priority and target model.", compbasemetrics.ALPHA),
+		},
+		[]string{"priority", "target_model_name"},
+	)
+
 	// Info Metrics
 	InferenceExtensionInfo = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
@@ -246,20 +324,27 @@ func Register(customCollectors ...prometheus.Collector) {
 		metrics.Registry.MustRegister(requestErrCounter)
 		metrics.Registry.MustRegister(requestLatencies)
 		metrics.Registry.MustRegister(requestSizes)
+		metrics.Registry.MustRegister(requestMediaSizes)
 		metrics.Registry.MustRegister(responseSizes)
 		metrics.Registry.MustRegister(inputTokens)
 		metrics.Registry.MustRegister(outputTokens)
 		metrics.Registry.MustRegister(runningRequests)
 		metrics.Registry.MustRegister(NormalizedTimePerOutputToken)
+		metrics.Registry.MustRegister(timeToFirstToken)
+		metrics.Registry.MustRegister(interTokenLatency)
 		metrics.Registry.MustRegister(inferencePoolAvgKVCache)
 		metrics.Registry.MustRegister(inferencePoolAvgQueueSize)
 		metrics.Registry.MustRegister(inferencePoolReadyPods)
+		metrics.Registry.MustRegister(inferencePoolEndpointEjected)
+		metrics.Registry.MustRegister(inferencePoolEndpointEjections)
 		metrics.Registry.MustRegister(SchedulerE2ELatency)
 		metrics.Registry.MustRegister(PluginProcessingLatencies)
 		metrics.Registry.MustRegister(InferenceExtensionInfo)
 		metrics.Registry.MustRegister(PrefixCacheSize)
 		metrics.Registry.MustRegister(PrefixCacheHitRatio)
 		metrics.Registry.MustRegister(PrefixCacheHitLength)
+		metrics.Registry.MustRegister(loraAdapterOperations)
+		metrics.Registry.MustRegister(shedRequests)
 		for _, collector := range customCollectors {
 			metrics.Registry.MustRegister(collector)
 		}
@@ -272,20 +357,27 @@ func Reset() {
 	requestErrCounter.Reset()
 	requestLatencies.Reset()
 	requestSizes.Reset()
+	requestMediaSizes.Reset()
 	responseSizes.Reset()
 	inputTokens.Reset()
 	outputTokens.Reset()
 	runningRequests.Reset()
 	NormalizedTimePerOutputToken.Reset()
+	timeToFirstToken.Reset()
+	interTokenLatency.Reset()
 	inferencePoolAvgKVCache.Reset()
 	inferencePoolAvgQueueSize.Reset()
 	inferencePoolReadyPods.Reset()
+	inferencePoolEndpointEjected.Reset()
+	inferencePoolEndpointEjections.Reset()
 	SchedulerE2ELatency.Reset()
 	PluginProcessingLatencies.Reset()
 	InferenceExtensionInfo.Reset()
 	PrefixCacheSize.Reset()
 	PrefixCacheHitRatio.Reset()
 	PrefixCacheHitLength.Reset()
+	loraAdapterOperations.Reset()
+	shedRequests.Reset()
 }
 
 // RecordRequstCounter records the number of requests.
@@ -305,6 +397,11 @@ func RecordRequestSizes(modelName, targetModelName string, reqSize int) {
 	requestSizes.WithLabelValues(modelName, targetModelName).Observe(float64(reqSize))
 }
 
+// RecordRequestMediaSizes records the total size of the inline media of a type in a request.
+func RecordRequestMediaSizes(modelName, targetModelName, mediaType string, size int) {
+	requestMediaSizes.WithLabelValues(modelName, targetModelName, mediaType).Observe(float64(size))
+}
+
 // RecordRequestLatencies records duration of request.
 func RecordRequestLatencies(ctx context.Context, modelName, targetModelName string, received time.Time, complete time.Time) bool {
 	if !complete.After(received) {
@@ -357,6 +454,17 @@ func RecordNormalizedTimePerOutputToken(ctx context.Context, modelName, targetMo
 	return true
 }
 
+// RecordTimeToFirstToken records the time from receiving a request to receiving the first token of its
+// streamed response.
+func RecordTimeToFirstToken(modelName, targetModelName, targetPod string, ttft time.Duration) {
+	timeToFirstToken.WithLabelValues(modelName, targetModelName, targetPod).Observe(ttft.Seconds())
+}
+
+// RecordInterTokenLatency records the latency between two consecutive tokens of a streamed response.
+func RecordInterTokenLatency(modelName, targetModelName, targetPod string, itl time.Duration) {
+	interTokenLatency.WithLabelValues(modelName, targetModelName, targetPod).Observe(itl.Seconds())
+}
+
 // IncRunningRequests increases the current running requests.
 func IncRunningRequests(modelName string) {
 	if modelName != "" {
@@ -383,6 +491,37 @@ func RecordInferencePoolReadyPods(name string, runningPods float64) {
 	inferencePoolReadyPods.WithLabelValues(name).Set(runningPods)
 }
 
+// RecordEndpointEjected records the ejection of an endpoint by outlier detection, for the given reason.
+func RecordEndpointEjected(targetPod, reason string) {
+	inferencePoolEndpointEjected.WithLabelValues(targetPod).Set(1)
+	inferencePoolEndpointEjections.WithLabelValues(targetPod, reason).Inc()
+}
+
+// RecordEndpointReturned records the return of an ejected endpoint to scheduling.
+func RecordEndpointReturned(targetPod string) {
+	inferencePoolEndpointEjected.WithLabelValues(targetPod).Set(0)
+}
+
+// DeleteEndpointEjection deletes the ejection metrics of an endpoint that is no longer tracked.
+func DeleteEndpointEjection(targetPod string) {
+	inferencePoolEndpointEjected.DeleteLabelValues(targetPod)
+	inferencePoolEndpointEjections.DeletePartialMatch(prometheus.Labels{"target_pod": targetPod})
+}
+
+// RecordLoraAdapterOperation records a load or unload operation of a LoRA adapter, and whether it succeeded.
+func RecordLoraAdapterOperation(adapter, operation string, success bool) {
+	result := "success"
+	if !success {
+		result = "failure"
+	}
+	loraAdapterOperations.WithLabelValues(adapter, operation, result).Inc()
+}
+
+// RecordShedRequest records a request of the given priority that was rejected because the system is saturated.
+func RecordShedRequest(priority int, targetModelName string) {
+	shedRequests.WithLabelValues(strconv.Itoa(priority), targetModelName).Inc()
+}
+
 // RecordSchedulerE2ELatency records the end-to-end scheduling latency.
 func RecordSchedulerE2ELatency(duration time.Duration) {
 	SchedulerE2ELatency.WithLabelValues().Observe(duration.Seconds())
@@ -42,6 +42,10 @@ const (
 	KVCacheAvgUsageMetric              = InferencePoolComponent + "_average_kv_cache_utilization"
 	QueueAvgSizeMetric                 = InferencePoolComponent + "_average_queue_size"
 	PerPodQueueSizeMetrics             = InferencePoolComponent + "_per_pod_queue_size"
+	EndpointEjectedMetric              = InferencePoolComponent + "_endpoint_ejected"
+	EndpointEjectionsMetric            = InferencePoolComponent + "_endpoint_ejections_total"
+	LoraAdapterOperationsMetric        = InferenceExtension + "_lora_adapter_operations_total"
+	ShedRequestsMetric                 = InferenceExtension + "_shed_requests_total"
 )
 
 func TestRecordRequestCounterandSizes(t *testing.T) {
@@ -113,6 +117,53 @@ func TestRecordRequestCounterandSizes(t *testing.T) {
 	}
 }
 
+func TestRecordRequestMediaSizes(t *testing.T) {
+	Register()
+	RecordRequestMediaSizes("m10", "t10", "image", 2000)
+	RecordRequestMediaSizes("m10", "t10", "image", 200000)
+	RecordRequestMediaSizes("m10", "t10", "audio", 500)
+
+	wantRequestMediaSizes, err := os.Open("testdata/request_media_sizes_metric")
+	defer func() {
+		if err := wantRequestMediaSizes.Close(); err != nil {
+			t.Error(err)
+		}
+	}()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := testutil.GatherAndCompare(metrics.Registry, wantRequestMediaSizes, InferenceModelComponent+"_request_media_sizes"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRecordStreamingLatencies(t *testing.T) {
+	Register()
+	RecordTimeToFirstToken("m10", "t10", "default/pod1", 80*time.Millisecond)
+	RecordTimeToFirstToken("m10", "t10", "default/pod2", 3*time.Second)
+	RecordInterTokenLatency("m10", "t10", "default/pod1", 12*time.Millisecond)
+	RecordInterTokenLatency("m10", "t10", "default/pod1", 45*time.Millisecond)
+
+	for _, metric := range []struct {
+		name     string
+		testdata string
+	}{
+		{name: InferenceModelComponent + "_time_to_first_token_seconds", testdata: "testdata/time_to_first_token_seconds_metric"},
+		{name: InferenceModelComponent + "_inter_token_latency_seconds", testdata: "testdata/inter_token_latency_seconds_metric"},
+	} {
+		want, err := os.Open(metric.testdata)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := testutil.GatherAndCompare(metrics.Registry, want, metric.name); err != nil {
+			t.Error(err)
+		}
+		if err := want.Close(); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
 func TestRecordRequestErrorCounter(t *testing.T) {
 	type requests struct {
 		modelName       string
@@ -559,6 +610,72 @@ func TestInferencePoolMetrics(t *testing.T) {
 	}
 }
 
+func TestEndpointEjectionMetrics(t *testing.T) {
+	Register()
+	RecordEndpointEjected("ns/pod1", "consecutive_errors")
+	RecordEndpointEjected("ns/pod2", "error_rate")
+	RecordEndpointReturned("ns/pod2")
+	RecordEndpointEjected("ns/pod2", "latency")
+	RecordEndpointReturned("ns/pod2")
+	RecordEndpointEjected("ns/pod3", "latency")
+	DeleteEndpointEjection("ns/pod3")
+
+	wantEjections, err := os.Open("testdata/endpoint_ejection_metrics")
+	defer func() {
+		if err := wantEjections.Close(); err != nil {
+			t.Error(err)
+		}
+	}()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := testutil.GatherAndCompare(metrics.Registry, wantEjections, EndpointEjectedMetric, EndpointEjectionsMetric); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestLoraAdapterOperationsMetric(t *testing.T) {
+	Register()
+	RecordLoraAdapterOperation("sql-lora", "load", true)
+	RecordLoraAdapterOperation("sql-lora", "load", true)
+	RecordLoraAdapterOperation("sql-lora", "unload", true)
+	RecordLoraAdapterOperation("tweet-lora", "load", false)
+
+	wantOperations, err := os.Open("testdata/lora_adapter_operations_metric")
+	defer func() {
+		if err := wantOperations.Close(); err != nil {
+			t.Error(err)
+		}
+	}()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := testutil.GatherAndCompare(metrics.Registry, wantOperations, LoraAdapterOperationsMetric); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestShedRequestsMetric(t *testing.T) {
+	Register()
+	RecordShedRequest(-2, "llama")
+	RecordShedRequest(-2, "llama")
+	RecordShedRequest(-1, "llama")
+	RecordShedRequest(0, "sql-lora")
+
+	wantShed, err := os.Open("testdata/shed_requests_metric")
+	defer func() {
+		if err := wantShed.Close(); err != nil {
+			t.Error(err)
+		}
+	}()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := testutil.GatherAndCompare(metrics.Registry, wantShed, ShedRequestsMetric); err != nil {
+		t.Error(err)
+	}
+}
+
 func TestPluginProcessingLatencies(t *testing.T) {
 	type pluginLatency struct {
 		extensionPoint string
@@ -37,7 +37,7 @@ type HandlePlugins interface {
 	// AddPlugin adds a plugin to the set of known plugin instances
 	AddPlugin(name string, plugin Plugin)
 
-	// GetAllPlugins returns all of the known plugins
+	// GetAllPlugins returns all of the known plugins, in the order they were added
 	GetAllPlugins() []Plugin
 
 	// GetAllPluginsWithNames returns all of the known plugins with their names
@@ -58,6 +58,8 @@ func (h *eppHandle) Context() context.Context {
 // eppHandlePlugins implements the set of APIs to work with instantiated plugins
 type eppHandlePlugins struct {
 	plugins map[string]Plugin
+	// names are the names of the plugins in the order they were added, which is the configured order.
+	names []string
 }
 
 // Plugin returns the named plugin instance
@@ -67,14 +69,17 @@ func (h *eppHandlePlugins) Plugin(name string) Plugin {
 
 // AddPlugin adds a plugin to the set of known plugin instances
 func (h *eppHandlePlugins) AddPlugin(name string, plugin Plugin) {
+	if _, ok := h.plugins[name]; !ok {
+		h.names = append(h.names, name)
+	}
 	h.plugins[name] = plugin
 }
 
-// GetAllPlugins returns all of the known plugins
+// GetAllPlugins returns all of the known plugins, in the order they were added
 func (h *eppHandlePlugins) GetAllPlugins() []Plugin {
-	result := make([]Plugin, 0)
-	for _, plugin := range h.plugins {
-		result = append(result, plugin)
+	result := make([]Plugin, 0, len(h.names))
+	for _, name := range h.names {
+		result = append(result, h.plugins[name])
 	}
 	return result
 }
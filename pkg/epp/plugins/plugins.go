@@ -16,9 +16,20 @@ limitations under the License.
 
 package plugins
 
+import (
+	"net/http"
+)
+
 // Plugin defines the interface for a plugin.
 // This interface should be embedded in all plugins across the code.
 type Plugin interface {
 	// TypedName returns the type and name tuple of this plugin instance.
 	TypedName() TypedName
 }
+
+// DebugHandler is implemented by plugins that expose their internal state for debugging.
+// The handler is served by the metrics server, under /debug/plugins/<plugin name>.
+type DebugHandler interface {
+	Plugin
+	http.Handler
+}
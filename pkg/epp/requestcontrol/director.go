@@ -20,9 +20,11 @@ package requestcontrol
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/rand"
 	"net"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -32,6 +34,9 @@ import (
 	"sigs.k8s.io/gateway-api-inference-extension/apix/v1alpha2"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend"
 	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datalayer"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datalayer/health"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datalayer/models"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datastore"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/handlers"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/metadata"
@@ -52,24 +57,78 @@ type SaturationDetector interface {
 	IsSaturated(ctx context.Context) bool
 }
 
+// RetryAfterEstimator is optionally implemented by a SaturationDetector, to estimate when requests that are
+// rejected due to saturation can be retried.
+type RetryAfterEstimator interface {
+	RetryAfter(ctx context.Context) time.Duration
+}
+
+// ModelSaturationDetector is optionally implemented by a SaturationDetector, to signal whether the backends are
+// considered saturated for the requests for a model, e.g. when no backend has room for a LoRA adapter.
+type ModelSaturationDetector interface {
+	IsModelSaturated(ctx context.Context, model string) bool
+}
+
+// AdapterSlotReservation is optionally implemented by a SaturationDetector that reserves LoRA adapter slots for
+// some adapters, to keep the requests for other adapters from taking the reserved slots, whatever their priority.
+// PodsWithAdapterSlot returns the candidate pods the request for the model can be scheduled to without taking
+// a reserved slot.
+type AdapterSlotReservation interface {
+	PodsWithAdapterSlot(ctx context.Context, model string, pods []schedulingtypes.Pod) []schedulingtypes.Pod
+}
+
+// AdmissionRequest describes a request whose admission is decided by an AdmissionDetector.
+type AdmissionRequest struct {
+	// Priority is the priority of the objective of the request.
+	Priority int
+	// TargetModel is the model the request is for.
+	TargetModel string
+}
+
+// AdmissionDetector is optionally implemented by a SaturationDetector that decides the admission of every request
+// from its description, e.g. to shed the requests of different priorities at different saturation levels. When
+// implemented, it is used instead of the other saturation signals, and decides for the requests of every priority,
+// including non-negative priorities, which are never shed otherwise. Admit returns whether the request is admitted
+// and, if it is not, the estimated time after which it can be retried, or zero if unknown.
+type AdmissionDetector interface {
+	Admit(ctx context.Context, request AdmissionRequest) (bool, time.Duration)
+}
+
 // NewDirectorWithConfig creates a new Director instance with all dependencies.
 func NewDirectorWithConfig(datastore datastore.Datastore, scheduler Scheduler, saturationDetector SaturationDetector, config *Config) *Director {
 	return &Director{
-		datastore:           datastore,
-		scheduler:           scheduler,
-		saturationDetector:  saturationDetector,
-		preRequestPlugins:   config.preRequestPlugins,
-		postResponsePlugins: config.postResponsePlugins,
+		datastore:                datastore,
+		scheduler:                scheduler,
+		saturationDetector:       saturationDetector,
+		preRequestPlugins:        config.preRequestPlugins,
+		requestMutatorPlugins:    config.requestMutatorPlugins,
+		postResponsePlugins:      config.postResponsePlugins,
+		responseMutatorPlugins:   config.responseMutatorPlugins,
+		responseStreamingPlugins: config.responseStreamingPlugins,
+		responseCompletePlugins:  config.responseCompletePlugins,
+		outlierTrackerPlugins:    config.outlierTrackerPlugins,
+		modelProviderPlugins:     config.modelProviderPlugins,
+		injectStreamUsage:        config.injectStreamUsage,
+		objectiveBudgets:         newObjectiveBudgets(),
 	}
 }
 
 // Director orchestrates the request handling flow, including scheduling.
 type Director struct {
-	datastore           datastore.Datastore
-	scheduler           Scheduler
-	saturationDetector  SaturationDetector
-	preRequestPlugins   []PreRequest
-	postResponsePlugins []PostResponse
+	datastore                datastore.Datastore
+	scheduler                Scheduler
+	saturationDetector       SaturationDetector
+	preRequestPlugins        []PreRequest
+	requestMutatorPlugins    []RequestMutator
+	postResponsePlugins      []PostResponse
+	responseMutatorPlugins   []ResponseMutator
+	responseStreamingPlugins []ResponseStreaming
+	responseCompletePlugins  []ResponseComplete
+	outlierTrackerPlugins    []OutlierTracker
+	modelProviderPlugins     []ModelProvider
+	objectiveBudgets         *objectiveBudgets
+	// injectStreamUsage is set if the usage of streamed responses is requested on behalf of the clients.
+	injectStreamUsage bool
 	// we just need a pointer to an int variable since priority is a pointer in InferenceObjective
 	// no need to set this in the constructor, since the value we want is the default int val
 	// and value types cannot be nil
@@ -80,32 +139,59 @@ type Director struct {
 //  1. Parses request details.
 //  2. Calls admitRequest for admission control.
 //  3. Calls Scheduler.Schedule if request is approved.
-//  4. Calls prepareRequest to populate RequestContext with result and call PreRequest plugins.
+//  4. Calls prepareRequest to populate RequestContext with result and call PreRequest and RequestMutator plugins.
 //
 // It always returns the requestContext even in the error case, as the request context is used in error handling.
-func (d *Director) HandleRequest(ctx context.Context, reqCtx *handlers.RequestContext) (*handlers.RequestContext, error) {
+func (d *Director) HandleRequest(ctx context.Context, reqCtx *handlers.RequestContext) (_ *handlers.RequestContext, retErr error) {
 	logger := log.FromContext(ctx)
 
 	// --- 1. Parse Request, Resolve Target Models, and Determine Parameters ---
 	requestBodyMap := reqCtx.Request.Body
 	var ok bool
 	reqCtx.IncomingModelName, ok = requestBodyMap["model"].(string)
-
 	if !ok {
+		// Some protocols, such as the KServe Open Inference Protocol, carry the model in the path.
+		reqCtx.IncomingModelName = requtil.ModelFromPath(reqCtx.Request.Headers[requtil.PathHeaderKey])
+	}
+
+	if reqCtx.IncomingModelName == "" {
 		return reqCtx, errutil.Error{Code: errutil.BadRequest, Msg: "model not found in request body"}
 	}
 	if reqCtx.TargetModelName == "" {
 		// Default to incoming model name
 		reqCtx.TargetModelName = reqCtx.IncomingModelName
 	}
-	reqCtx.Request.Body["model"] = reqCtx.TargetModelName
+	if ok {
+		reqCtx.Request.Body["model"] = reqCtx.TargetModelName
+	} else if reqCtx.TargetModelName != reqCtx.IncomingModelName {
+		// The model is rewritten in the path, which is sent back with the other request headers.
+		path := reqCtx.Request.Headers[requtil.PathHeaderKey]
+		reqCtx.Request.Headers[requtil.PathHeaderKey] = requtil.WithModelInPath(path, reqCtx.TargetModelName)
+	}
 
-	requestData, err := requtil.ExtractRequestData(reqCtx.Request.Body)
+	if d.injectStreamUsage {
+		d.injectStreamUsageOption(ctx, reqCtx)
+	}
+
+	requestData, err := requtil.ExtractRequestData(reqCtx.Request.Headers, reqCtx.Request.Body)
 	if err != nil {
 		return reqCtx, errutil.Error{Code: errutil.BadRequest, Msg: fmt.Errorf("failed to extract request data: %w", err).Error()}
 	}
 
 	infObjective := d.datastore.ObjectiveGet(reqCtx.ObjectiveKey)
+	if infObjective == nil && reqCtx.ObjectiveKey == "" {
+		// A request that doesn't name an objective gets the objective its model and headers match, if any.
+		if infObjective = d.datastore.ObjectiveMatch(reqCtx.IncomingModelName, reqCtx.Request.Headers); infObjective != nil {
+			reqCtx.ObjectiveKey = infObjective.Name
+		}
+	}
+	if infObjective != nil {
+		// The usage of the objective is reported in its status.
+		objectiveName := infObjective.Name
+		defer func() {
+			d.datastore.ObjectiveRecordRequest(objectiveName, retErr != nil)
+		}()
+	}
 	if infObjective == nil {
 		logger.V(logutil.VERBOSE).Info("No associated InferenceObjective found, using default", "objectiveKey", reqCtx.ObjectiveKey)
 		infObjective = &v1alpha2.InferenceObjective{
@@ -118,6 +204,11 @@ func (d *Director) HandleRequest(ctx context.Context, reqCtx *handlers.RequestCo
 		infObjective.Spec.Priority = &d.defaultPriority
 	}
 
+	// The OpenAI and Anthropic APIs carry the model in the body, unlike KServe, whose body has no output limit.
+	if err := enforceTokenLimits(infObjective, requestData, reqCtx.Request.Body, ok); err != nil {
+		return reqCtx, err
+	}
+
 	// Prepare LLMRequest (needed for both saturation detection and Scheduler)
 	reqCtx.SchedulingRequest = &schedulingtypes.LLMRequest{
 		RequestId:   reqCtx.Request.Headers[requtil.RequestIdHeaderKey],
@@ -125,6 +216,14 @@ func (d *Director) HandleRequest(ctx context.Context, reqCtx *handlers.RequestCo
 		Data:        requestData,
 		Headers:     reqCtx.Request.Headers,
 	}
+	if slos := infObjective.Spec.SLOs; slos != nil {
+		if slos.TimeToFirstToken != nil {
+			reqCtx.SchedulingRequest.TTFTSLO = slos.TimeToFirstToken.Duration
+		}
+		if slos.EndToEndLatency != nil {
+			reqCtx.SchedulingRequest.E2ELatencySLO = slos.EndToEndLatency.Duration
+		}
+	}
 
 	logger = logger.WithValues("objectiveKey", reqCtx.ObjectiveKey, "incomingModelName", reqCtx.IncomingModelName, "targetModelName", reqCtx.TargetModelName, "priority", infObjective.Spec.Priority)
 
@@ -132,20 +231,48 @@ func (d *Director) HandleRequest(ctx context.Context, reqCtx *handlers.RequestCo
 	logger.V(logutil.DEBUG).Info("LLM request assembled")
 
 	// --- 2. Admission Control check --
-	if err := d.admitRequest(ctx, *infObjective.Spec.Priority, reqCtx.FairnessID); err != nil {
+	if err := d.admitRequest(ctx, *infObjective.Spec.Priority, reqCtx.FairnessID, reqCtx.TargetModelName); err != nil {
 		return reqCtx, err
 	}
 
 	// --- 3. Call Scheduler (with the relevant candidate pods) ---
 	candidatePods := d.getCandidatePodsForScheduling(ctx, reqCtx.Request.Metadata)
+	candidatePods = d.excludeFailedEndpoints(ctx, reqCtx.Request.Metadata, candidatePods)
 	if len(candidatePods) == 0 {
 		return reqCtx, errutil.Error{Code: errutil.ServiceUnavailable, Msg: "failed to find candidate pods for serving the request"}
 	}
+	if !slices.ContainsFunc(candidatePods, func(pod schedulingtypes.Pod) bool {
+		return models.CanServe(pod.GetAttributes(), reqCtx.TargetModelName)
+	}) && !d.runModelProviderPlugins(ctx, reqCtx.TargetModelName) {
+		return reqCtx, errutil.Error{Code: errutil.ModelNotFound, Msg: fmt.Sprintf("model %s is not served by any endpoint", reqCtx.TargetModelName)}
+	}
+	if reservation, ok := d.saturationDetector.(AdapterSlotReservation); ok {
+		candidatePods = reservation.PodsWithAdapterSlot(ctx, reqCtx.TargetModelName, candidatePods)
+		if len(candidatePods) == 0 {
+			return reqCtx, errutil.Error{Code: errutil.InferencePoolResourceExhausted,
+				Msg: fmt.Sprintf("no endpoint has an unreserved adapter slot for model %s", reqCtx.TargetModelName)}
+		}
+	}
 	result, err := d.scheduler.Schedule(ctx, reqCtx.SchedulingRequest, candidatePods)
 	if err != nil {
 		return reqCtx, errutil.Error{Code: errutil.InferencePoolResourceExhausted, Msg: fmt.Errorf("failed to find target pod: %w", err).Error()}
 	}
 
+	// The budget of the objective is only spent by the requests that passed every check that can reject them.
+	concurrencySlot, err := d.objectiveBudgets.acquire(infObjective)
+	if err != nil {
+		return reqCtx, err
+	}
+	if concurrencySlot {
+		// the slot is released when the response is complete, or here if the request is not sent.
+		reqCtx.ObjectiveConcurrencySlot = true
+		defer func() {
+			if retErr != nil {
+				d.releaseObjectiveConcurrencySlot(reqCtx)
+			}
+		}()
+	}
+
 	// --- 4. Prepare Request (Populates RequestContext and call PreRequest plugins) ---
 	// Insert target endpoint to instruct Envoy to route requests to the specified target pod and attach the port number.
 	// Invoke PreRequest registered plugins.
@@ -157,25 +284,71 @@ func (d *Director) HandleRequest(ctx context.Context, reqCtx *handlers.RequestCo
 	return reqCtx, nil
 }
 
+// injectStreamUsageOption sets `stream_options.include_usage` on a streaming completions or chat-completions
+// request, so that the model server reports the token usage of the response in a final chunk. If the client
+// didn't request the usage itself, the request context is marked for the handler to strip that chunk out of
+// the response the client receives.
+func (d *Director) injectStreamUsageOption(ctx context.Context, reqCtx *handlers.RequestContext) {
+	path, _, _ := strings.Cut(reqCtx.Request.Headers[requtil.PathHeaderKey], "?")
+	if !strings.HasSuffix(path, requtil.CompletionsPath) && !strings.HasSuffix(path, requtil.ChatCompletionsPath) {
+		return
+	}
+	if stream, _ := reqCtx.Request.Body["stream"].(bool); !stream {
+		return
+	}
+
+	streamOptions, ok := reqCtx.Request.Body["stream_options"].(map[string]any)
+	if !ok {
+		if reqCtx.Request.Body["stream_options"] != nil {
+			return // leave malformed options to the model server to reject
+		}
+		streamOptions = map[string]any{}
+	}
+	if includeUsage, _ := streamOptions["include_usage"].(bool); includeUsage {
+		return
+	}
+	streamOptions["include_usage"] = true
+	reqCtx.Request.Body["stream_options"] = streamOptions
+	reqCtx.StreamUsageInjected = true
+	log.FromContext(ctx).V(logutil.TRACE).Info("Injected stream usage option into the request")
+}
+
 // admitRequest handles admission control to decide whether or not to accept the request
-// based on the request priority and system saturation state.
-func (d *Director) admitRequest(ctx context.Context, requestPriority int, fairnessID string) error {
+// based on the request priority and system saturation state, for the target model if the saturation detector
+// supports it. Requests with a non-negative priority are only shed by an AdmissionDetector.
+func (d *Director) admitRequest(ctx context.Context, requestPriority int, fairnessID string, targetModel string) error {
 	logger := log.FromContext(ctx)
 
 	logger.V(logutil.TRACE).Info("Entering Flow Control", "priority", requestPriority, "fairnessID", fairnessID)
 
-	// This will be removed in favor of a more robust implementation (Flow Control) in the very near future.
-	// TODO: Make this a configurable value.
-	// Tracking issue https://github.com/kubernetes-sigs/gateway-api-inference-extension/issues/1347
-	if requestPriority >= 0 {
-		logger.V(logutil.TRACE).Info("Non-sheddable request bypassing saturation check.")
-		return nil
+	var saturated bool
+	var retryAfter time.Duration
+	if admissionDetector, ok := d.saturationDetector.(AdmissionDetector); ok {
+		var admitted bool
+		admitted, retryAfter = admissionDetector.Admit(ctx, AdmissionRequest{Priority: requestPriority, TargetModel: targetModel})
+		saturated = !admitted
+	} else {
+		// This will be removed in favor of a more robust implementation (Flow Control) in the very near future.
+		// Tracking issue https://github.com/kubernetes-sigs/gateway-api-inference-extension/issues/1347
+		if requestPriority >= 0 {
+			logger.V(logutil.TRACE).Info("Non-sheddable request bypassing saturation check.")
+			return nil
+		}
+		if modelDetector, ok := d.saturationDetector.(ModelSaturationDetector); ok {
+			saturated = modelDetector.IsModelSaturated(ctx, targetModel)
+		} else {
+			saturated = d.saturationDetector.IsSaturated(ctx) // Assuming non-nil Saturation Detector
+		}
+		if estimator, ok := d.saturationDetector.(RetryAfterEstimator); ok && saturated {
+			retryAfter = estimator.RetryAfter(ctx)
+		}
 	}
-
-	if d.saturationDetector.IsSaturated(ctx) { // Assuming non-nil Saturation Detector
+	if saturated {
+		metrics.RecordShedRequest(requestPriority, targetModel)
 		return errutil.Error{
-			Code: errutil.InferencePoolResourceExhausted,
-			Msg:  "system saturated, sheddable request dropped",
+			Code:       errutil.InferencePoolResourceExhausted,
+			Msg:        "system saturated, sheddable request dropped",
+			RetryAfter: retryAfter,
 		}
 	}
 
@@ -185,6 +358,7 @@ func (d *Director) admitRequest(ctx context.Context, requestPriority int, fairne
 // getCandidatePodsForScheduling gets the list of relevant endpoints for the scheduling cycle from the datastore.
 // according to EPP protocol, if "x-gateway-destination-endpoint-subset" is set on the request metadata and specifies
 // a subset of endpoints, only these endpoints will be considered as candidates for the scheduler.
+// Endpoints whose probed health is not healthy are never candidates.
 // Snapshot pod metrics from the datastore to:
 // 1. Reduce concurrent access to the datastore.
 // 2. Ensure consistent data during the scheduling operation of a request between all scheduling cycles.
@@ -193,13 +367,13 @@ func (d *Director) getCandidatePodsForScheduling(ctx context.Context, requestMet
 
 	subsetMap, found := requestMetadata[metadata.SubsetFilterNamespace].(map[string]any)
 	if !found {
-		return d.toSchedulerPodMetrics(d.datastore.PodList(backendmetrics.AllPodsPredicate))
+		return d.toSchedulerPodMetrics(d.datastore.PodList(servingPodsPredicate))
 	}
 
 	// Check if endpoint key is present in the subset map and ensure there is at least one value
 	endpointSubsetList, found := subsetMap[metadata.SubsetFilterKey].([]any)
 	if !found {
-		return d.toSchedulerPodMetrics(d.datastore.PodList(backendmetrics.AllPodsPredicate))
+		return d.toSchedulerPodMetrics(d.datastore.PodList(servingPodsPredicate))
 	} else if len(endpointSubsetList) == 0 {
 		loggerTrace.Info("found empty subset filter in request metadata, filtering all pods")
 		return []schedulingtypes.Pod{}
@@ -218,7 +392,7 @@ func (d *Director) getCandidatePodsForScheduling(ctx context.Context, requestMet
 	podFitleredList := d.datastore.PodList(func(pm backendmetrics.PodMetrics) bool {
 		podTotalCount++
 		if _, found := endpoints[pm.GetPod().Address]; found {
-			return true
+			return servingPodsPredicate(pm)
 		}
 		return false
 	})
@@ -228,7 +402,48 @@ func (d *Director) getCandidatePodsForScheduling(ctx context.Context, requestMet
 	return d.toSchedulerPodMetrics(podFitleredList)
 }
 
-// prepareRequest populates the RequestContext and calls the registered PreRequest plugins
+// excludeFailedEndpoints detects a request that the gateway retries after the endpoints it was routed to failed to
+// serve it. The gateway reports a retried attempt in the request metadata, which, unlike the request headers, the
+// client can't set, with an attempt count above 1 and the endpoints the previous attempts were tried on. The pods
+// of these endpoints are reported to the OutlierTracker plugins as failed, and are excluded from the candidate pods,
+// unless no other candidate pod remains.
+func (d *Director) excludeFailedEndpoints(ctx context.Context, requestMetadata map[string]any, candidatePods []schedulingtypes.Pod) []schedulingtypes.Pod {
+	attempt, found := requestMetadata[metadata.AttemptNamespace].(map[string]any)
+	if !found {
+		return candidatePods
+	}
+	if count, _ := attempt[metadata.AttemptCountKey].(float64); count <= 1 {
+		return candidatePods
+	}
+	attemptedEndpoints, _ := attempt[metadata.AttemptedEndpointsKey].([]any)
+	failedAddresses := map[string]bool{}
+	for _, endpoint := range attemptedEndpoints {
+		if endpoint, ok := endpoint.(string); ok {
+			failedAddresses[endpointAddress(endpoint)] = true
+		}
+	}
+	if len(failedAddresses) == 0 {
+		return candidatePods
+	}
+
+	remainingPods := make([]schedulingtypes.Pod, 0, len(candidatePods))
+	for _, pod := range candidatePods {
+		if failedAddresses[pod.GetPod().Address] {
+			d.runOutlierTrackerPlugins(ctx, pod.GetPod(), false)
+			continue
+		}
+		remainingPods = append(remainingPods, pod)
+	}
+	log.FromContext(ctx).V(logutil.VERBOSE).Info("Retried request, excluding failed endpoints", "attemptedEndpoints", attemptedEndpoints,
+		"candidateCount", len(candidatePods), "remainingCount", len(remainingPods))
+
+	if len(remainingPods) == 0 {
+		return candidatePods // retrying on a failed endpoint is better than failing the request
+	}
+	return remainingPods
+}
+
+// prepareRequest populates the RequestContext and calls the registered PreRequest and RequestMutator plugins
 // for allowing plugging customized logic based on the scheduling result.
 func (d *Director) prepareRequest(ctx context.Context, reqCtx *handlers.RequestContext, result *schedulingtypes.SchedulingResult) (*handlers.RequestContext, error) {
 	logger := log.FromContext(ctx)
@@ -254,39 +469,216 @@ func (d *Director) prepareRequest(ctx context.Context, reqCtx *handlers.RequestC
 		targetEndpoints = append(targetEndpoints, curEndpoint)
 	}
 
+	// fallback endpoints are given in order, for the gateway to fail over to if the target endpoints fail.
+	fallbackEndpoints := []string{}
+	for _, pod := range result.ProfileResults[result.PrimaryProfileName].FallbackPods {
+		fallbackEndpoints = append(fallbackEndpoints, net.JoinHostPort(pod.GetPod().Address, strconv.Itoa(targetPort)))
+	}
+
 	multiEndpointString := strings.Join(targetEndpoints, ",")
-	logger.V(logutil.VERBOSE).Info("Request handled", "objectiveKey", reqCtx.ObjectiveKey, "incomingModelName", reqCtx.IncomingModelName, "targetModel", reqCtx.TargetModelName, "endpoint", multiEndpointString)
+	fallbackEndpointString := strings.Join(fallbackEndpoints, ",")
+	logger.V(logutil.VERBOSE).Info("Request handled", "objectiveKey", reqCtx.ObjectiveKey, "incomingModelName", reqCtx.IncomingModelName, "targetModel", reqCtx.TargetModelName,
+		"endpoint", multiEndpointString, "fallbackEndpoint", fallbackEndpointString)
 
 	reqCtx.TargetPod = targetPods[0]
 	reqCtx.TargetEndpoint = multiEndpointString
+	reqCtx.FallbackEndpoint = fallbackEndpointString
 
 	d.runPreRequestPlugins(ctx, reqCtx.SchedulingRequest, result, targetPort)
+	if err := d.runRequestMutatorPlugins(ctx, reqCtx, result); err != nil {
+		return reqCtx, err
+	}
 
 	return reqCtx, nil
 }
 
+// servingPodsPredicate excludes the pods whose model server was probed and found not healthy.
+func servingPodsPredicate(pm backendmetrics.PodMetrics) bool {
+	return health.IsServing(pm)
+}
+
 func (d *Director) toSchedulerPodMetrics(pods []backendmetrics.PodMetrics) []schedulingtypes.Pod {
 	pm := make([]schedulingtypes.Pod, len(pods))
 	for i, pod := range pods {
-		pm[i] = &schedulingtypes.PodMetrics{Pod: pod.GetPod().Clone(), MetricsState: pod.GetMetrics().Clone()}
+		pm[i] = &schedulingtypes.PodMetrics{Pod: pod.GetPod().Clone(), MetricsState: pod.GetMetrics().Clone(), Attributes: cloneAttributes(pod)}
 	}
 
 	return pm
 }
 
+// cloneAttributes returns a copy of the extended attributes of the pod, or nil if it has none.
+func cloneAttributes(pod backendmetrics.PodMetrics) datalayer.AttributeMap {
+	keys := pod.Keys()
+	if len(keys) == 0 {
+		return nil
+	}
+	attributes := datalayer.NewAttributes()
+	for _, key := range keys {
+		if value, found := pod.Get(key); found { // Get returns a clone of the value
+			attributes.Put(key, value)
+		}
+	}
+	return attributes
+}
+
 func (d *Director) HandleResponse(ctx context.Context, reqCtx *handlers.RequestContext) (*handlers.RequestContext, error) {
 	response := &Response{
 		RequestId: reqCtx.Request.Headers[requtil.RequestIdHeaderKey],
 		Headers:   reqCtx.Response.Headers,
 	}
 
-	// TODO: to extend fallback functionality, handle cases where target pod is unavailable
-	// https://github.com/kubernetes-sigs/gateway-api-inference-extension/issues/1224
+	d.recordEndpointOutcomes(ctx, reqCtx)
+
+	d.runResponseMutatorPlugins(ctx, reqCtx.SchedulingRequest, response, reqCtx.TargetPod)
+	if len(d.responseMutatorPlugins) > 0 {
+		// The response mutators may change the length of the body, which is not known yet, so the body is sent
+		// without its original length.
+		delete(response.Headers, "content-length")
+	}
+	reqCtx.Response.Headers = response.Headers
+
 	d.runPostResponsePlugins(ctx, reqCtx.SchedulingRequest, response, reqCtx.TargetPod)
 
 	return reqCtx, nil
 }
 
+// recordEndpointOutcomes reports the outcome of serving the request to the OutlierTracker plugins. If the gateway
+// reports that the request was served by another endpoint than the first destination endpoint, i.e. it failed over,
+// the endpoints it tried before are reported as failed and the target pod is updated to the pod that served the request.
+func (d *Director) recordEndpointOutcomes(ctx context.Context, reqCtx *handlers.RequestContext) {
+	if served := servedEndpoint(reqCtx.Request.Metadata); served != "" {
+		attemptedEndpoints := strings.Split(reqCtx.TargetEndpoint, ",")
+		if reqCtx.FallbackEndpoint != "" {
+			attemptedEndpoints = append(attemptedEndpoints, strings.Split(reqCtx.FallbackEndpoint, ",")...)
+		}
+		servedAddress := endpointAddress(served)
+		servedIndex := slices.IndexFunc(attemptedEndpoints, func(endpoint string) bool { return endpointAddress(endpoint) == servedAddress })
+		if servedIndex > 0 {
+			log.FromContext(ctx).V(logutil.VERBOSE).Info("Request failed over", "servedEndpoint", served, "failedEndpoints", attemptedEndpoints[:servedIndex])
+			for _, endpoint := range attemptedEndpoints[:servedIndex] {
+				if pod := d.getPodByAddress(endpointAddress(endpoint)); pod != nil {
+					d.runOutlierTrackerPlugins(ctx, pod, false)
+				}
+			}
+			if pod := d.getPodByAddress(servedAddress); pod != nil {
+				reqCtx.TargetPod = pod
+			}
+		}
+	}
+
+	if reqCtx.TargetPod != nil {
+		d.runOutlierTrackerPlugins(ctx, reqCtx.TargetPod, !isServerError(reqCtx.Response.Headers))
+	}
+}
+
+// servedEndpoint returns the endpoint that served the request, as reported by the gateway in the response metadata.
+func servedEndpoint(requestMetadata map[string]any) string {
+	endpointMetadata, found := requestMetadata[metadata.DestinationEndpointNamespace].(map[string]any)
+	if !found {
+		return ""
+	}
+	served, _ := endpointMetadata[metadata.DestinationEndpointServedKey].(string)
+	return served
+}
+
+// isServerError returns true if the status of the response headers is a server error.
+func isServerError(headers map[string]string) bool {
+	status, found := headers[":status"]
+	if !found {
+		status = headers["status"]
+	}
+	code, err := strconv.Atoi(status)
+	return err == nil && code >= 500
+}
+
+// endpointAddress returns the address of the endpoint, which is formatted as "<address>:<port>" (ex. "10.0.1.0:8080").
+func endpointAddress(endpoint string) string {
+	endpoint = strings.TrimSpace(endpoint)
+	address, _, err := net.SplitHostPort(endpoint)
+	if err != nil {
+		return endpoint
+	}
+	return address
+}
+
+func (d *Director) getPodByAddress(address string) *backend.Pod {
+	pods := d.datastore.PodList(func(pm backendmetrics.PodMetrics) bool {
+		return pm.GetPod().Address == address
+	})
+	if len(pods) == 0 {
+		return nil
+	}
+	return pods[0].GetPod()
+}
+
+// HandleResponseBody calls the registered ResponseMutator plugins with a chunk of the response body, which is
+// the whole body if the response is not streamed, and returns the mutated chunk. The mutated chunks of streamed
+// responses are then passed to the registered ResponseStreaming plugins.
+func (d *Director) HandleResponseBody(ctx context.Context, reqCtx *handlers.RequestContext, body []byte, isStreaming bool, endOfStream bool) []byte {
+	if len(d.responseMutatorPlugins) == 0 && (!isStreaming || len(d.responseStreamingPlugins) == 0) {
+		return body
+	}
+	response := d.newResponse(reqCtx)
+	response.Headers = nil
+	response.Body = string(body)
+	response.IsStreaming = isStreaming
+	response.EndOfStream = endOfStream
+
+	d.runResponseMutatorPlugins(ctx, reqCtx.SchedulingRequest, response, reqCtx.TargetPod)
+	if isStreaming {
+		d.runResponseStreamingPlugins(ctx, reqCtx.SchedulingRequest, response, reqCtx.TargetPod)
+	}
+	return []byte(response.Body)
+}
+
+// HandleResponseComplete calls the registered ResponseComplete plugins once the response of a request is
+// complete, or once the request was cancelled before its response was complete. The body is the whole body
+// of a response that is not streamed, and empty for a streamed response.
+func (d *Director) HandleResponseComplete(ctx context.Context, reqCtx *handlers.RequestContext, body []byte, cancelled bool) {
+	d.releaseObjectiveConcurrencySlot(reqCtx)
+	if len(d.responseCompletePlugins) == 0 {
+		return
+	}
+	response := d.newResponse(reqCtx)
+	response.Body = string(body)
+	response.IsStreaming = reqCtx.ModelServerStreaming()
+	response.EndOfStream = !cancelled
+	response.ResponseCompleteTimestamp = reqCtx.ResponseCompleteTimestamp
+	response.Cancelled = cancelled
+
+	loggerDebug := log.FromContext(ctx).V(logutil.DEBUG)
+	for _, plugin := range d.responseCompletePlugins {
+		loggerDebug.Info("Running response-complete plugin", "plugin", plugin.TypedName())
+		before := time.Now()
+		plugin.ResponseComplete(ctx, reqCtx.SchedulingRequest, response, reqCtx.TargetPod)
+		metrics.RecordPluginProcessingLatency(ResponseCompleteExtensionPoint, plugin.TypedName().Type, plugin.TypedName().Name, time.Since(before))
+		loggerDebug.Info("Completed running response-complete plugin successfully", "plugin", plugin.TypedName())
+	}
+}
+
+// releaseObjectiveConcurrencySlot releases the slot of the concurrency budget of its objective the request holds,
+// if any.
+func (d *Director) releaseObjectiveConcurrencySlot(reqCtx *handlers.RequestContext) {
+	if reqCtx.ObjectiveConcurrencySlot {
+		reqCtx.ObjectiveConcurrencySlot = false
+		d.objectiveBudgets.release(reqCtx.ObjectiveKey)
+	}
+}
+
+// newResponse returns the Response passed to the response plugins, with the information of the response known so far.
+func (d *Director) newResponse(reqCtx *handlers.RequestContext) *Response {
+	response := &Response{
+		RequestId:                reqCtx.Request.Headers[requtil.RequestIdHeaderKey],
+		Usage:                    reqCtx.Usage,
+		RequestReceivedTimestamp: reqCtx.RequestReceivedTimestamp,
+		FirstTokenTimestamp:      reqCtx.FirstTokenTimestamp(),
+	}
+	if reqCtx.Response != nil {
+		response.Headers = reqCtx.Response.Headers
+	}
+	return response
+}
+
 func (d *Director) GetRandomPod() *backend.Pod {
 	pods := d.datastore.PodList(backendmetrics.AllPodsPredicate)
 	if len(pods) == 0 {
@@ -309,6 +701,32 @@ func (d *Director) runPreRequestPlugins(ctx context.Context, request *scheduling
 	}
 }
 
+// runRequestMutatorPlugins calls the RequestMutator plugins in order, with the request body and headers.
+func (d *Director) runRequestMutatorPlugins(ctx context.Context, reqCtx *handlers.RequestContext, schedulingResult *schedulingtypes.SchedulingResult) error {
+	if len(d.requestMutatorPlugins) == 0 {
+		return nil
+	}
+	loggerDebug := log.FromContext(ctx).V(logutil.DEBUG)
+	mutable := &Request{Headers: reqCtx.Request.Headers, Body: reqCtx.Request.Body}
+	for _, plugin := range d.requestMutatorPlugins {
+		loggerDebug.Info("Running request-mutator plugin", "plugin", plugin.TypedName())
+		before := time.Now()
+		err := plugin.MutateRequest(ctx, reqCtx.SchedulingRequest, schedulingResult, mutable)
+		metrics.RecordPluginProcessingLatency(RequestMutatorExtensionPoint, plugin.TypedName().Type, plugin.TypedName().Name, time.Since(before))
+		if err != nil {
+			var e errutil.Error
+			if !errors.As(err, &e) {
+				e = errutil.Error{Code: errutil.Internal, Msg: fmt.Sprintf("request-mutator plugin %s failed: %v", plugin.TypedName(), err)}
+			}
+			return e
+		}
+		loggerDebug.Info("Completed running request-mutator plugin successfully", "plugin", plugin.TypedName())
+	}
+	reqCtx.Request.Headers = mutable.Headers
+	reqCtx.Request.Body = mutable.Body
+	return nil
+}
+
 func (d *Director) runPostResponsePlugins(ctx context.Context, request *schedulingtypes.LLMRequest, response *Response, targetPod *backend.Pod) {
 	loggerDebug := log.FromContext(ctx).V(logutil.DEBUG)
 	for _, plugin := range d.postResponsePlugins {
@@ -319,3 +737,56 @@ func (d *Director) runPostResponsePlugins(ctx context.Context, request *scheduli
 		loggerDebug.Info("Completed running post-response plugin successfully", "plugin", plugin.TypedName())
 	}
 }
+
+func (d *Director) runResponseMutatorPlugins(ctx context.Context, request *schedulingtypes.LLMRequest, response *Response, targetPod *backend.Pod) {
+	loggerDebug := log.FromContext(ctx).V(logutil.DEBUG)
+	for _, plugin := range d.responseMutatorPlugins {
+		loggerDebug.Info("Running response-mutator plugin", "plugin", plugin.TypedName())
+		before := time.Now()
+		plugin.MutateResponse(ctx, request, response, targetPod)
+		metrics.RecordPluginProcessingLatency(ResponseMutatorExtensionPoint, plugin.TypedName().Type, plugin.TypedName().Name, time.Since(before))
+		loggerDebug.Info("Completed running response-mutator plugin successfully", "plugin", plugin.TypedName())
+	}
+}
+
+func (d *Director) runResponseStreamingPlugins(ctx context.Context, request *schedulingtypes.LLMRequest, response *Response, targetPod *backend.Pod) {
+	loggerTrace := log.FromContext(ctx).V(logutil.TRACE)
+	for _, plugin := range d.responseStreamingPlugins {
+		loggerTrace.Info("Running response-streaming plugin", "plugin", plugin.TypedName())
+		before := time.Now()
+		plugin.ResponseStreaming(ctx, request, response, targetPod)
+		metrics.RecordPluginProcessingLatency(ResponseStreamingExtensionPoint, plugin.TypedName().Type, plugin.TypedName().Name, time.Since(before))
+		loggerTrace.Info("Completed running response-streaming plugin successfully", "plugin", plugin.TypedName())
+	}
+}
+
+func (d *Director) runOutlierTrackerPlugins(ctx context.Context, pod *backend.Pod, success bool) {
+	loggerDebug := log.FromContext(ctx).V(logutil.DEBUG)
+	for _, plugin := range d.outlierTrackerPlugins {
+		loggerDebug.Info("Running outlier-tracker plugin", "plugin", plugin.TypedName(), "pod", pod.NamespacedName, "success", success)
+		before := time.Now()
+		if success {
+			plugin.RecordSuccess(ctx, pod)
+		} else {
+			plugin.RecordFailure(ctx, pod)
+		}
+		metrics.RecordPluginProcessingLatency(OutlierTrackerExtensionPoint, plugin.TypedName().Type, plugin.TypedName().Name, time.Since(before))
+		loggerDebug.Info("Completed running outlier-tracker plugin successfully", "plugin", plugin.TypedName())
+	}
+}
+
+// runModelProviderPlugins returns true if any of the ModelProvider plugins provides the model on demand.
+func (d *Director) runModelProviderPlugins(ctx context.Context, model string) bool {
+	loggerDebug := log.FromContext(ctx).V(logutil.DEBUG)
+	for _, plugin := range d.modelProviderPlugins {
+		loggerDebug.Info("Running model-provider plugin", "plugin", plugin.TypedName(), "model", model)
+		before := time.Now()
+		provided := plugin.ProvidesModel(ctx, model)
+		metrics.RecordPluginProcessingLatency(ModelProviderExtensionPoint, plugin.TypedName().Type, plugin.TypedName().Name, time.Since(before))
+		if provided {
+			loggerDebug.Info("Model is provided on demand", "plugin", plugin.TypedName(), "model", model)
+			return true
+		}
+	}
+	return false
+}
@@ -20,6 +20,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -31,11 +34,16 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	v1 "sigs.k8s.io/gateway-api-inference-extension/api/v1"
+	"sigs.k8s.io/gateway-api-inference-extension/apix/v1alpha2"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend"
 	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datalayer"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datalayer/health"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datalayer/models"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datastore"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/handlers"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/metadata"
@@ -57,6 +65,46 @@ func (m *mockSaturationDetector) IsSaturated(_ context.Context) bool {
 	return m.isSaturated
 }
 
+type mockModelSaturationDetector struct {
+	mockSaturationDetector
+	saturatedModels []string
+}
+
+func (m *mockModelSaturationDetector) IsModelSaturated(_ context.Context, model string) bool {
+	return slices.Contains(m.saturatedModels, model)
+}
+
+// mockAdmissionDetector sheds the requests with a priority up to shedUpTo, estimating a longer retry for lower
+// priorities.
+type mockAdmissionDetector struct {
+	mockSaturationDetector
+	shedUpTo int
+}
+
+func (m *mockAdmissionDetector) Admit(_ context.Context, request AdmissionRequest) (bool, time.Duration) {
+	if request.Priority > m.shedUpTo {
+		return true, 0
+	}
+	return false, time.Duration(1-request.Priority) * time.Second
+}
+
+// RetryAfter is not used when the detector decides the admission.
+func (m *mockAdmissionDetector) RetryAfter(_ context.Context) time.Duration {
+	return time.Minute
+}
+
+// mockAdapterSlotReservation filters out the pods without an unreserved adapter slot.
+type mockAdapterSlotReservation struct {
+	mockSaturationDetector
+	noSlotPods []string
+}
+
+func (m *mockAdapterSlotReservation) PodsWithAdapterSlot(_ context.Context, _ string, pods []schedulingtypes.Pod) []schedulingtypes.Pod {
+	return slices.DeleteFunc(slices.Clone(pods), func(pod schedulingtypes.Pod) bool {
+		return slices.Contains(m.noSlotPods, pod.GetPod().NamespacedName.Name)
+	})
+}
+
 type mockScheduler struct {
 	scheduleResults *schedulingtypes.SchedulingResult
 	scheduleErr     error
@@ -170,6 +218,7 @@ func TestDirector_HandleRequest(t *testing.T) {
 
 	tests := []struct {
 		name                   string
+		path                   string
 		reqBodyMap             map[string]any
 		mockSaturationDetector *mockSaturationDetector
 		inferenceObjectiveName string
@@ -177,6 +226,7 @@ func TestDirector_HandleRequest(t *testing.T) {
 		wantErrCode            string                   // Expected errutil code string
 		wantReqCtx             *handlers.RequestContext // Fields to check in the returned RequestContext
 		wantMutatedBodyModel   string                   // Expected model in reqCtx.Request.Body after PostDispatch
+		wantPath               string                   // Expected path of the request after target model resolution
 		targetModelName        string                   // Expected model name after target model resolution
 	}{
 		{
@@ -259,6 +309,54 @@ func TestDirector_HandleRequest(t *testing.T) {
 			inferenceObjectiveName: objectiveName,
 			targetModelName:        model,
 		},
+		{
+			name: "successful kserve request with the model in the path",
+			path: "/v2/models/" + model + "/infer",
+			reqBodyMap: map[string]any{
+				"inputs": []any{
+					map[string]any{"name": "text_input", "datatype": "BYTES", "shape": []any{1}, "data": []any{"critical prompt"}},
+				},
+			},
+			mockSaturationDetector: &mockSaturationDetector{isSaturated: false},
+			schedulerMockSetup: func(m *mockScheduler) {
+				m.scheduleResults = defaultSuccessfulScheduleResults
+			},
+			wantReqCtx: &handlers.RequestContext{
+				ObjectiveKey:    objectiveName,
+				TargetModelName: model,
+				TargetPod: &backend.Pod{
+					NamespacedName: types.NamespacedName{Namespace: "default", Name: "pod1"},
+					Address:        "192.168.1.100",
+				},
+				TargetEndpoint: "192.168.1.100:8000,192.168.2.100:8000,192.168.4.100:8000",
+			},
+			inferenceObjectiveName: objectiveName,
+		},
+		{
+			name: "successful kserve request with target model resolution",
+			path: "/v2/models/" + modelWithResolvedTarget + "/versions/1/infer",
+			reqBodyMap: map[string]any{
+				"inputs": []any{
+					map[string]any{"name": "text_input", "datatype": "BYTES", "shape": []any{1}, "data": []any{"prompt"}},
+				},
+			},
+			mockSaturationDetector: &mockSaturationDetector{isSaturated: false},
+			schedulerMockSetup: func(m *mockScheduler) {
+				m.scheduleResults = defaultSuccessfulScheduleResults
+			},
+			wantReqCtx: &handlers.RequestContext{
+				ObjectiveKey:    objectiveNameResolve,
+				TargetModelName: "resolved-target-model-A",
+				TargetPod: &backend.Pod{
+					NamespacedName: types.NamespacedName{Namespace: "default", Name: "pod1"},
+					Address:        "192.168.1.100",
+				},
+				TargetEndpoint: "192.168.1.100:8000,192.168.2.100:8000,192.168.4.100:8000",
+			},
+			wantPath:               "/v2/models/resolved-target-model-A/versions/1/infer",
+			inferenceObjectiveName: objectiveNameResolve,
+			targetModelName:        "resolved-target-model-A",
+		},
 		{
 			name: "successful completions request (sheddable, not saturated)",
 			reqBodyMap: map[string]any{
@@ -405,6 +503,9 @@ func TestDirector_HandleRequest(t *testing.T) {
 				ObjectiveKey:    test.inferenceObjectiveName,
 				TargetModelName: test.targetModelName,
 			}
+			if test.path != "" {
+				reqCtx.Request.Headers[requtil.PathHeaderKey] = test.path
+			}
 			// Deep copy the body map.
 			for k, v := range test.reqBodyMap {
 				reqCtx.Request.Body[k] = v
@@ -436,11 +537,78 @@ func TestDirector_HandleRequest(t *testing.T) {
 				assert.Equal(t, test.wantMutatedBodyModel, returnedReqCtx.Request.Body["model"],
 					"Mutated reqCtx.Request.Body model mismatch")
 			}
+			if test.wantPath != "" {
+				assert.Equal(t, test.wantPath, returnedReqCtx.Request.Headers[requtil.PathHeaderKey], "Request path mismatch")
+				assert.NotContains(t, returnedReqCtx.Request.Body, "model", "the model should not be added to the body")
+			}
 		})
 	}
 }
 
 // TestGetCandidatePodsForScheduling is testing getCandidatePodsForScheduling and more specifically the functionality of SubsetFilter.
+func TestDirector_AdmitRequestModelSaturation(t *testing.T) {
+	detector := &mockModelSaturationDetector{saturatedModels: []string{"long-tail-lora"}}
+	director := NewDirectorWithConfig(nil, &mockScheduler{}, detector, NewConfig())
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		priority    int
+		model       string
+		wantErrCode string
+	}{
+		{name: "sheddable request for a saturated model", priority: -1, model: "long-tail-lora", wantErrCode: errutil.InferencePoolResourceExhausted},
+		{name: "sheddable request for another model", priority: -1, model: "premium-lora"},
+		{name: "non-sheddable request for a saturated model", priority: 0, model: "long-tail-lora"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := director.admitRequest(ctx, test.priority, "", test.model)
+			if test.wantErrCode == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var e errutil.Error
+			if assert.ErrorAs(t, err, &e, "Error should be of type errutil.Error") {
+				assert.Equal(t, test.wantErrCode, e.Code, "Error code mismatch")
+			}
+		})
+	}
+}
+
+func TestDirector_AdmitRequestAdmissionDetector(t *testing.T) {
+	// the generic IsSaturated signal is ignored when the detector decides the admission
+	detector := &mockAdmissionDetector{mockSaturationDetector: mockSaturationDetector{isSaturated: true}, shedUpTo: 0}
+	director := NewDirectorWithConfig(nil, &mockScheduler{}, detector, NewConfig())
+	ctx := context.Background()
+
+	tests := []struct {
+		name           string
+		priority       int
+		wantErrCode    string
+		wantRetryAfter time.Duration
+	}{
+		{name: "lowest tier is shed", priority: -2, wantErrCode: errutil.InferencePoolResourceExhausted, wantRetryAfter: 3 * time.Second},
+		{name: "non-negative priority is shed as a last resort", priority: 0, wantErrCode: errutil.InferencePoolResourceExhausted,
+			wantRetryAfter: time.Second},
+		{name: "higher priority is admitted", priority: 1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := director.admitRequest(ctx, test.priority, "", "model")
+			if test.wantErrCode == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var e errutil.Error
+			if assert.ErrorAs(t, err, &e, "Error should be of type errutil.Error") {
+				assert.Equal(t, test.wantErrCode, e.Code, "Error code mismatch")
+				assert.Equal(t, test.wantRetryAfter, e.RetryAfter, "Retry after mismatch")
+			}
+		})
+	}
+}
+
 func TestGetCandidatePodsForScheduling(t *testing.T) {
 	var makeFilterMetadata = func(data []any) map[string]any {
 		return map[string]any{
@@ -572,6 +740,82 @@ func TestGetCandidatePodsForScheduling(t *testing.T) {
 	}
 }
 
+func TestExcludeFailedEndpoints(t *testing.T) {
+	pod1 := &schedulingtypes.PodMetrics{Pod: &backend.Pod{NamespacedName: types.NamespacedName{Name: "pod1"}, Address: "10.0.0.1"}}
+	pod2 := &schedulingtypes.PodMetrics{Pod: &backend.Pod{NamespacedName: types.NamespacedName{Name: "pod2"}, Address: "10.0.0.2"}}
+	pod3 := &schedulingtypes.PodMetrics{Pod: &backend.Pod{NamespacedName: types.NamespacedName{Name: "pod3"}, Address: "10.0.0.3"}}
+	attempt := func(count float64, endpoints ...any) map[string]any {
+		return map[string]any{metadata.AttemptNamespace: map[string]any{
+			metadata.AttemptCountKey:       count,
+			metadata.AttemptedEndpointsKey: endpoints,
+		}}
+	}
+
+	tests := []struct {
+		name         string
+		metadata     map[string]any
+		candidates   []schedulingtypes.Pod
+		wantPods     []schedulingtypes.Pod
+		wantFailures []string
+	}{
+		{
+			name:       "no attempt metadata, no pods excluded",
+			metadata:   map[string]any{},
+			candidates: []schedulingtypes.Pod{pod1, pod2, pod3},
+			wantPods:   []schedulingtypes.Pod{pod1, pod2, pod3},
+		},
+		{
+			name:       "first attempt, no pods excluded",
+			metadata:   attempt(1, "10.0.0.1:8000"),
+			candidates: []schedulingtypes.Pod{pod1, pod2, pod3},
+			wantPods:   []schedulingtypes.Pod{pod1, pod2, pod3},
+		},
+		{
+			name:         "retry, failed endpoint excluded",
+			metadata:     attempt(2, "10.0.0.1:8000"),
+			candidates:   []schedulingtypes.Pod{pod1, pod2, pod3},
+			wantPods:     []schedulingtypes.Pod{pod2, pod3},
+			wantFailures: []string{"/pod1"},
+		},
+		{
+			name:         "retry, multiple failed endpoints excluded",
+			metadata:     attempt(3, "10.0.0.1:8000", "10.0.0.3:8000"),
+			candidates:   []schedulingtypes.Pod{pod1, pod2, pod3},
+			wantPods:     []schedulingtypes.Pod{pod2},
+			wantFailures: []string{"/pod1", "/pod3"},
+		},
+		{
+			name:         "retry, failed endpoint kept when no other candidate remains",
+			metadata:     attempt(2, "10.0.0.1:8000"),
+			candidates:   []schedulingtypes.Pod{pod1},
+			wantPods:     []schedulingtypes.Pod{pod1},
+			wantFailures: []string{"/pod1"},
+		},
+		{
+			name:       "retry without attempted endpoints, no pods excluded",
+			metadata:   attempt(2),
+			candidates: []schedulingtypes.Pod{pod1, pod2, pod3},
+			wantPods:   []schedulingtypes.Pod{pod1, pod2, pod3},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tracker := &testOutlierTracker{tn: plugins.TypedName{Type: testOutlierTrackerType, Name: "tracker"}}
+			director := NewDirectorWithConfig(nil, &mockScheduler{}, &mockSaturationDetector{}, NewConfig().WithOutlierTrackerPlugins(tracker))
+
+			got := director.excludeFailedEndpoints(context.Background(), test.metadata, test.candidates)
+
+			if diff := cmp.Diff(test.wantPods, got); diff != "" {
+				t.Errorf("Unexpected candidate pods (-want +got): %v", diff)
+			}
+			if diff := cmp.Diff(test.wantFailures, tracker.failures); diff != "" {
+				t.Errorf("Unexpected failures (-want +got): %v", diff)
+			}
+		})
+	}
+}
+
 func TestGetRandomPod(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -621,6 +865,71 @@ func TestGetRandomPod(t *testing.T) {
 	}
 }
 
+func TestInjectStreamUsageOption(t *testing.T) {
+	tests := []struct {
+		name         string
+		path         string
+		body         map[string]any
+		wantBody     map[string]any
+		wantInjected bool
+	}{
+		{
+			name:         "streaming chat-completions request",
+			path:         requtil.ChatCompletionsPath,
+			body:         map[string]any{"stream": true},
+			wantBody:     map[string]any{"stream": true, "stream_options": map[string]any{"include_usage": true}},
+			wantInjected: true,
+		},
+		{
+			name:         "streaming completions request with other stream options",
+			path:         requtil.CompletionsPath + "?debug=true",
+			body:         map[string]any{"stream": true, "stream_options": map[string]any{"continuous_usage_stats": false}},
+			wantBody:     map[string]any{"stream": true, "stream_options": map[string]any{"continuous_usage_stats": false, "include_usage": true}},
+			wantInjected: true,
+		},
+		{
+			name:     "client requested usage",
+			path:     requtil.ChatCompletionsPath,
+			body:     map[string]any{"stream": true, "stream_options": map[string]any{"include_usage": true}},
+			wantBody: map[string]any{"stream": true, "stream_options": map[string]any{"include_usage": true}},
+		},
+		{
+			name:     "non streaming request",
+			path:     requtil.ChatCompletionsPath,
+			body:     map[string]any{"stream": false},
+			wantBody: map[string]any{"stream": false},
+		},
+		{
+			name:     "streaming request of another API",
+			path:     requtil.AnthropicMessagesPath,
+			body:     map[string]any{"stream": true},
+			wantBody: map[string]any{"stream": true},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := logutil.NewTestLoggerIntoContext(context.Background())
+			director := NewDirectorWithConfig(nil, nil, nil, NewConfig().WithStreamUsageInjection(true))
+			reqCtx := &handlers.RequestContext{
+				Request: &handlers.Request{
+					Headers: map[string]string{requtil.PathHeaderKey: test.path},
+					Body:    test.body,
+				},
+			}
+
+			director.injectStreamUsageOption(ctx, reqCtx)
+
+			if diff := cmp.Diff(test.wantBody, reqCtx.Request.Body); diff != "" {
+				t.Errorf("Unexpected request body (-want +got): %v", diff)
+			}
+			if reqCtx.StreamUsageInjected != test.wantInjected {
+				t.Errorf("StreamUsageInjected = %t, want %t", reqCtx.StreamUsageInjected, test.wantInjected)
+			}
+		})
+	}
+}
+
 func TestDirector_HandleResponse(t *testing.T) {
 	pr1 := newTestPostResponse("pr1")
 
@@ -658,6 +967,276 @@ func TestDirector_HandleResponse(t *testing.T) {
 	}
 }
 
+func TestDirector_RequestMutators(t *testing.T) {
+	ctx := logutil.NewTestLoggerIntoContext(context.Background())
+	clampMaxTokens := newTestMutator("clamp", func(mutable *Request) error {
+		if maxTokens, ok := mutable.Body["max_tokens"].(float64); ok && maxTokens > 100 {
+			mutable.Body["max_tokens"] = float64(100)
+		}
+		return nil
+	})
+	addTenantHeader := newTestMutator("tenant", func(mutable *Request) error {
+		mutable.Headers["x-tenant"] = fmt.Sprint(mutable.Body["max_tokens"])
+		return nil
+	})
+	reject := newTestMutator("reject", func(_ *Request) error {
+		return errutil.Error{Code: errutil.BadRequest, Msg: "rejected"}
+	})
+	fail := newTestMutator("fail", func(_ *Request) error {
+		return errors.New("failed")
+	})
+
+	tests := []struct {
+		name        string
+		mutators    []RequestMutator
+		wantBody    map[string]any
+		wantHeaders map[string]string
+		wantErrCode string
+	}{
+		{
+			name:        "mutators run in order",
+			mutators:    []RequestMutator{clampMaxTokens, addTenantHeader},
+			wantBody:    map[string]any{"max_tokens": float64(100)},
+			wantHeaders: map[string]string{"x-tenant": "100"},
+		},
+		{
+			name:        "mutator rejects the request",
+			mutators:    []RequestMutator{reject, clampMaxTokens},
+			wantErrCode: errutil.BadRequest,
+		},
+		{
+			name:        "mutator fails",
+			mutators:    []RequestMutator{fail},
+			wantErrCode: errutil.Internal,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			director := NewDirectorWithConfig(nil, nil, nil, NewConfig().WithRequestMutatorPlugins(test.mutators...))
+			reqCtx := &handlers.RequestContext{
+				Request: &handlers.Request{
+					Headers: map[string]string{},
+					Body:    map[string]any{"max_tokens": float64(500)},
+				},
+			}
+
+			err := director.runRequestMutatorPlugins(ctx, reqCtx, &schedulingtypes.SchedulingResult{})
+			if test.wantErrCode != "" {
+				var e errutil.Error
+				if assert.ErrorAs(t, err, &e, "Error should be of type errutil.Error") {
+					assert.Equal(t, test.wantErrCode, e.Code, "Error code mismatch")
+				}
+				return
+			}
+			assert.NoError(t, err)
+			if diff := cmp.Diff(test.wantBody, reqCtx.Request.Body); diff != "" {
+				t.Errorf("Unexpected request body (-want +got): %v", diff)
+			}
+			if diff := cmp.Diff(test.wantHeaders, reqCtx.Request.Headers); diff != "" {
+				t.Errorf("Unexpected request headers (-want +got): %v", diff)
+			}
+		})
+	}
+}
+
+func TestDirector_ResponseMutators(t *testing.T) {
+	ctx := logutil.NewTestLoggerIntoContext(context.Background())
+	redact := newTestResponseMutator("redact", func(response *Response) {
+		if response.Headers != nil {
+			delete(response.Headers, "x-internal")
+		}
+		response.Body = strings.ReplaceAll(response.Body, "secret", "******")
+	})
+	mark := newTestResponseMutator("mark", func(response *Response) {
+		if response.Headers != nil {
+			response.Headers["x-mutated"] = "true"
+		}
+		if response.EndOfStream {
+			response.Body += "\n"
+		}
+	})
+	director := NewDirectorWithConfig(nil, nil, nil, NewConfig().WithResponseMutatorPlugins(redact, mark))
+
+	reqCtx := &handlers.RequestContext{
+		Request: &handlers.Request{Headers: map[string]string{requtil.RequestIdHeaderKey: "test-req-id"}},
+		Response: &handlers.Response{
+			Headers: map[string]string{"x-internal": "pod1", "content-type": "text/event-stream"},
+		},
+		TargetPod: &backend.Pod{NamespacedName: types.NamespacedName{Namespace: "namespace1", Name: "test-pod-name"}},
+	}
+	if _, err := director.HandleResponse(ctx, reqCtx); err != nil {
+		t.Fatalf("HandleResponse() returned unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(map[string]string{"content-type": "text/event-stream", "x-mutated": "true"}, reqCtx.Response.Headers); diff != "" {
+		t.Errorf("Unexpected response headers (-want +got): %v", diff)
+	}
+
+	chunk := director.HandleResponseBody(ctx, reqCtx, []byte(`data: {"text":"a secret"}`), true, false)
+	if diff := cmp.Diff(`data: {"text":"a ******"}`, string(chunk)); diff != "" {
+		t.Errorf("Unexpected response chunk (-want +got): %v", diff)
+	}
+	chunk = director.HandleResponseBody(ctx, reqCtx, []byte(`data: [DONE]`), true, true)
+	if diff := cmp.Diff("data: [DONE]\n", string(chunk)); diff != "" {
+		t.Errorf("Unexpected last response chunk (-want +got): %v", diff)
+	}
+}
+
+func TestDirector_ResponseMutatorsChangeBodyLength(t *testing.T) {
+	ctx := logutil.NewTestLoggerIntoContext(context.Background())
+	annotate := newTestResponseMutator("annotate", func(response *Response) {
+		response.Body = strings.Replace(response.Body, `"choices"`, `"annotated":true,"choices"`, 1)
+	})
+	director := NewDirectorWithConfig(nil, nil, nil, NewConfig().WithResponseMutatorPlugins(annotate))
+
+	body := `{"choices":[]}`
+	reqCtx := &handlers.RequestContext{
+		Request: &handlers.Request{Headers: map[string]string{requtil.RequestIdHeaderKey: "test-req-id"}},
+		Response: &handlers.Response{
+			Headers: map[string]string{"content-type": "application/json", "content-length": strconv.Itoa(len(body))},
+		},
+	}
+	if _, err := director.HandleResponse(ctx, reqCtx); err != nil {
+		t.Fatalf("HandleResponse() returned unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(map[string]string{"content-type": "application/json"}, reqCtx.Response.Headers); diff != "" {
+		t.Errorf("Unexpected response headers, the content length should be removed (-want +got): %v", diff)
+	}
+	if diff := cmp.Diff(`{"annotated":true,"choices":[]}`, string(director.HandleResponseBody(ctx, reqCtx, []byte(body), false, true))); diff != "" {
+		t.Errorf("Unexpected response body (-want +got): %v", diff)
+	}
+
+	// Without response mutators, the content length is kept.
+	director = NewDirectorWithConfig(nil, nil, nil, NewConfig())
+	reqCtx.Response.Headers = map[string]string{"content-length": strconv.Itoa(len(body))}
+	if _, err := director.HandleResponse(ctx, reqCtx); err != nil {
+		t.Fatalf("HandleResponse() returned unexpected error: %v", err)
+	}
+	assert.Equal(t, strconv.Itoa(len(body)), reqCtx.Response.Headers["content-length"])
+}
+
+func TestDirector_ResponseLifecycle(t *testing.T) {
+	ctx := logutil.NewTestLoggerIntoContext(context.Background())
+	plugin := &testResponseLifecycle{tn: plugins.TypedName{Type: testResponseLifecycleType, Name: "lifecycle"}}
+	director := NewDirectorWithConfig(nil, nil, nil, NewConfig().WithResponseStreamingPlugins(plugin).WithResponseCompletePlugins(plugin))
+
+	received := time.Now()
+	reqCtx := &handlers.RequestContext{
+		Request:                  &handlers.Request{Headers: map[string]string{requtil.RequestIdHeaderKey: "test-req-id"}},
+		Response:                 &handlers.Response{Headers: map[string]string{"content-type": "application/json"}},
+		RequestReceivedTimestamp: received,
+		TargetPod:                &backend.Pod{NamespacedName: types.NamespacedName{Namespace: "namespace1", Name: "test-pod-name"}},
+	}
+
+	// The chunks of a buffered response are not streamed.
+	director.HandleResponseBody(ctx, reqCtx, []byte(`{"choices":[]}`), false, true)
+	if len(plugin.streamed) != 0 {
+		t.Errorf("ResponseStreaming called %d times for a buffered response, want 0", len(plugin.streamed))
+	}
+	director.HandleResponseBody(ctx, reqCtx, []byte(`data: {}`), true, false)
+	if diff := cmp.Diff([]string{`data: {}`}, plugin.streamed); diff != "" {
+		t.Errorf("Unexpected streamed chunks (-want +got): %v", diff)
+	}
+
+	reqCtx.Usage = handlers.Usage{PromptTokens: 3, CompletionTokens: 5, TotalTokens: 8}
+	reqCtx.ResponseCompleteTimestamp = received.Add(time.Second)
+	director.HandleResponseComplete(ctx, reqCtx, []byte(`{"usage":{}}`), true)
+
+	want := &Response{
+		RequestId:                 "test-req-id",
+		Headers:                   map[string]string{"content-type": "application/json"},
+		Body:                      `{"usage":{}}`,
+		Usage:                     handlers.Usage{PromptTokens: 3, CompletionTokens: 5, TotalTokens: 8},
+		RequestReceivedTimestamp:  received,
+		ResponseCompleteTimestamp: received.Add(time.Second),
+		Cancelled:                 true,
+	}
+	if diff := cmp.Diff(want, plugin.completed); diff != "" {
+		t.Errorf("Unexpected completed response (-want +got): %v", diff)
+	}
+}
+
+func TestDirector_HandleResponseFailover(t *testing.T) {
+	storePods := []*corev1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Name: "pod1"}, Status: corev1.PodStatus{PodIP: "10.0.0.1"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "pod2"}, Status: corev1.PodStatus{PodIP: "10.0.0.2"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "pod3"}, Status: corev1.PodStatus{PodIP: "10.0.0.3"}},
+	}
+	pmf := backendmetrics.NewPodMetricsFactory(&backendmetrics.FakePodMetricsClient{}, time.Second)
+	ds := datastore.NewDatastore(t.Context(), pmf)
+	for _, pod := range storePods {
+		ds.PodUpdateOrAddIfNotExist(pod)
+	}
+	servedMetadata := func(endpoint string) map[string]any {
+		return map[string]any{
+			metadata.DestinationEndpointNamespace: map[string]any{metadata.DestinationEndpointServedKey: endpoint},
+		}
+	}
+
+	tests := []struct {
+		name            string
+		metadata        map[string]any
+		responseHeaders map[string]string
+		wantTargetPod   string
+		wantFailures    []string
+		wantSuccesses   []string
+	}{
+		{
+			name:            "served by the target endpoint",
+			metadata:        map[string]any{},
+			responseHeaders: map[string]string{":status": "200"},
+			wantTargetPod:   "/pod1",
+			wantSuccesses:   []string{"/pod1"},
+		},
+		{
+			name:            "server error on the target endpoint",
+			metadata:        map[string]any{},
+			responseHeaders: map[string]string{":status": "503"},
+			wantTargetPod:   "/pod1",
+			wantFailures:    []string{"/pod1"},
+		},
+		{
+			name:            "failed over to the second fallback endpoint",
+			metadata:        servedMetadata("10.0.0.3:8000"),
+			responseHeaders: map[string]string{":status": "200"},
+			wantTargetPod:   "/pod3",
+			wantFailures:    []string{"/pod1", "/pod2"},
+			wantSuccesses:   []string{"/pod3"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := logutil.NewTestLoggerIntoContext(context.Background())
+			tracker := &testOutlierTracker{tn: plugins.TypedName{Type: testOutlierTrackerType, Name: "tracker"}}
+			pr := newTestPostResponse("pr")
+			director := NewDirectorWithConfig(ds, &mockScheduler{}, &mockSaturationDetector{}, NewConfig().WithOutlierTrackerPlugins(tracker).WithPostResponsePlugins(pr))
+
+			reqCtx := &handlers.RequestContext{
+				Request:          &handlers.Request{Headers: map[string]string{}, Metadata: test.metadata},
+				Response:         &handlers.Response{Headers: test.responseHeaders},
+				TargetPod:        ds.PodList(func(pm backendmetrics.PodMetrics) bool { return pm.GetPod().Address == "10.0.0.1" })[0].GetPod(),
+				TargetEndpoint:   "10.0.0.1:8000",
+				FallbackEndpoint: "10.0.0.2:8000,10.0.0.3:8000",
+			}
+
+			if _, err := director.HandleResponse(ctx, reqCtx); err != nil {
+				t.Fatalf("HandleResponse() returned unexpected error: %v", err)
+			}
+
+			if diff := cmp.Diff(test.wantTargetPod, pr.lastTargetPodOnResponse); diff != "" {
+				t.Errorf("Unexpected target pod (-want +got): %v", diff)
+			}
+			if diff := cmp.Diff(test.wantFailures, tracker.failures); diff != "" {
+				t.Errorf("Unexpected failures (-want +got): %v", diff)
+			}
+			if diff := cmp.Diff(test.wantSuccesses, tracker.successes); diff != "" {
+				t.Errorf("Unexpected successes (-want +got): %v", diff)
+			}
+		})
+	}
+}
+
 const (
 	testPostResponseType = "test-post-response"
 )
@@ -682,3 +1261,339 @@ func (p *testPostResponse) PostResponse(_ context.Context, _ *schedulingtypes.LL
 	p.lastRespOnResponse = response
 	p.lastTargetPodOnResponse = targetPod.NamespacedName.String()
 }
+
+const (
+	testMutatorType = "test-mutator"
+)
+
+type testMutator struct {
+	tn     plugins.TypedName
+	mutate func(mutable *Request) error
+}
+
+func newTestMutator(name string, mutate func(mutable *Request) error) *testMutator {
+	return &testMutator{
+		tn:     plugins.TypedName{Type: testMutatorType, Name: name},
+		mutate: mutate,
+	}
+}
+
+func (p *testMutator) TypedName() plugins.TypedName {
+	return p.tn
+}
+
+func (p *testMutator) MutateRequest(_ context.Context, _ *schedulingtypes.LLMRequest, _ *schedulingtypes.SchedulingResult, mutable *Request) error {
+	return p.mutate(mutable)
+}
+
+type testResponseMutator struct {
+	tn     plugins.TypedName
+	mutate func(response *Response)
+}
+
+func newTestResponseMutator(name string, mutate func(response *Response)) *testResponseMutator {
+	return &testResponseMutator{
+		tn:     plugins.TypedName{Type: testMutatorType, Name: name},
+		mutate: mutate,
+	}
+}
+
+func (p *testResponseMutator) TypedName() plugins.TypedName {
+	return p.tn
+}
+
+func (p *testResponseMutator) MutateResponse(_ context.Context, _ *schedulingtypes.LLMRequest, response *Response, _ *backend.Pod) {
+	p.mutate(response)
+}
+
+const (
+	testResponseLifecycleType = "test-response-lifecycle"
+)
+
+type testResponseLifecycle struct {
+	tn        plugins.TypedName
+	streamed  []string
+	completed *Response
+}
+
+func (p *testResponseLifecycle) TypedName() plugins.TypedName {
+	return p.tn
+}
+
+func (p *testResponseLifecycle) ResponseStreaming(_ context.Context, _ *schedulingtypes.LLMRequest, response *Response, _ *backend.Pod) {
+	p.streamed = append(p.streamed, response.Body)
+}
+
+func (p *testResponseLifecycle) ResponseComplete(_ context.Context, _ *schedulingtypes.LLMRequest, response *Response, _ *backend.Pod) {
+	p.completed = response
+}
+
+const (
+	testOutlierTrackerType = "test-outlier-tracker"
+)
+
+type testOutlierTracker struct {
+	tn        plugins.TypedName
+	failures  []string
+	successes []string
+}
+
+func (p *testOutlierTracker) TypedName() plugins.TypedName {
+	return p.tn
+}
+
+func (p *testOutlierTracker) RecordFailure(_ context.Context, pod *backend.Pod) {
+	p.failures = append(p.failures, pod.NamespacedName.String())
+}
+
+func (p *testOutlierTracker) RecordSuccess(_ context.Context, pod *backend.Pod) {
+	p.successes = append(p.successes, pod.NamespacedName.String())
+}
+
+type testModelProvider struct {
+	models []string
+}
+
+func (p *testModelProvider) TypedName() plugins.TypedName {
+	return plugins.TypedName{Type: "test-model-provider", Name: "test-model-provider"}
+}
+
+func (p *testModelProvider) ProvidesModel(_ context.Context, model string) bool {
+	return slices.Contains(p.models, model)
+}
+
+func TestDirector_HandleRequestEndpointAttributes(t *testing.T) {
+	ctx := logutil.NewTestLoggerIntoContext(context.Background())
+
+	tests := []struct {
+		name        string
+		model       string
+		unhealthy   []string
+		provided    []string
+		noSlot      []string
+		wantErrCode string
+		wantErrMsg  string
+	}{
+		{
+			name:        "base model served",
+			model:       "base-model",
+			wantErrCode: errutil.InferencePoolResourceExhausted, // scheduled, the mock scheduler fails
+		},
+		{
+			name:        "adapter served",
+			model:       "lora-adapter",
+			wantErrCode: errutil.InferencePoolResourceExhausted,
+		},
+		{
+			name:        "model not served by any endpoint",
+			model:       "unknown-model",
+			wantErrCode: errutil.ModelNotFound,
+		},
+		{
+			name:        "model not served by any endpoint provided on demand",
+			model:       "unknown-model",
+			provided:    []string{"unknown-model"},
+			wantErrCode: errutil.InferencePoolResourceExhausted,
+		},
+		{
+			name:        "adapter with an unreserved slot on an endpoint",
+			model:       "lora-adapter",
+			noSlot:      []string{"pod2"},
+			wantErrCode: errutil.InferencePoolResourceExhausted,
+			wantErrMsg:  "failed to find target pod",
+		},
+		{
+			name:        "adapter without an unreserved slot on any endpoint",
+			model:       "lora-adapter",
+			noSlot:      []string{"pod1", "pod2"},
+			wantErrCode: errutil.InferencePoolResourceExhausted,
+			wantErrMsg:  "no endpoint has an unreserved adapter slot for model lora-adapter",
+		},
+		{
+			name:        "model only served by an unhealthy endpoint",
+			model:       "lora-adapter",
+			unhealthy:   []string{"pod1"},
+			wantErrCode: errutil.ModelNotFound,
+		},
+		{
+			name:        "no healthy endpoint",
+			model:       "base-model",
+			unhealthy:   []string{"pod1", "pod2"},
+			wantErrCode: errutil.ServiceUnavailable,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ds := datastore.NewDatastore(t.Context(), datalayer.NewEndpointFactory(nil, time.Second))
+			pool := &v1.InferencePool{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-pool", Namespace: "default"},
+				Spec: v1.InferencePoolSpec{
+					TargetPorts: []v1.Port{{Number: v1.PortNumber(int32(8000))}},
+					Selector:    v1.LabelSelector{MatchLabels: map[v1.LabelKey]v1.LabelValue{"app": "inference"}},
+				},
+			}
+			scheme := runtime.NewScheme()
+			_ = clientgoscheme.AddToScheme(scheme)
+			if err := ds.PoolSet(ctx, fake.NewClientBuilder().WithScheme(scheme).Build(), pool); err != nil {
+				t.Fatalf("Error while setting inference pool: %v", err)
+			}
+			for i := range 2 {
+				ds.PodUpdateOrAddIfNotExist(&corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("pod%v", i+1), Namespace: "default", Labels: map[string]string{"app": "inference"}},
+					Status: corev1.PodStatus{
+						PodIP:      fmt.Sprintf("192.168.%v.100", i+1),
+						Phase:      corev1.PodRunning,
+						Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+					},
+				})
+			}
+			for _, pod := range ds.PodList(backendmetrics.AllPodsPredicate) {
+				served := &models.ServedModels{BaseModels: []string{"base-model"}}
+				if pod.GetPod().NamespacedName.Name == "pod1" {
+					served.Adapters = map[string]string{"lora-adapter": "base-model"}
+				}
+				pod.Put(models.ServedModelsAttributeKey, served)
+				pod.Put(health.HealthAttributeKey, &health.Health{Healthy: !slices.Contains(test.unhealthy, pod.GetPod().NamespacedName.Name)})
+			}
+
+			config := NewConfig().WithModelProviderPlugins(&testModelProvider{models: test.provided})
+			director := NewDirectorWithConfig(ds, &mockScheduler{scheduleErr: errors.New("no pod")}, &mockAdapterSlotReservation{noSlotPods: test.noSlot}, config)
+			reqCtx := &handlers.RequestContext{
+				Request: &handlers.Request{
+					Body:    map[string]any{"model": test.model, "prompt": "prompt"},
+					Headers: map[string]string{requtil.RequestIdHeaderKey: "test-req-id"},
+				},
+			}
+
+			_, err := director.HandleRequest(ctx, reqCtx)
+
+			var e errutil.Error
+			if assert.ErrorAs(t, err, &e, "Error should be of type errutil.Error") {
+				assert.Equal(t, test.wantErrCode, e.Code, "Error code mismatch")
+				assert.Contains(t, e.Msg, test.wantErrMsg, "Error message mismatch")
+			}
+		})
+	}
+}
+
+func TestDirector_HandleRequestObjective(t *testing.T) {
+	ctx := logutil.NewTestLoggerIntoContext(context.Background())
+
+	objective := testutil.MakeInferenceObjective("food-review-objective").MatchModels("food-review").Priority(1).ObjRef()
+	objective.Spec.MaxInputTokens = ptr.To[int64](4)
+	objective.Spec.MaxOutputTokens = ptr.To[int64](100)
+	objective.Spec.SLOs = &v1alpha2.ObjectiveSLOs{TimeToFirstToken: &metav1.Duration{Duration: time.Second}}
+	objective.Spec.Budget = &v1alpha2.ObjectiveBudget{MaxConcurrentRequests: ptr.To[int32](1)}
+
+	ds := datastore.NewDatastore(t.Context(), datalayer.NewEndpointFactory(nil, time.Second))
+	ds.ObjectiveSet(objective)
+	pool := &v1.InferencePool{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-pool", Namespace: "default"},
+		Spec: v1.InferencePoolSpec{
+			TargetPorts: []v1.Port{{Number: v1.PortNumber(int32(8000))}},
+			Selector:    v1.LabelSelector{MatchLabels: map[v1.LabelKey]v1.LabelValue{"app": "inference"}},
+		},
+	}
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	if err := ds.PoolSet(ctx, fake.NewClientBuilder().WithScheme(scheme).Build(), pool); err != nil {
+		t.Fatalf("Error while setting inference pool: %v", err)
+	}
+	ds.PodUpdateOrAddIfNotExist(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "pod1", Namespace: "default", Labels: map[string]string{"app": "inference"}},
+		Status: corev1.PodStatus{
+			PodIP:      "192.168.1.100",
+			Phase:      corev1.PodRunning,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+		},
+	})
+
+	scheduler := &mockScheduler{scheduleResults: &schedulingtypes.SchedulingResult{
+		ProfileResults: map[string]*schedulingtypes.ProfileRunResult{
+			"testProfile": {
+				TargetPods: []schedulingtypes.Pod{
+					&schedulingtypes.ScoredPod{
+						Pod: &schedulingtypes.PodMetrics{
+							Pod: &backend.Pod{
+								Address:        "192.168.1.100",
+								NamespacedName: types.NamespacedName{Name: "pod1", Namespace: "default"},
+							},
+						},
+					},
+				},
+			},
+		},
+		PrimaryProfileName: "testProfile",
+	}}
+	director := NewDirectorWithConfig(ds, scheduler, &mockSaturationDetector{}, NewConfig())
+	newReqCtx := func(body map[string]any) *handlers.RequestContext {
+		return &handlers.RequestContext{
+			Request: &handlers.Request{
+				Body:    body,
+				Headers: map[string]string{requtil.RequestIdHeaderKey: "test-req-id"},
+			},
+		}
+	}
+	assertErrCode := func(err error, wantErrCode string) {
+		t.Helper()
+		var e errutil.Error
+		if assert.ErrorAs(t, err, &e, "Error should be of type errutil.Error") {
+			assert.Equal(t, wantErrCode, e.Code, "Error code mismatch")
+		}
+	}
+
+	// The input tokens are above the limit of the matched objective.
+	reqCtx, err := director.HandleRequest(ctx, newReqCtx(map[string]any{"model": "food-review", "prompt": "a prompt above the limit"}))
+	assertErrCode(err, errutil.BadRequest)
+	assert.Equal(t, objective.Name, reqCtx.ObjectiveKey, "the request should match the objective")
+
+	// The maximum number of output tokens is lowered to the limit of the objective.
+	first, err := director.HandleRequest(ctx, newReqCtx(map[string]any{"model": "food-review", "prompt": "hi", "max_tokens": float64(500)}))
+	if !assert.NoError(t, err, "HandleRequest() returned unexpected error") {
+		return
+	}
+	assert.Equal(t, int64(100), first.Request.Body["max_tokens"], "max_tokens should be lowered to the limit")
+	assert.Equal(t, time.Second, first.SchedulingRequest.TTFTSLO, "the SLO of the objective should be set on the request")
+	assert.True(t, first.ObjectiveConcurrencySlot, "the request should hold a concurrency slot")
+
+	// The concurrency budget is exhausted until the first request completes.
+	_, err = director.HandleRequest(ctx, newReqCtx(map[string]any{"model": "food-review", "prompt": "hi"}))
+	assertErrCode(err, errutil.InferencePoolResourceExhausted)
+	director.HandleResponseComplete(ctx, first, nil, false)
+	assert.False(t, first.ObjectiveConcurrencySlot, "the concurrency slot should be released")
+
+	// A request that fails to be scheduled doesn't take a slot.
+	scheduler.scheduleErr = errors.New("no pod")
+	_, err = director.HandleRequest(ctx, newReqCtx(map[string]any{"model": "food-review", "prompt": "hi"}))
+	assertErrCode(err, errutil.InferencePoolResourceExhausted)
+	scheduler.scheduleErr = nil
+
+	second, err := director.HandleRequest(ctx, newReqCtx(map[string]any{"model": "food-review", "prompt": "hi"}))
+	if assert.NoError(t, err, "HandleRequest() returned unexpected error") {
+		assert.Equal(t, int64(100), second.Request.Body["max_tokens"], "max_tokens should be set to the limit")
+	}
+
+	// Requests of other models don't match the objective.
+	other, err := director.HandleRequest(ctx, newReqCtx(map[string]any{"model": "other-model", "prompt": "a prompt above the limit"}))
+	if assert.NoError(t, err, "HandleRequest() returned unexpected error") {
+		assert.Empty(t, other.ObjectiveKey, "the request should not match the objective")
+	}
+
+	usage := ds.ObjectiveUsageGet(objective.Name)
+	assert.Equal(t, int64(5), usage.Requests, "the requests of the objective should be recorded")
+	assert.Equal(t, int64(3), usage.RejectedRequests, "the rejected requests of the objective should be recorded")
+
+	// Requests rejected before they are scheduled don't spend the rate budget of the objective.
+	director.HandleResponseComplete(ctx, second, nil, false)
+	objective.Spec.Budget = &v1alpha2.ObjectiveBudget{RequestsPerSecond: ptr.To[int32](1)}
+	ds.ObjectiveSet(objective)
+	scheduler.scheduleErr = errors.New("no pod")
+	_, err = director.HandleRequest(ctx, newReqCtx(map[string]any{"model": "food-review", "prompt": "hi"}))
+	assertErrCode(err, errutil.InferencePoolResourceExhausted)
+	scheduler.scheduleErr = nil
+	_, err = director.HandleRequest(ctx, newReqCtx(map[string]any{"model": "food-review", "prompt": "hi"}))
+	assert.NoError(t, err, "the rejected request should not have spent the rate budget")
+	_, err = director.HandleRequest(ctx, newReqCtx(map[string]any{"model": "food-review", "prompt": "hi"}))
+	assertErrCode(err, errutil.InferencePoolResourceExhausted)
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package requestcontrol
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"golang.org/x/time/rate"
+
+	"sigs.k8s.io/gateway-api-inference-extension/apix/v1alpha2"
+	schedulingtypes "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+	errutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/error"
+)
+
+const (
+	// charactersPerToken is the number of prompt characters per token the input tokens of a request are estimated by.
+	charactersPerToken = 4
+)
+
+// maxOutputTokensFields are the request body fields that limit the number of output tokens, in order of precedence.
+var maxOutputTokensFields = []string{"max_completion_tokens", "max_output_tokens", "max_tokens"}
+
+// enforceTokenLimits rejects a request whose estimated number of input tokens is above the MaxInputTokens of
+// its objective, and lowers the maximum number of output tokens the request asks for to the MaxOutputTokens of
+// its objective, setting it if the request doesn't ask for a maximum. The output limit is only enforced on
+// requests of the OpenAI and Anthropic APIs (limitOutput), since the body of other protocols, such as the
+// KServe Open Inference Protocol, has no maximum output tokens field.
+func enforceTokenLimits(objective *v1alpha2.InferenceObjective, data *schedulingtypes.LLMRequestData, body map[string]any,
+	limitOutput bool) error {
+	if limit := objective.Spec.MaxInputTokens; limit != nil {
+		if tokens := estimateInputTokens(data); tokens > *limit {
+			return errutil.Error{Code: errutil.BadRequest,
+				Msg: fmt.Sprintf("the request has about %d input tokens, above the limit of %d of its objective", tokens, *limit)}
+		}
+	}
+
+	limit := objective.Spec.MaxOutputTokens
+	if limit == nil || !limitOutput || data.IsPooling() {
+		return nil
+	}
+	for _, field := range maxOutputTokensFields {
+		if value, ok := body[field].(float64); ok {
+			if value > float64(*limit) {
+				body[field] = *limit
+			}
+			return nil
+		}
+	}
+	if data.Responses != nil {
+		body["max_output_tokens"] = *limit
+	} else {
+		body["max_tokens"] = *limit
+	}
+	return nil
+}
+
+// estimateInputTokens estimates the number of input tokens of a request from the length of its prompt.
+func estimateInputTokens(data *schedulingtypes.LLMRequestData) int64 {
+	var characters int
+	switch {
+	case data.Completions != nil:
+		characters = len(data.Completions.Prompt)
+	case data.ChatCompletions != nil:
+		for _, message := range data.ChatCompletions.Messages {
+			characters += len(message.Content.Text())
+		}
+	case data.Responses != nil:
+		input, _ := json.Marshal(data.Responses.Input)
+		characters = len(data.Responses.Instructions) + len(input)
+	case data.Embeddings != nil:
+		input, _ := json.Marshal(data.Embeddings.Input)
+		characters = len(input)
+	case data.Rerank != nil:
+		characters = len(data.Rerank.Text())
+	}
+	return int64((characters + charactersPerToken - 1) / charactersPerToken)
+}
+
+// objectiveBudgets enforces the rate and the concurrency budgets of the objectives.
+type objectiveBudgets struct {
+	mu      sync.Mutex
+	budgets map[string]*objectiveBudget // by objective name
+}
+
+type objectiveBudget struct {
+	limiter  *rate.Limiter
+	inFlight int
+}
+
+func newObjectiveBudgets() *objectiveBudgets {
+	return &objectiveBudgets{budgets: map[string]*objectiveBudget{}}
+}
+
+// acquire takes a request of the objective from its budget. It returns whether the request holds a concurrency
+// slot that must be released, or an error if the request is beyond the budget.
+func (b *objectiveBudgets) acquire(objective *v1alpha2.InferenceObjective) (bool, error) {
+	budget := objective.Spec.Budget
+	if budget == nil || (budget.RequestsPerSecond == nil && budget.MaxConcurrentRequests == nil) {
+		return false, nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	state, ok := b.budgets[objective.Name]
+	if !ok {
+		state = &objectiveBudget{}
+		b.budgets[objective.Name] = state
+	}
+	if budget.MaxConcurrentRequests != nil && state.inFlight >= int(*budget.MaxConcurrentRequests) {
+		return false, errutil.Error{Code: errutil.InferencePoolResourceExhausted,
+			Msg: fmt.Sprintf("the concurrency budget of objective %s is exhausted", objective.Name)}
+	}
+	if rps := budget.RequestsPerSecond; rps != nil {
+		if state.limiter == nil {
+			state.limiter = rate.NewLimiter(rate.Limit(*rps), int(*rps))
+		} else if state.limiter.Limit() != rate.Limit(*rps) {
+			state.limiter.SetLimit(rate.Limit(*rps))
+			state.limiter.SetBurst(int(*rps))
+		}
+		if !state.limiter.Allow() {
+			return false, errutil.Error{Code: errutil.InferencePoolResourceExhausted,
+				Msg: fmt.Sprintf("the rate budget of objective %s is exhausted", objective.Name)}
+		}
+	} else {
+		state.limiter = nil
+	}
+	if budget.MaxConcurrentRequests == nil {
+		return false, nil
+	}
+	state.inFlight++
+	return true, nil
+}
+
+// release returns the concurrency slot of a request of the objective to its budget.
+func (b *objectiveBudgets) release(objectiveName string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if state, ok := b.budgets[objectiveName]; ok && state.inFlight > 0 {
+		state.inFlight--
+	}
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package requestcontrol
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/gateway-api-inference-extension/apix/v1alpha2"
+	schedulingtypes "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+)
+
+func TestEnforceTokenLimits(t *testing.T) {
+	completions := &schedulingtypes.LLMRequestData{Completions: &schedulingtypes.CompletionsRequest{Prompt: "12345678"}}
+	responses := &schedulingtypes.LLMRequestData{Responses: &schedulingtypes.ResponsesRequest{Instructions: "1234"}}
+
+	tests := []struct {
+		name      string
+		spec      v1alpha2.InferenceObjectiveSpec
+		data      *schedulingtypes.LLMRequestData
+		body      map[string]any
+		kserve    bool
+		wantBody  map[string]any
+		expectErr bool
+	}{
+		{
+			name:     "no limits",
+			data:     completions,
+			body:     map[string]any{"max_tokens": float64(500)},
+			wantBody: map[string]any{"max_tokens": float64(500)},
+		},
+		{
+			name: "input tokens within the limit",
+			spec: v1alpha2.InferenceObjectiveSpec{MaxInputTokens: ptr.To[int64](2)},
+			data: completions,
+			body: map[string]any{},
+			// no maximum is set without an output limit
+			wantBody: map[string]any{},
+		},
+		{
+			name:      "input tokens above the limit",
+			spec:      v1alpha2.InferenceObjectiveSpec{MaxInputTokens: ptr.To[int64](1)},
+			data:      completions,
+			body:      map[string]any{},
+			expectErr: true,
+		},
+		{
+			name:     "output tokens lowered to the limit",
+			spec:     v1alpha2.InferenceObjectiveSpec{MaxOutputTokens: ptr.To[int64](100)},
+			data:     completions,
+			body:     map[string]any{"max_completion_tokens": float64(500)},
+			wantBody: map[string]any{"max_completion_tokens": int64(100)},
+		},
+		{
+			name:     "output tokens within the limit",
+			spec:     v1alpha2.InferenceObjectiveSpec{MaxOutputTokens: ptr.To[int64](100)},
+			data:     completions,
+			body:     map[string]any{"max_tokens": float64(50)},
+			wantBody: map[string]any{"max_tokens": float64(50)},
+		},
+		{
+			name:     "output limit set on a completions request",
+			spec:     v1alpha2.InferenceObjectiveSpec{MaxOutputTokens: ptr.To[int64](100)},
+			data:     completions,
+			body:     map[string]any{},
+			wantBody: map[string]any{"max_tokens": int64(100)},
+		},
+		{
+			name:     "output limit set on a responses request",
+			spec:     v1alpha2.InferenceObjectiveSpec{MaxOutputTokens: ptr.To[int64](100)},
+			data:     responses,
+			body:     map[string]any{},
+			wantBody: map[string]any{"max_output_tokens": int64(100)},
+		},
+		{
+			name:     "output limit not set on a KServe request",
+			spec:     v1alpha2.InferenceObjectiveSpec{MaxOutputTokens: ptr.To[int64](100)},
+			data:     completions,
+			body:     map[string]any{"inputs": []any{}},
+			kserve:   true,
+			wantBody: map[string]any{"inputs": []any{}},
+		},
+		{
+			name:      "input tokens above the limit on a KServe request",
+			spec:      v1alpha2.InferenceObjectiveSpec{MaxInputTokens: ptr.To[int64](1)},
+			data:      completions,
+			body:      map[string]any{"inputs": []any{}},
+			kserve:    true,
+			expectErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := enforceTokenLimits(&v1alpha2.InferenceObjective{Spec: test.spec}, test.data, test.body, !test.kserve)
+			if (err != nil) != test.expectErr {
+				t.Fatalf("unexpected error %v, expected error %v", err, test.expectErr)
+			}
+			if test.expectErr {
+				return
+			}
+			if diff := cmp.Diff(test.wantBody, test.body); diff != "" {
+				t.Errorf("Unexpected body (-want +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestObjectiveBudgets(t *testing.T) {
+	budgets := newObjectiveBudgets()
+
+	rateLimited := &v1alpha2.InferenceObjective{Spec: v1alpha2.InferenceObjectiveSpec{
+		Budget: &v1alpha2.ObjectiveBudget{RequestsPerSecond: ptr.To[int32](2)},
+	}}
+	rateLimited.Name = "rate-limited"
+	for i := range 2 {
+		if slot, err := budgets.acquire(rateLimited); err != nil || slot {
+			t.Fatalf("request %d: acquire() = %v, %v, want false, nil", i, slot, err)
+		}
+	}
+	if _, err := budgets.acquire(rateLimited); err == nil {
+		t.Error("expected the rate budget to be exhausted")
+	}
+
+	concurrent := &v1alpha2.InferenceObjective{Spec: v1alpha2.InferenceObjectiveSpec{
+		Budget: &v1alpha2.ObjectiveBudget{MaxConcurrentRequests: ptr.To[int32](1)},
+	}}
+	concurrent.Name = "concurrent"
+	if slot, err := budgets.acquire(concurrent); err != nil || !slot {
+		t.Fatalf("acquire() = %v, %v, want true, nil", slot, err)
+	}
+	if _, err := budgets.acquire(concurrent); err == nil {
+		t.Error("expected the concurrency budget to be exhausted")
+	}
+	budgets.release(concurrent.Name)
+	if slot, err := budgets.acquire(concurrent); err != nil || !slot {
+		t.Errorf("acquire() after release = %v, %v, want true, nil", slot, err)
+	}
+
+	if slot, err := budgets.acquire(&v1alpha2.InferenceObjective{}); err != nil || slot {
+		t.Errorf("acquire() without budget = %v, %v, want false, nil", slot, err)
+	}
+}
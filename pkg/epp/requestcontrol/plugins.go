@@ -25,8 +25,14 @@ import (
 )
 
 const (
-	PreRequestExtensionPoint   = "PreRequest"
-	PostResponseExtensionPoint = "PostResponse"
+	PreRequestExtensionPoint        = "PreRequest"
+	RequestMutatorExtensionPoint    = "RequestMutator"
+	PostResponseExtensionPoint      = "PostResponse"
+	ResponseMutatorExtensionPoint   = "ResponseMutator"
+	ResponseStreamingExtensionPoint = "ResponseStreaming"
+	ResponseCompleteExtensionPoint  = "ResponseComplete"
+	OutlierTrackerExtensionPoint    = "OutlierTracker"
+	ModelProviderExtensionPoint     = "ModelProvider"
 )
 
 // PreRequest is called by the director after a getting result from scheduling layer and
@@ -36,9 +42,62 @@ type PreRequest interface {
 	PreRequest(ctx context.Context, request *types.LLMRequest, schedulingResult *types.SchedulingResult, targetPort int)
 }
 
+// RequestMutator is called by the director after the PreRequest plugins, before a request is sent to the
+// selected model server. It may modify the body and the headers of the request. Returning an error fails the
+// request; an errutil.Error is returned to the client as is, e.g. to reject a request with errutil.BadRequest.
+type RequestMutator interface {
+	plugins.Plugin
+	MutateRequest(ctx context.Context, request *types.LLMRequest, schedulingResult *types.SchedulingResult, mutable *Request) error
+}
+
 // PostResponse is called by the director after a successful response was sent.
 // The given pod argument is the pod that served the request.
 type PostResponse interface {
 	plugins.Plugin
 	PostResponse(ctx context.Context, request *types.LLMRequest, response *Response, targetPod *backend.Pod)
 }
+
+// ResponseMutator is called by the director when the response headers are received, and for every chunk of
+// the response body, before they are sent to the client. The body of a response that is not streamed is given
+// as a single chunk. It may modify the headers during header processing, and the body during body processing.
+// Since the body may change length, the content-length header is removed from responses when response mutators
+// are configured. The given pod argument is the pod that served the request.
+type ResponseMutator interface {
+	plugins.Plugin
+	MutateResponse(ctx context.Context, request *types.LLMRequest, response *Response, targetPod *backend.Pod)
+}
+
+// ResponseStreaming is called by the director for every chunk of a streamed response body, after the
+// ResponseMutator plugins, with the chunk that is sent to the client.
+// The given pod argument is the pod that served the request.
+type ResponseStreaming interface {
+	plugins.Plugin
+	ResponseStreaming(ctx context.Context, request *types.LLMRequest, response *Response, targetPod *backend.Pod)
+}
+
+// ResponseComplete is called by the director exactly once for every request that was sent to a model server,
+// when its response is complete, or when the request is cancelled (e.g. the client disconnected) before
+// its response is complete, in which case response.Cancelled is set.
+// The given pod argument is the pod that served the request.
+type ResponseComplete interface {
+	plugins.Plugin
+	ResponseComplete(ctx context.Context, request *types.LLMRequest, response *Response, targetPod *backend.Pod)
+}
+
+// OutlierTracker is called by the director with the outcome of every attempt to serve a request on a pod.
+// RecordFailure is called for a pod that failed to serve a request, either with a server error, or by being
+// skipped over by the gateway in favor of a fallback endpoint. RecordSuccess is called for a pod that served a
+// request without a server error.
+type OutlierTracker interface {
+	plugins.Plugin
+	RecordFailure(ctx context.Context, pod *backend.Pod)
+	RecordSuccess(ctx context.Context, pod *backend.Pod)
+}
+
+// ModelProvider is called by the director when no candidate pod serves the target model of a request, before the
+// request is rejected as not found. It returns true if the plugin makes the model available on demand, e.g. by
+// loading an adapter on the pod the request is scheduled to, in which case the request is scheduled as usual.
+type ModelProvider interface {
+	plugins.Plugin
+	ProvidesModel(ctx context.Context, model string) bool
+}
@@ -23,15 +23,28 @@ import (
 // NewConfig creates a new Config object and returns its pointer.
 func NewConfig() *Config {
 	return &Config{
-		preRequestPlugins:   []PreRequest{},
-		postResponsePlugins: []PostResponse{},
+		preRequestPlugins:        []PreRequest{},
+		requestMutatorPlugins:    []RequestMutator{},
+		postResponsePlugins:      []PostResponse{},
+		responseMutatorPlugins:   []ResponseMutator{},
+		responseStreamingPlugins: []ResponseStreaming{},
+		responseCompletePlugins:  []ResponseComplete{},
+		outlierTrackerPlugins:    []OutlierTracker{},
+		modelProviderPlugins:     []ModelProvider{},
 	}
 }
 
 // Config provides a configuration for the requestcontrol plugins.
 type Config struct {
-	preRequestPlugins   []PreRequest
-	postResponsePlugins []PostResponse
+	preRequestPlugins        []PreRequest
+	requestMutatorPlugins    []RequestMutator
+	postResponsePlugins      []PostResponse
+	responseMutatorPlugins   []ResponseMutator
+	responseStreamingPlugins []ResponseStreaming
+	responseCompletePlugins  []ResponseComplete
+	outlierTrackerPlugins    []OutlierTracker
+	modelProviderPlugins     []ModelProvider
+	injectStreamUsage        bool
 }
 
 // WithPreRequestPlugins sets the given plugins as the PreRequest plugins.
@@ -41,6 +54,13 @@ func (c *Config) WithPreRequestPlugins(plugins ...PreRequest) *Config {
 	return c
 }
 
+// WithRequestMutatorPlugins sets the given plugins as the RequestMutator plugins, which run in the given order.
+// If the Config has RequestMutator plugins already, this call replaces the existing plugins with the given ones.
+func (c *Config) WithRequestMutatorPlugins(plugins ...RequestMutator) *Config {
+	c.requestMutatorPlugins = plugins
+	return c
+}
+
 // WithPostResponsePlugins sets the given plugins as the PostResponse plugins.
 // If the Config has PostResponse plugins already, this call replaces the existing plugins with the given ones.
 func (c *Config) WithPostResponsePlugins(plugins ...PostResponse) *Config {
@@ -48,13 +68,74 @@ func (c *Config) WithPostResponsePlugins(plugins ...PostResponse) *Config {
 	return c
 }
 
+// WithResponseMutatorPlugins sets the given plugins as the ResponseMutator plugins, which run in the given order.
+// If the Config has ResponseMutator plugins already, this call replaces the existing plugins with the given ones.
+func (c *Config) WithResponseMutatorPlugins(plugins ...ResponseMutator) *Config {
+	c.responseMutatorPlugins = plugins
+	return c
+}
+
+// WithResponseStreamingPlugins sets the given plugins as the ResponseStreaming plugins.
+// If the Config has ResponseStreaming plugins already, this call replaces the existing plugins with the given ones.
+func (c *Config) WithResponseStreamingPlugins(plugins ...ResponseStreaming) *Config {
+	c.responseStreamingPlugins = plugins
+	return c
+}
+
+// WithResponseCompletePlugins sets the given plugins as the ResponseComplete plugins.
+// If the Config has ResponseComplete plugins already, this call replaces the existing plugins with the given ones.
+func (c *Config) WithResponseCompletePlugins(plugins ...ResponseComplete) *Config {
+	c.responseCompletePlugins = plugins
+	return c
+}
+
+// WithOutlierTrackerPlugins sets the given plugins as the OutlierTracker plugins.
+// If the Config has OutlierTracker plugins already, this call replaces the existing plugins with the given ones.
+func (c *Config) WithOutlierTrackerPlugins(plugins ...OutlierTracker) *Config {
+	c.outlierTrackerPlugins = plugins
+	return c
+}
+
+// WithModelProviderPlugins sets the given plugins as the ModelProvider plugins.
+// If the Config has ModelProvider plugins already, this call replaces the existing plugins with the given ones.
+func (c *Config) WithModelProviderPlugins(plugins ...ModelProvider) *Config {
+	c.modelProviderPlugins = plugins
+	return c
+}
+
+// WithStreamUsageInjection sets whether `stream_options.include_usage` is set on streaming completions and
+// chat-completions requests, so that the token usage of streamed responses is always reported.
+func (c *Config) WithStreamUsageInjection(enabled bool) *Config {
+	c.injectStreamUsage = enabled
+	return c
+}
+
+// AddPlugins adds the given plugins to the extension points they implement, keeping the order of the plugins.
 func (c *Config) AddPlugins(pluginObjects ...plugins.Plugin) {
 	for _, plugin := range pluginObjects {
 		if preRequestPlugin, ok := plugin.(PreRequest); ok {
 			c.preRequestPlugins = append(c.preRequestPlugins, preRequestPlugin)
 		}
+		if requestMutatorPlugin, ok := plugin.(RequestMutator); ok {
+			c.requestMutatorPlugins = append(c.requestMutatorPlugins, requestMutatorPlugin)
+		}
 		if postResponsePlugin, ok := plugin.(PostResponse); ok {
 			c.postResponsePlugins = append(c.postResponsePlugins, postResponsePlugin)
 		}
+		if responseMutatorPlugin, ok := plugin.(ResponseMutator); ok {
+			c.responseMutatorPlugins = append(c.responseMutatorPlugins, responseMutatorPlugin)
+		}
+		if responseStreamingPlugin, ok := plugin.(ResponseStreaming); ok {
+			c.responseStreamingPlugins = append(c.responseStreamingPlugins, responseStreamingPlugin)
+		}
+		if responseCompletePlugin, ok := plugin.(ResponseComplete); ok {
+			c.responseCompletePlugins = append(c.responseCompletePlugins, responseCompletePlugin)
+		}
+		if outlierTrackerPlugin, ok := plugin.(OutlierTracker); ok {
+			c.outlierTrackerPlugins = append(c.outlierTrackerPlugins, outlierTrackerPlugin)
+		}
+		if modelProviderPlugin, ok := plugin.(ModelProvider); ok {
+			c.modelProviderPlugins = append(c.modelProviderPlugins, modelProviderPlugin)
+		}
 	}
 }
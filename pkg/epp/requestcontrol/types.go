@@ -16,6 +16,21 @@ limitations under the License.
 
 package requestcontrol
 
+import (
+	"time"
+
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/handlers"
+)
+
+// Request contains the parts of the request that RequestMutator plugins can modify
+type Request struct {
+	// Headers is a map of the request headers. Headers that are added or changed are sent to the model server,
+	// while removing a header from the map doesn't remove it from the request
+	Headers map[string]string
+	// Body is the decoded request body, which is encoded again after all the mutators ran
+	Body map[string]any
+}
+
 // Response contains information from the response received to be passed to PostResponse plugins
 type Response struct {
 	// RequestId is the Envoy generated Id for the request being processed
@@ -28,4 +43,17 @@ type Response struct {
 	IsStreaming bool
 	// EndOfStream when true indicates that this invocation contains the last chunk of the response
 	EndOfStream bool
+	// Usage is the token usage reported by the model server, once it is received
+	Usage handlers.Usage
+	// RequestReceivedTimestamp is the time at which the request was received
+	RequestReceivedTimestamp time.Time
+	// FirstTokenTimestamp is the time at which the first generated token of a streamed response was received,
+	// or zero if none was received yet
+	FirstTokenTimestamp time.Time
+	// ResponseCompleteTimestamp is the time at which the response completed or the request was cancelled.
+	// Set for ResponseComplete plugins only
+	ResponseCompleteTimestamp time.Time
+	// Cancelled indicates that the request was cancelled before its response was complete.
+	// Set for ResponseComplete plugins only
+	Cancelled bool
 }
@@ -17,6 +17,7 @@ package saturationdetector
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"sigs.k8s.io/controller-runtime/pkg/log"
@@ -42,6 +43,8 @@ const (
 	EnvSdQueueDepthThreshold       = "SD_QUEUE_DEPTH_THRESHOLD"
 	EnvSdKVCacheUtilThreshold      = "SD_KV_CACHE_UTIL_THRESHOLD"
 	EnvSdMetricsStalenessThreshold = "SD_METRICS_STALENESS_THRESHOLD"
+	EnvSdLoraPriorityAdapters      = "SD_LORA_PRIORITY_ADAPTERS"
+	EnvSdLoraReservedSlots         = "SD_LORA_RESERVED_SLOTS"
 )
 
 // LoadConfigFromEnv loads SaturationDetector Config from environment variables.
@@ -66,6 +69,18 @@ func LoadConfigFromEnv() *Config {
 		cfg.MetricsStalenessThreshold = DefaultMetricsStalenessThreshold
 	}
 
+	// the priority adapters are given as a comma separated list.
+	for _, adapter := range strings.Split(envutil.GetEnvString(EnvSdLoraPriorityAdapters, "", logger), ",") {
+		if adapter = strings.TrimSpace(adapter); adapter != "" {
+			cfg.PriorityAdapters = append(cfg.PriorityAdapters, adapter)
+		}
+	}
+
+	cfg.LoraReservedSlots = envutil.GetEnvInt(EnvSdLoraReservedSlots, 0, logger)
+	if cfg.LoraReservedSlots < 0 {
+		cfg.LoraReservedSlots = 0
+	}
+
 	// NewDetector validates the config and assigns defaults.
 	logger.Info("SaturationDetector configuration loaded from env", "config", fmt.Sprintf("%+v", cfg))
 	return cfg
@@ -0,0 +1,229 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package saturationdetector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/plugins"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/requestcontrol"
+	schedulingtypes "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/logging"
+)
+
+const (
+	HysteresisSaturationDetectorType = "hysteresis-saturation-detector"
+
+	// DefaultExitQueueDepthThreshold is the default backend waiting queue size a pod has to be at or below for
+	// the system to leave saturation.
+	DefaultExitQueueDepthThreshold = 3
+	// DefaultExitKVCacheUtilThreshold is the default KV cache utilization a pod has to be at or below for the
+	// system to leave saturation.
+	DefaultExitKVCacheUtilThreshold = 0.7
+	// DefaultMinHoldTime is the default minimum time the saturation signal holds after it changes.
+	DefaultMinHoldTime = 5 * time.Second
+)
+
+// HysteresisParameters are the parameters of the HysteresisDetector.
+type HysteresisParameters struct {
+	// EnterQueueDepthThreshold is the backend waiting queue size above which a pod has insufficient capacity
+	// while the system is not saturated.
+	EnterQueueDepthThreshold int `json:"enterQueueDepthThreshold"`
+	// EnterKVCacheUtilThreshold is the KV cache utilization above which a pod has insufficient capacity while the
+	// system is not saturated.
+	EnterKVCacheUtilThreshold float64 `json:"enterKVCacheUtilThreshold"`
+	// ExitQueueDepthThreshold is the backend waiting queue size above which a pod has insufficient capacity while
+	// the system is saturated. It must not be above EnterQueueDepthThreshold.
+	ExitQueueDepthThreshold int `json:"exitQueueDepthThreshold"`
+	// ExitKVCacheUtilThreshold is the KV cache utilization above which a pod has insufficient capacity while the
+	// system is saturated. It must not be above EnterKVCacheUtilThreshold.
+	ExitKVCacheUtilThreshold float64 `json:"exitKVCacheUtilThreshold"`
+	// MetricsStalenessThreshold is how old the metrics of a pod can be for the pod to have capacity.
+	MetricsStalenessThreshold metav1.Duration `json:"metricsStalenessThreshold"`
+	// MinHoldTime is the minimum time the saturation signal holds after it changes.
+	MinHoldTime metav1.Duration `json:"minHoldTime"`
+	// PriorityAdapters are the LoRA adapters the reserved adapter slots of every pod are reserved for.
+	PriorityAdapters []string `json:"priorityAdapters"`
+	// LoraReservedSlots is the number of adapter slots of every pod that are reserved for the PriorityAdapters.
+	LoraReservedSlots int `json:"loraReservedSlots"`
+}
+
+// compile-time type assertion
+var (
+	_ SaturationDetector                     = &HysteresisDetector{}
+	_ requestcontrol.ModelSaturationDetector = &HysteresisDetector{}
+	_ requestcontrol.AdapterSlotReservation  = &HysteresisDetector{}
+)
+
+// HysteresisSaturationDetectorFactory returns the factory function of the HysteresisDetector, which lists the pods
+// from the datastore.
+func HysteresisSaturationDetectorFactory(datastore Datastore) plugins.FactoryFunc {
+	return func(name string, rawParameters json.RawMessage, _ plugins.Handle) (plugins.Plugin, error) {
+		parameters := HysteresisParameters{
+			EnterQueueDepthThreshold:  DefaultQueueDepthThreshold,
+			EnterKVCacheUtilThreshold: DefaultKVCacheUtilThreshold,
+			ExitQueueDepthThreshold:   DefaultExitQueueDepthThreshold,
+			ExitKVCacheUtilThreshold:  DefaultExitKVCacheUtilThreshold,
+			MetricsStalenessThreshold: metav1.Duration{Duration: DefaultMetricsStalenessThreshold},
+			MinHoldTime:               metav1.Duration{Duration: DefaultMinHoldTime},
+		}
+		if rawParameters != nil {
+			if err := json.Unmarshal(rawParameters, &parameters); err != nil {
+				return nil, fmt.Errorf("failed to parse the parameters of the '%s' saturation detector - %w", HysteresisSaturationDetectorType, err)
+			}
+		}
+		if err := validateHysteresisParameters(parameters); err != nil {
+			return nil, fmt.Errorf("invalid parameters of the '%s' saturation detector - %w", HysteresisSaturationDetectorType, err)
+		}
+
+		return NewHysteresisDetector(parameters, datastore).WithName(name), nil
+	}
+}
+
+func validateHysteresisParameters(parameters HysteresisParameters) error {
+	enter := &Config{
+		QueueDepthThreshold:       parameters.EnterQueueDepthThreshold,
+		KVCacheUtilThreshold:      parameters.EnterKVCacheUtilThreshold,
+		MetricsStalenessThreshold: parameters.MetricsStalenessThreshold.Duration,
+		PriorityAdapters:          parameters.PriorityAdapters,
+		LoraReservedSlots:         parameters.LoraReservedSlots,
+	}
+	exit := &Config{
+		QueueDepthThreshold:       parameters.ExitQueueDepthThreshold,
+		KVCacheUtilThreshold:      parameters.ExitKVCacheUtilThreshold,
+		MetricsStalenessThreshold: parameters.MetricsStalenessThreshold.Duration,
+	}
+	if err := validateConfig(enter); err != nil {
+		return err
+	}
+	if err := validateConfig(exit); err != nil {
+		return err
+	}
+	switch {
+	case parameters.ExitQueueDepthThreshold > parameters.EnterQueueDepthThreshold:
+		return fmt.Errorf("exitQueueDepthThreshold %d must not be above enterQueueDepthThreshold %d",
+			parameters.ExitQueueDepthThreshold, parameters.EnterQueueDepthThreshold)
+	case parameters.ExitKVCacheUtilThreshold > parameters.EnterKVCacheUtilThreshold:
+		return fmt.Errorf("exitKVCacheUtilThreshold %v must not be above enterKVCacheUtilThreshold %v",
+			parameters.ExitKVCacheUtilThreshold, parameters.EnterKVCacheUtilThreshold)
+	case parameters.MinHoldTime.Duration < 0:
+		return fmt.Errorf("minHoldTime %v must not be negative", parameters.MinHoldTime.Duration)
+	}
+	return nil
+}
+
+// NewHysteresisDetector initializes a new HysteresisDetector and returns its pointer.
+func NewHysteresisDetector(parameters HysteresisParameters, datastore Datastore) *HysteresisDetector {
+	return &HysteresisDetector{
+		typedName: plugins.TypedName{Type: HysteresisSaturationDetectorType, Name: HysteresisSaturationDetectorType},
+		enter: &Detector{datastore: datastore, config: &Config{
+			QueueDepthThreshold:       parameters.EnterQueueDepthThreshold,
+			KVCacheUtilThreshold:      parameters.EnterKVCacheUtilThreshold,
+			MetricsStalenessThreshold: parameters.MetricsStalenessThreshold.Duration,
+			PriorityAdapters:          parameters.PriorityAdapters,
+			LoraReservedSlots:         parameters.LoraReservedSlots,
+		}},
+		exit: &Detector{datastore: datastore, config: &Config{
+			QueueDepthThreshold:       parameters.ExitQueueDepthThreshold,
+			KVCacheUtilThreshold:      parameters.ExitKVCacheUtilThreshold,
+			MetricsStalenessThreshold: parameters.MetricsStalenessThreshold.Duration,
+		}},
+		minHoldTime: parameters.MinHoldTime.Duration,
+		now:         time.Now,
+	}
+}
+
+// HysteresisDetector is a saturation detector with separate thresholds for entering and for leaving saturation.
+// The system enters saturation when no pod has capacity within the enter thresholds, and leaves it when a pod has
+// capacity within the lower exit thresholds, so metrics that hover around a threshold don't flip the signal on
+// every scrape. In addition, the signal holds for at least MinHoldTime after it changes.
+type HysteresisDetector struct {
+	typedName   plugins.TypedName
+	enter       *Detector
+	exit        *Detector
+	minHoldTime time.Duration
+	now         func() time.Time
+
+	mu          sync.Mutex
+	saturated   bool
+	lastChanged time.Time
+}
+
+// TypedName returns the type and name tuple of this plugin instance.
+func (d *HysteresisDetector) TypedName() plugins.TypedName {
+	return d.typedName
+}
+
+// WithName sets the name of the detector.
+func (d *HysteresisDetector) WithName(name string) *HysteresisDetector {
+	d.typedName.Name = name
+	return d
+}
+
+// IsSaturated checks if the system is currently considered saturated.
+func (d *HysteresisDetector) IsSaturated(ctx context.Context) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := d.now()
+	if !d.lastChanged.IsZero() && now.Sub(d.lastChanged) < d.minHoldTime {
+		return d.saturated
+	}
+
+	var saturated bool
+	if d.saturated {
+		saturated = d.exit.IsSaturated(ctx)
+	} else {
+		saturated = d.enter.IsSaturated(ctx)
+	}
+	if saturated != d.saturated {
+		log.FromContext(ctx).WithName(loggerName).V(logutil.DEFAULT).Info("Saturation changed", "saturated", saturated)
+		d.saturated = saturated
+		d.lastChanged = now
+	}
+	return d.saturated
+}
+
+// IsModelSaturated checks if the system is currently considered saturated for requests for the model. It is
+// saturated while the system is saturated, and otherwise if no pod has capacity within the enter thresholds and
+// room for the model, by the adapter slots of the threshold based Detector (see Detector.IsModelSaturated).
+func (d *HysteresisDetector) IsModelSaturated(ctx context.Context, model string) bool {
+	return d.IsSaturated(ctx) || d.enter.IsModelSaturated(ctx, model)
+}
+
+// PodsWithAdapterSlot returns the pods that have an adapter slot for the model that is not reserved for the
+// PriorityAdapters (see Detector.PodsWithAdapterSlot).
+func (d *HysteresisDetector) PodsWithAdapterSlot(ctx context.Context, model string, pods []schedulingtypes.Pod) []schedulingtypes.Pod {
+	return d.enter.PodsWithAdapterSlot(ctx, model, pods)
+}
+
+// RetryAfter estimates how long it takes until a saturated system has capacity for new requests again, based on
+// the exit thresholds. The estimate is at least the remaining hold time of the saturation signal.
+func (d *HysteresisDetector) RetryAfter(ctx context.Context) time.Duration {
+	d.mu.Lock()
+	hold := d.minHoldTime - d.now().Sub(d.lastChanged)
+	d.mu.Unlock()
+
+	return min(max(d.exit.RetryAfter(ctx), hold), maxRetryAfter)
+}
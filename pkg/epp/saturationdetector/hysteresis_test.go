@@ -0,0 +1,143 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package saturationdetector
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/plugins"
+	"sigs.k8s.io/gateway-api-inference-extension/test/utils"
+)
+
+func TestHysteresisDetector_IsSaturated(t *testing.T) {
+	pod := newMockPodMetrics("pod1", &backendmetrics.MetricsState{})
+	datastore := &mockDatastore{pods: []*backendmetrics.FakePodMetrics{pod}}
+	detector := NewHysteresisDetector(HysteresisParameters{
+		EnterQueueDepthThreshold:  10,
+		EnterKVCacheUtilThreshold: 0.9,
+		ExitQueueDepthThreshold:   5,
+		ExitKVCacheUtilThreshold:  0.7,
+		MetricsStalenessThreshold: metav1.Duration{Duration: time.Hour},
+		MinHoldTime:               metav1.Duration{Duration: 10 * time.Second},
+	}, datastore)
+	now := time.Now()
+	detector.now = func() time.Time { return now }
+
+	steps := []struct {
+		name       string
+		elapsed    time.Duration
+		queueDepth int
+		kvCache    float64
+		expected   bool
+	}{
+		{name: "below the enter thresholds", queueDepth: 8, kvCache: 0.5, expected: false},
+		{name: "above the enter queue threshold", elapsed: time.Second, queueDepth: 11, kvCache: 0.5, expected: true},
+		{name: "back below the enter thresholds within the hold time", elapsed: 5 * time.Second, queueDepth: 4, kvCache: 0.5, expected: true},
+		{name: "between the thresholds after the hold time", elapsed: 20 * time.Second, queueDepth: 8, kvCache: 0.5, expected: true},
+		{name: "kv cache between the thresholds", elapsed: 21 * time.Second, queueDepth: 2, kvCache: 0.8, expected: true},
+		{name: "below the exit thresholds", elapsed: 22 * time.Second, queueDepth: 5, kvCache: 0.7, expected: false},
+		{name: "between the thresholds again", elapsed: 40 * time.Second, queueDepth: 8, kvCache: 0.8, expected: false},
+		{name: "above the enter kv cache threshold within the hold time", elapsed: 25 * time.Second, queueDepth: 0, kvCache: 0.95, expected: false},
+		{name: "above the enter kv cache threshold after the hold time", elapsed: 41 * time.Second, queueDepth: 0, kvCache: 0.95, expected: true},
+	}
+	for _, step := range steps {
+		detector.now = func() time.Time { return now.Add(step.elapsed) }
+		pod.Metrics = &backendmetrics.MetricsState{
+			WaitingQueueSize:    step.queueDepth,
+			KVCacheUsagePercent: step.kvCache,
+			UpdateTime:          time.Now(),
+		}
+		if got := detector.IsSaturated(context.Background()); got != step.expected {
+			t.Fatalf("%s: IsSaturated() = %v, want %v", step.name, got, step.expected)
+		}
+	}
+}
+
+func TestHysteresisDetector_IsModelSaturated(t *testing.T) {
+	// pod1 has capacity with only its reserved adapter slot free, and pod2 has lora3 active without capacity.
+	pod1 := newMockPodMetrics("pod1", &backendmetrics.MetricsState{ActiveModels: map[string]int{"lora1": 1, "lora2": 1},
+		MaxActiveModels: 3, UpdateTime: time.Now()})
+	pod2 := newMockPodMetrics("pod2", &backendmetrics.MetricsState{WaitingQueueSize: 20, ActiveModels: map[string]int{"lora3": 1},
+		MaxActiveModels: 3, UpdateTime: time.Now()})
+	detector := NewHysteresisDetector(HysteresisParameters{
+		EnterQueueDepthThreshold:  10,
+		EnterKVCacheUtilThreshold: 0.9,
+		ExitQueueDepthThreshold:   5,
+		ExitKVCacheUtilThreshold:  0.7,
+		MetricsStalenessThreshold: metav1.Duration{Duration: time.Hour},
+		PriorityAdapters:          []string{"premium-lora"},
+		LoraReservedSlots:         1,
+	}, &mockDatastore{pods: []*backendmetrics.FakePodMetrics{pod1, pod2}})
+
+	for model, expected := range map[string]bool{"lora3": true, "premium-lora": false, "base-model": false} {
+		if got := detector.IsModelSaturated(context.Background(), model); got != expected {
+			t.Errorf("IsModelSaturated(%s) = %v, want %v", model, got, expected)
+		}
+	}
+
+	// every model is saturated while the system is saturated.
+	pod1.Metrics = &backendmetrics.MetricsState{WaitingQueueSize: 20, UpdateTime: time.Now()}
+	if !detector.IsModelSaturated(context.Background(), "base-model") {
+		t.Errorf("Expected the base model to be saturated while the system is saturated")
+	}
+}
+
+func TestHysteresisSaturationDetectorFactory(t *testing.T) {
+	tests := []struct {
+		name      string
+		params    map[string]any
+		expectErr bool
+	}{
+		{name: "defaults", params: nil},
+		{
+			name: "valid",
+			params: map[string]any{"enterQueueDepthThreshold": 10, "exitQueueDepthThreshold": 2, "enterKVCacheUtilThreshold": 0.9,
+				"exitKVCacheUtilThreshold": 0.5, "minHoldTime": "30s"},
+		},
+		{name: "exit queue threshold above enter", params: map[string]any{"exitQueueDepthThreshold": 6}, expectErr: true},
+		{name: "exit kv cache threshold above enter", params: map[string]any{"exitKVCacheUtilThreshold": 0.85}, expectErr: true},
+		{name: "negative hold time", params: map[string]any{"minHoldTime": "-1s"}, expectErr: true},
+		{name: "invalid kv cache threshold", params: map[string]any{"enterKVCacheUtilThreshold": 1.5}, expectErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var raw json.RawMessage
+			if test.params != nil {
+				raw, _ = json.Marshal(test.params)
+			}
+			plugin, err := HysteresisSaturationDetectorFactory(&mockDatastore{})("hysteresis", raw, utils.NewTestHandle(context.Background()))
+			if test.expectErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := plugin.TypedName(); got != (plugins.TypedName{Type: HysteresisSaturationDetectorType, Name: "hysteresis"}) {
+				t.Errorf("unexpected typed name %v", got)
+			}
+		})
+	}
+}
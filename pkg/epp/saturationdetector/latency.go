@@ -0,0 +1,328 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package saturationdetector
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend"
+	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/plugins"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/requestcontrol"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/logging"
+)
+
+const (
+	LatencySaturationDetectorType = "latency-saturation-detector"
+
+	// DefaultLatencyPercentile is the default percentile of the latencies that is compared with the targets.
+	DefaultLatencyPercentile = 0.9
+	// DefaultLatencyWindow is the default rolling window the latencies are tracked over.
+	DefaultLatencyWindow = 30 * time.Second
+	// DefaultLatencyTrendWindow is the default window of the latest latencies the trend is measured over.
+	DefaultLatencyTrendWindow = 10 * time.Second
+	// DefaultLatencyMinSamples is the default minimum number of latencies within the window for them to be considered.
+	DefaultLatencyMinSamples = 10
+
+	// maxLatencySamples bounds the number of latencies tracked per pool and per pod.
+	maxLatencySamples = 1000
+)
+
+// LatencyParameters are the parameters of the LatencyDetector.
+type LatencyParameters struct {
+	// TTFTTarget is the target time to first token of streamed responses whose objective has no time to first
+	// token SLO. 0 disables it.
+	TTFTTarget metav1.Duration `json:"ttftTarget"`
+	// E2ELatencyTarget is the target end-to-end latency of responses whose objective has no end-to-end latency
+	// SLO. 0 disables it.
+	E2ELatencyTarget metav1.Duration `json:"e2eLatencyTarget"`
+	// Percentile is the percentile, between 0 and 1, of the latencies within the window that is compared with the
+	// targets.
+	Percentile float64 `json:"percentile"`
+	// Window is the rolling window the latencies are tracked over.
+	Window metav1.Duration `json:"window"`
+	// TrendWindow is the window of the latest latencies the trend is measured over, by comparing their percentile
+	// with the percentile of the earlier latencies within the window. It must be shorter than the window.
+	// 0 disables trend detection.
+	TrendWindow metav1.Duration `json:"trendWindow"`
+	// MinSamples is the minimum number of latencies within the window, and within the trend window, for them to
+	// be considered.
+	MinSamples int `json:"minSamples"`
+}
+
+// compile-time type assertion
+var (
+	_ SaturationDetector              = &LatencyDetector{}
+	_ requestcontrol.ResponseComplete = &LatencyDetector{}
+)
+
+// LatencySaturationDetectorFactory returns the factory function of the LatencyDetector, which lists the pods
+// from the datastore.
+func LatencySaturationDetectorFactory(datastore Datastore) plugins.FactoryFunc {
+	return func(name string, rawParameters json.RawMessage, _ plugins.Handle) (plugins.Plugin, error) {
+		parameters := LatencyParameters{
+			Percentile:  DefaultLatencyPercentile,
+			Window:      metav1.Duration{Duration: DefaultLatencyWindow},
+			TrendWindow: metav1.Duration{Duration: DefaultLatencyTrendWindow},
+			MinSamples:  DefaultLatencyMinSamples,
+		}
+		if rawParameters != nil {
+			if err := json.Unmarshal(rawParameters, &parameters); err != nil {
+				return nil, fmt.Errorf("failed to parse the parameters of the '%s' saturation detector - %w", LatencySaturationDetectorType, err)
+			}
+		}
+		if err := validateLatencyParameters(parameters); err != nil {
+			return nil, fmt.Errorf("invalid parameters of the '%s' saturation detector - %w", LatencySaturationDetectorType, err)
+		}
+
+		return NewLatencyDetector(parameters, datastore).WithName(name), nil
+	}
+}
+
+func validateLatencyParameters(parameters LatencyParameters) error {
+	switch {
+	case parameters.TTFTTarget.Duration < 0 || parameters.E2ELatencyTarget.Duration < 0:
+		return errors.New("the latency targets must not be negative")
+	case parameters.Percentile <= 0 || parameters.Percentile > 1:
+		return fmt.Errorf("percentile %v must be greater than 0 and at most 1", parameters.Percentile)
+	case parameters.Window.Duration <= 0:
+		return fmt.Errorf("window %v must be positive", parameters.Window.Duration)
+	case parameters.TrendWindow.Duration < 0 || parameters.TrendWindow.Duration >= parameters.Window.Duration:
+		return fmt.Errorf("trendWindow %v must not be negative and must be shorter than the window %v",
+			parameters.TrendWindow.Duration, parameters.Window.Duration)
+	case parameters.MinSamples < 1:
+		return fmt.Errorf("minSamples %d must be positive", parameters.MinSamples)
+	}
+	return nil
+}
+
+// NewLatencyDetector initializes a new LatencyDetector and returns its pointer.
+func NewLatencyDetector(parameters LatencyParameters, datastore Datastore) *LatencyDetector {
+	return &LatencyDetector{
+		typedName:  plugins.TypedName{Type: LatencySaturationDetectorType, Name: LatencySaturationDetectorType},
+		datastore:  datastore,
+		parameters: parameters,
+		now:        time.Now,
+		pool:       &latencySeries{},
+		pods:       map[string]*latencySeries{},
+	}
+}
+
+// LatencyDetector is a saturation detector that decides saturation from the latencies the users experience,
+// rather than from the metrics the model servers report. It tracks the time to first token of streamed
+// responses and the end-to-end latency of all responses, over a rolling window, for the pool and for every pod,
+// from the completed responses.
+//
+// The target of a latency is the SLO of the objective of the request if it has one, and the configured target
+// otherwise; a latency without a target is not tracked. Latencies are tracked relative to their targets, so that
+// requests of objectives with different SLOs are compared on the same scale.
+//
+// A series of latencies is over its target if the percentile of the latencies within the window is above the
+// target, or if the latencies are rising quickly: the percentile of the latest latencies, within the trend
+// window, is projected to exceed the target within another trend window if it keeps rising at the rate it
+// rose from the percentile of the earlier latencies.
+//
+// The system is saturated if the latencies of the pool are over their targets, and no pod has capacity. A pod
+// has capacity unless its own latencies are over their targets, so that a pod that recently joined the pool, or
+// that no longer gets requests, ends the saturation.
+type LatencyDetector struct {
+	typedName  plugins.TypedName
+	datastore  Datastore
+	parameters LatencyParameters
+	now        func() time.Time
+
+	mu   sync.Mutex
+	pool *latencySeries
+	pods map[string]*latencySeries // by the namespaced name of the pod
+}
+
+// latencySeries holds the latencies observed within the window, relative to their targets, oldest first.
+type latencySeries struct {
+	ttft []latencySample
+	e2e  []latencySample
+}
+
+type latencySample struct {
+	timestamp time.Time
+	ratio     float64 // the latency divided by its target
+}
+
+// TypedName returns the type and name tuple of this plugin instance.
+func (d *LatencyDetector) TypedName() plugins.TypedName {
+	return d.typedName
+}
+
+// WithName sets the name of the detector.
+func (d *LatencyDetector) WithName(name string) *LatencyDetector {
+	d.typedName.Name = name
+	return d
+}
+
+// ResponseComplete records the time to first token and the end-to-end latency of a completed response.
+func (d *LatencyDetector) ResponseComplete(_ context.Context, request *types.LLMRequest, response *requestcontrol.Response, targetPod *backend.Pod) {
+	ttftTarget, e2eTarget := d.parameters.TTFTTarget.Duration, d.parameters.E2ELatencyTarget.Duration
+	if request != nil {
+		if request.TTFTSLO > 0 {
+			ttftTarget = request.TTFTSLO
+		}
+		if request.E2ELatencySLO > 0 {
+			e2eTarget = request.E2ELatencySLO
+		}
+	}
+	if targetPod == nil || response.Cancelled || response.RequestReceivedTimestamp.IsZero() || response.ResponseCompleteTimestamp.IsZero() {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := d.now()
+	name := targetPod.NamespacedName.String()
+	pod, ok := d.pods[name]
+	if !ok {
+		pod = &latencySeries{}
+		d.pods[name] = pod
+	}
+	if e2eTarget > 0 {
+		e2e := latencySample{timestamp: now, ratio: ratio(response.ResponseCompleteTimestamp.Sub(response.RequestReceivedTimestamp), e2eTarget)}
+		d.pool.e2e = appendSample(d.pool.e2e, e2e)
+		pod.e2e = appendSample(pod.e2e, e2e)
+	}
+	if ttftTarget > 0 && !response.FirstTokenTimestamp.IsZero() {
+		ttft := latencySample{timestamp: now, ratio: ratio(response.FirstTokenTimestamp.Sub(response.RequestReceivedTimestamp), ttftTarget)}
+		d.pool.ttft = appendSample(d.pool.ttft, ttft)
+		pod.ttft = appendSample(pod.ttft, ttft)
+	}
+}
+
+func ratio(latency, target time.Duration) float64 {
+	return float64(latency) / float64(target)
+}
+
+// appendSample appends a sample to the samples, dropping the oldest ones beyond maxLatencySamples.
+func appendSample(samples []latencySample, sample latencySample) []latencySample {
+	samples = append(samples, sample)
+	if len(samples) > maxLatencySamples {
+		samples = slices.Delete(samples, 0, len(samples)-maxLatencySamples)
+	}
+	return samples
+}
+
+// IsSaturated checks if the system is currently considered saturated.
+func (d *LatencyDetector) IsSaturated(ctx context.Context) bool {
+	logger := log.FromContext(ctx).WithName(loggerName)
+	podMetrics := d.datastore.PodList(backendmetrics.AllPodsPredicate)
+	if len(podMetrics) == 0 {
+		logger.V(logutil.VERBOSE).Info("No pods found in datastore; system is considered SATURATED (no capacity).")
+		return true
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := d.now()
+	d.prune(now, podMetrics)
+	if !d.isOverTarget(d.pool, now) {
+		return false
+	}
+	for _, podMetric := range podMetrics {
+		pod, ok := d.pods[podMetric.GetPod().NamespacedName.String()]
+		if !ok || !d.isOverTarget(pod, now) {
+			logger.V(logutil.TRACE).Info("Found pod with latencies within the targets", "pod", podMetric.GetPod().NamespacedName)
+			return false
+		}
+	}
+
+	logger.V(logutil.VERBOSE).Info("Pool and pod latencies are over the targets; system is considered SATURATED.")
+	return true
+}
+
+// prune drops the latencies that are older than the window, and the pods that are no longer in the datastore.
+func (d *LatencyDetector) prune(now time.Time, podMetrics []backendmetrics.PodMetrics) {
+	oldest := now.Add(-d.parameters.Window.Duration)
+	d.pool.prune(oldest)
+	current := make(map[string]bool, len(podMetrics))
+	for _, podMetric := range podMetrics {
+		current[podMetric.GetPod().NamespacedName.String()] = true
+	}
+	for name, pod := range d.pods {
+		if !current[name] {
+			delete(d.pods, name)
+			continue
+		}
+		pod.prune(oldest)
+	}
+}
+
+func (s *latencySeries) prune(oldest time.Time) {
+	isOld := func(sample latencySample) bool { return sample.timestamp.Before(oldest) }
+	s.ttft = slices.DeleteFunc(s.ttft, isOld)
+	s.e2e = slices.DeleteFunc(s.e2e, isOld)
+}
+
+// isOverTarget returns true if the time to first token or the end-to-end latencies of the series are over
+// their targets.
+func (d *LatencyDetector) isOverTarget(series *latencySeries, now time.Time) bool {
+	return d.samplesOverTarget(series.ttft, now) || d.samplesOverTarget(series.e2e, now)
+}
+
+// samplesOverTarget returns true if the percentile of the samples is above their targets, or if the percentile
+// of the samples within the trend window is projected to exceed their targets within another trend window.
+func (d *LatencyDetector) samplesOverTarget(samples []latencySample, now time.Time) bool {
+	if len(samples) < d.parameters.MinSamples {
+		return false
+	}
+	if d.percentile(samples) > 1 {
+		return true
+	}
+	if d.parameters.TrendWindow.Duration == 0 {
+		return false
+	}
+
+	trendStart := now.Add(-d.parameters.TrendWindow.Duration)
+	split, _ := slices.BinarySearchFunc(samples, trendStart, func(sample latencySample, start time.Time) int {
+		return sample.timestamp.Compare(start)
+	})
+	earlier, latest := samples[:split], samples[split:]
+	if len(earlier) < d.parameters.MinSamples || len(latest) < d.parameters.MinSamples {
+		return false
+	}
+	latestPercentile := d.percentile(latest)
+	return 2*latestPercentile-d.percentile(earlier) > 1
+}
+
+// percentile returns the configured percentile of the latency ratios of the samples, which must not be empty.
+func (d *LatencyDetector) percentile(samples []latencySample) float64 {
+	ratios := make([]float64, len(samples))
+	for i, sample := range samples {
+		ratios[i] = sample.ratio
+	}
+	slices.Sort(ratios)
+	index := int(math.Ceil(d.parameters.Percentile*float64(len(ratios)))) - 1
+	return ratios[max(index, 0)]
+}
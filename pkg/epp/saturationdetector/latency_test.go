@@ -0,0 +1,304 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package saturationdetector
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/requestcontrol"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+	"sigs.k8s.io/gateway-api-inference-extension/test/utils"
+)
+
+func TestLatencyDetector_IsSaturated(t *testing.T) {
+	parameters := LatencyParameters{
+		TTFTTarget:       metav1.Duration{Duration: 500 * time.Millisecond},
+		E2ELatencyTarget: metav1.Duration{Duration: 10 * time.Second},
+		Percentile:       0.9,
+		Window:           metav1.Duration{Duration: 30 * time.Second},
+		TrendWindow:      metav1.Duration{Duration: 10 * time.Second},
+		MinSamples:       5,
+	}
+
+	// sample is a response completed the given time before the evaluation.
+	type sample struct {
+		pod  string
+		ago  time.Duration
+		ttft time.Duration // 0 for a response that is not streamed
+		e2e  time.Duration
+	}
+	repeat := func(count int, s sample) []sample {
+		samples := make([]sample, count)
+		for i := range samples {
+			samples[i] = s
+		}
+		return samples
+	}
+	concat := func(samples ...[]sample) []sample {
+		result := []sample{}
+		for _, s := range samples {
+			result = append(result, s...)
+		}
+		return result
+	}
+
+	tests := []struct {
+		name     string
+		pods     []string
+		samples  []sample
+		expected bool
+	}{
+		{
+			name:     "no pods",
+			expected: true,
+		},
+		{
+			name:     "no latencies",
+			pods:     []string{"pod1"},
+			expected: false,
+		},
+		{
+			name:     "latencies within the targets",
+			pods:     []string{"pod1"},
+			samples:  repeat(20, sample{pod: "pod1", ago: 15 * time.Second, ttft: 100 * time.Millisecond, e2e: 2 * time.Second}),
+			expected: false,
+		},
+		{
+			name:     "ttft over the target",
+			pods:     []string{"pod1"},
+			samples:  repeat(20, sample{pod: "pod1", ago: 15 * time.Second, ttft: time.Second, e2e: 2 * time.Second}),
+			expected: true,
+		},
+		{
+			name:     "e2e latency of responses that are not streamed over the target",
+			pods:     []string{"pod1"},
+			samples:  repeat(20, sample{pod: "pod1", ago: 15 * time.Second, e2e: 20 * time.Second}),
+			expected: true,
+		},
+		{
+			name:     "too few latencies over the target",
+			pods:     []string{"pod1"},
+			samples:  repeat(4, sample{pod: "pod1", ago: 15 * time.Second, ttft: time.Second, e2e: 20 * time.Second}),
+			expected: false,
+		},
+		{
+			name:     "latencies over the target are older than the window",
+			pods:     []string{"pod1"},
+			samples:  repeat(20, sample{pod: "pod1", ago: time.Minute, ttft: time.Second, e2e: 20 * time.Second}),
+			expected: false,
+		},
+		{
+			name: "only the slowest tenth of the latencies over the target",
+			pods: []string{"pod1"},
+			samples: concat(
+				repeat(18, sample{pod: "pod1", ago: 15 * time.Second, ttft: 100 * time.Millisecond, e2e: 2 * time.Second}),
+				repeat(2, sample{pod: "pod1", ago: 15 * time.Second, ttft: time.Second, e2e: 20 * time.Second}),
+			),
+			expected: false,
+		},
+		{
+			name: "ttft rising quickly",
+			pods: []string{"pod1"},
+			samples: concat(
+				repeat(10, sample{pod: "pod1", ago: 20 * time.Second, ttft: 100 * time.Millisecond, e2e: 2 * time.Second}),
+				// 2*400ms - 100ms = 700ms is projected for the next trend window
+				repeat(10, sample{pod: "pod1", ago: 5 * time.Second, ttft: 400 * time.Millisecond, e2e: 2 * time.Second}),
+			),
+			expected: true,
+		},
+		{
+			name: "ttft rising slowly",
+			pods: []string{"pod1"},
+			samples: concat(
+				repeat(10, sample{pod: "pod1", ago: 20 * time.Second, ttft: 300 * time.Millisecond, e2e: 2 * time.Second}),
+				repeat(10, sample{pod: "pod1", ago: 5 * time.Second, ttft: 350 * time.Millisecond, e2e: 2 * time.Second}),
+			),
+			expected: false,
+		},
+		{
+			name: "one pod within the targets",
+			pods: []string{"pod1", "pod2"},
+			samples: concat(
+				repeat(20, sample{pod: "pod1", ago: 15 * time.Second, ttft: time.Second, e2e: 20 * time.Second}),
+				repeat(5, sample{pod: "pod2", ago: 15 * time.Second, ttft: 100 * time.Millisecond, e2e: 2 * time.Second}),
+			),
+			expected: false,
+		},
+		{
+			name: "pod without latencies joined the pool",
+			pods: []string{"pod1", "pod2"},
+			samples: concat(
+				repeat(20, sample{pod: "pod1", ago: 15 * time.Second, ttft: time.Second, e2e: 20 * time.Second}),
+			),
+			expected: false,
+		},
+		{
+			name: "all pods over the targets",
+			pods: []string{"pod1", "pod2"},
+			samples: concat(
+				repeat(20, sample{pod: "pod1", ago: 15 * time.Second, ttft: time.Second, e2e: 20 * time.Second}),
+				repeat(20, sample{pod: "pod2", ago: 15 * time.Second, ttft: 800 * time.Millisecond, e2e: 5 * time.Second}),
+			),
+			expected: true,
+		},
+		{
+			name: "pool over the targets because of a pod that left the pool",
+			pods: []string{"pod1"},
+			samples: concat(
+				repeat(20, sample{pod: "pod1", ago: 15 * time.Second, ttft: 100 * time.Millisecond, e2e: 2 * time.Second}),
+				repeat(20, sample{pod: "pod2", ago: 15 * time.Second, ttft: time.Second, e2e: 20 * time.Second}),
+			),
+			expected: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			datastore := &mockDatastore{}
+			for _, pod := range test.pods {
+				datastore.pods = append(datastore.pods, newMockPodMetrics(pod, &backendmetrics.MetricsState{}))
+			}
+			detector := NewLatencyDetector(parameters, datastore)
+			now := time.Now()
+			for _, s := range test.samples {
+				detector.now = func() time.Time { return now.Add(-s.ago) }
+				received := now.Add(-s.ago - s.e2e)
+				response := &requestcontrol.Response{
+					RequestReceivedTimestamp:  received,
+					ResponseCompleteTimestamp: received.Add(s.e2e),
+				}
+				if s.ttft != 0 {
+					response.FirstTokenTimestamp = received.Add(s.ttft)
+				}
+				detector.ResponseComplete(context.Background(), nil, response, newMockPodMetrics(s.pod, nil).GetPod())
+			}
+			detector.now = func() time.Time { return now }
+
+			if got := detector.IsSaturated(context.Background()); got != test.expected {
+				t.Errorf("IsSaturated() = %v, want %v", got, test.expected)
+			}
+		})
+	}
+}
+
+func TestLatencyDetector_ResponseCompleteSkipsCancelled(t *testing.T) {
+	datastore := &mockDatastore{pods: []*backendmetrics.FakePodMetrics{newMockPodMetrics("pod1", &backendmetrics.MetricsState{})}}
+	detector := NewLatencyDetector(LatencyParameters{
+		E2ELatencyTarget: metav1.Duration{Duration: time.Second},
+		Percentile:       0.5,
+		Window:           metav1.Duration{Duration: time.Minute},
+		MinSamples:       1,
+	}, datastore)
+
+	received := time.Now().Add(-time.Minute)
+	detector.ResponseComplete(context.Background(), nil, &requestcontrol.Response{
+		RequestReceivedTimestamp:  received,
+		ResponseCompleteTimestamp: received.Add(30 * time.Second),
+		Cancelled:                 true,
+	}, datastore.pods[0].GetPod())
+	if detector.IsSaturated(context.Background()) {
+		t.Error("expected the latency of a cancelled request to be ignored")
+	}
+}
+
+func TestLatencyDetector_ObjectiveSLOs(t *testing.T) {
+	tests := []struct {
+		name       string
+		parameters LatencyParameters
+		request    *types.LLMRequest
+		e2e        time.Duration
+		expected   bool
+	}{
+		{
+			name:     "no targets",
+			request:  &types.LLMRequest{},
+			e2e:      20 * time.Second,
+			expected: false,
+		},
+		{
+			name:     "latency over the SLO of the objective",
+			request:  &types.LLMRequest{E2ELatencySLO: 10 * time.Second},
+			e2e:      20 * time.Second,
+			expected: true,
+		},
+		{
+			name:       "SLO of the objective takes precedence over the configured target",
+			parameters: LatencyParameters{E2ELatencyTarget: metav1.Duration{Duration: 10 * time.Second}},
+			request:    &types.LLMRequest{E2ELatencySLO: 30 * time.Second},
+			e2e:        20 * time.Second,
+			expected:   false,
+		},
+		{
+			name:       "configured target without SLO of the objective",
+			parameters: LatencyParameters{E2ELatencyTarget: metav1.Duration{Duration: 10 * time.Second}},
+			request:    &types.LLMRequest{},
+			e2e:        20 * time.Second,
+			expected:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			datastore := &mockDatastore{pods: []*backendmetrics.FakePodMetrics{newMockPodMetrics("pod1", &backendmetrics.MetricsState{})}}
+			parameters := test.parameters
+			parameters.Percentile = 0.5
+			parameters.Window = metav1.Duration{Duration: time.Minute}
+			parameters.MinSamples = 1
+			detector := NewLatencyDetector(parameters, datastore)
+
+			received := time.Now().Add(-test.e2e)
+			detector.ResponseComplete(context.Background(), test.request, &requestcontrol.Response{
+				RequestReceivedTimestamp:  received,
+				ResponseCompleteTimestamp: received.Add(test.e2e),
+			}, datastore.pods[0].GetPod())
+			if got := detector.IsSaturated(context.Background()); got != test.expected {
+				t.Errorf("IsSaturated() = %v, want %v", got, test.expected)
+			}
+		})
+	}
+}
+
+func TestLatencySaturationDetectorFactory(t *testing.T) {
+	tests := []struct {
+		name      string
+		params    string
+		expectErr bool
+	}{
+		{name: "ttft target", params: `{"ttftTarget": "500ms"}`},
+		{name: "all parameters", params: `{"ttftTarget": "500ms", "e2eLatencyTarget": "10s", "percentile": 0.99, "window": "1m",
+			"trendWindow": "0s", "minSamples": 100}`},
+		{name: "no targets", params: `{}`},
+		{name: "negative target", params: `{"e2eLatencyTarget": "-1s"}`, expectErr: true},
+		{name: "invalid percentile", params: `{"ttftTarget": "500ms", "percentile": 90}`, expectErr: true},
+		{name: "trend window not shorter than the window", params: `{"ttftTarget": "500ms", "window": "10s", "trendWindow": "10s"}`, expectErr: true},
+		{name: "zero min samples", params: `{"ttftTarget": "500ms", "minSamples": 0}`, expectErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := LatencySaturationDetectorFactory(&mockDatastore{})("latency", json.RawMessage(test.params), utils.NewTestHandle(context.Background()))
+			if (err != nil) != test.expectErr {
+				t.Errorf("unexpected error %v, expected error %v", err, test.expectErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package saturationdetector
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/plugins"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/requestcontrol"
+)
+
+const (
+	ThresholdSaturationDetectorType = "threshold-saturation-detector"
+)
+
+// SaturationDetector is a plugin that determines if the backend model servers are saturated. At most one
+// saturation detector can be configured in the EndpointPickerConfig. A saturation detector can also estimate when
+// a saturated system has capacity again by implementing RetryAfter(ctx) time.Duration, and can detect saturation
+// per model by implementing IsModelSaturated(ctx, model) bool.
+type SaturationDetector interface {
+	plugins.Plugin
+	IsSaturated(ctx context.Context) bool
+}
+
+// ThresholdParameters are the parameters of the threshold saturation detector.
+type ThresholdParameters struct {
+	// QueueDepthThreshold is the backend waiting queue size above which a pod has insufficient capacity.
+	QueueDepthThreshold int `json:"queueDepthThreshold"`
+	// KVCacheUtilThreshold is the KV cache utilization (0.0 to 1.0) above which a pod has insufficient capacity.
+	KVCacheUtilThreshold float64 `json:"kvCacheUtilThreshold"`
+	// MetricsStalenessThreshold is how old the metrics of a pod can be for the pod to have capacity.
+	MetricsStalenessThreshold metav1.Duration `json:"metricsStalenessThreshold"`
+	// PriorityAdapters are the LoRA adapters the reserved adapter slots of every pod are reserved for.
+	PriorityAdapters []string `json:"priorityAdapters"`
+	// LoraReservedSlots is the number of adapter slots of every pod that are reserved for the PriorityAdapters.
+	LoraReservedSlots int `json:"loraReservedSlots"`
+}
+
+// compile-time type assertion
+var (
+	_ SaturationDetector                    = &Detector{}
+	_ requestcontrol.AdapterSlotReservation = &Detector{}
+)
+
+// ThresholdSaturationDetectorFactory returns the factory function of the threshold based Detector, which lists the pods
+// from the datastore.
+func ThresholdSaturationDetectorFactory(datastore Datastore) plugins.FactoryFunc {
+	return func(name string, rawParameters json.RawMessage, handle plugins.Handle) (plugins.Plugin, error) {
+		parameters := ThresholdParameters{
+			QueueDepthThreshold:       DefaultQueueDepthThreshold,
+			KVCacheUtilThreshold:      DefaultKVCacheUtilThreshold,
+			MetricsStalenessThreshold: metav1.Duration{Duration: DefaultMetricsStalenessThreshold},
+		}
+		if rawParameters != nil {
+			if err := json.Unmarshal(rawParameters, &parameters); err != nil {
+				return nil, fmt.Errorf("failed to parse the parameters of the '%s' saturation detector - %w", ThresholdSaturationDetectorType, err)
+			}
+		}
+		config := &Config{
+			QueueDepthThreshold:       parameters.QueueDepthThreshold,
+			KVCacheUtilThreshold:      parameters.KVCacheUtilThreshold,
+			MetricsStalenessThreshold: parameters.MetricsStalenessThreshold.Duration,
+			PriorityAdapters:          parameters.PriorityAdapters,
+			LoraReservedSlots:         parameters.LoraReservedSlots,
+		}
+		if err := validateConfig(config); err != nil {
+			return nil, fmt.Errorf("invalid parameters of the '%s' saturation detector - %w", ThresholdSaturationDetectorType, err)
+		}
+
+		return NewDetector(config, datastore, log.FromContext(handle.Context())).WithName(name), nil
+	}
+}
+
+// validateConfig validates the thresholds of a Config.
+func validateConfig(config *Config) error {
+	switch {
+	case config.QueueDepthThreshold < 0:
+		return fmt.Errorf("queueDepthThreshold %d must not be negative", config.QueueDepthThreshold)
+	case config.KVCacheUtilThreshold <= 0 || config.KVCacheUtilThreshold > 1:
+		return fmt.Errorf("kvCacheUtilThreshold %v must be greater than 0 and at most 1", config.KVCacheUtilThreshold)
+	case config.MetricsStalenessThreshold <= 0:
+		return fmt.Errorf("metricsStalenessThreshold %v must be positive", config.MetricsStalenessThreshold)
+	case config.LoraReservedSlots < 0:
+		return fmt.Errorf("loraReservedSlots %d must not be negative", config.LoraReservedSlots)
+	case config.LoraReservedSlots > 0 && len(config.PriorityAdapters) == 0:
+		return errors.New("loraReservedSlots requires priorityAdapters")
+	}
+	return nil
+}
@@ -23,27 +23,39 @@ limitations under the License.
 // introduction of the FlowController. It fetches live metrics from the
 // provided Datastore.
 //
+// Detectors are plugins, and the detector to use can be chosen in the
+// EndpointPickerConfig. Besides the threshold based Detector, the package
+// provides a detector with hysteresis that prevents rapid oscillations of the
+// saturation signal, a detector that combines several signals by a weighted
+// score, and a detector that decides saturation from the latencies of the
+// responses.
+//
 // TODO: Explore more advanced saturation signals in the future, such as:
-//   - Latency-objective-based saturation.
-//   - Predictive saturation based on trends.
-//   - Hysteresis bands or other smoothing techniques to prevent rapid
-//     oscillations of the saturation signal.
+//   - Predictive saturation based on trends of the model server metrics.
 package saturationdetector
 
 import (
 	"context"
+	"slices"
 	"time"
 
 	"github.com/go-logr/logr"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datalayer/models"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/plugins"
+	schedulingtypes "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
 	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/logging"
 )
 
 const (
 	// loggerName is the name to use for loggers created by this package.
 	loggerName = "SaturationDetector"
+
+	// minRetryAfter and maxRetryAfter bound the estimates of RetryAfter.
+	minRetryAfter = time.Second
+	maxRetryAfter = 30 * time.Second
 )
 
 // Config holds the configuration for the SaturationDetector.
@@ -59,6 +71,12 @@ type Config struct {
 	// "good capacity" considerations or treated as having no capacity for
 	// safety.
 	MetricsStalenessThreshold time.Duration
+	// PriorityAdapters are the LoRA adapters the reserved adapter slots of every pod are reserved for.
+	PriorityAdapters []string
+	// LoraReservedSlots defines the number of adapter slots (MaxActiveModels) of every pod that are reserved
+	// for the PriorityAdapters. Requests for other adapters are considered saturated when only the reserved
+	// slots are free, and are not scheduled to pods where they would take a reserved slot.
+	LoraReservedSlots int
 }
 
 // Datastore provides an interface to access backend pod metrics.
@@ -77,6 +95,7 @@ type Datastore interface {
 // decoupled approach (e.g., passing metrics directly to IsSaturated) proves
 // more beneficial.
 type Detector struct {
+	typedName plugins.TypedName
 	datastore Datastore
 	config    *Config
 }
@@ -89,14 +108,28 @@ func NewDetector(config *Config, datastore Datastore, logger logr.Logger) *Detec
 	logger.WithName(loggerName).V(logutil.DEFAULT).Info("Creating new SaturationDetector",
 		"queueDepthThreshold", config.QueueDepthThreshold,
 		"kvCacheUtilThreshold", config.KVCacheUtilThreshold,
-		"metricsStalenessThreshold", config.MetricsStalenessThreshold.String())
+		"metricsStalenessThreshold", config.MetricsStalenessThreshold.String(),
+		"priorityAdapters", config.PriorityAdapters,
+		"loraReservedSlots", config.LoraReservedSlots)
 
 	return &Detector{
+		typedName: plugins.TypedName{Type: ThresholdSaturationDetectorType, Name: ThresholdSaturationDetectorType},
 		datastore: datastore,
 		config:    config,
 	}
 }
 
+// TypedName returns the type and name tuple of this plugin instance.
+func (d *Detector) TypedName() plugins.TypedName {
+	return d.typedName
+}
+
+// WithName sets the name of the detector.
+func (d *Detector) WithName(name string) *Detector {
+	d.typedName.Name = name
+	return d
+}
+
 // IsSaturated checks if the system is currently considered saturated.
 // The system is saturated if NO pod currently has "good capacity".
 // "Good capacity" means:
@@ -118,45 +151,218 @@ func (d *Detector) IsSaturated(ctx context.Context) bool {
 	}
 
 	for _, podMetric := range allPodsMetrics {
-		metrics := podMetric.GetMetrics()
-		podNn := "unknown-pod"
-		if podMetric.GetPod() != nil {
-			podNn = podMetric.GetPod().NamespacedName.String()
+		if d.hasGoodCapacity(logger, podMetric) {
+			return false // Found at least one pod with good capacity, so system is NOT saturated.
 		}
+	}
+
+	logger.V(logutil.VERBOSE).Info("No pods found with good capacity; system is considered SATURATED.")
+	return true
+}
+
+// IsModelSaturated checks if the system is currently considered saturated for requests for the model.
+// It is saturated if NO pod currently has "good capacity" (see IsSaturated) and room for the model. When the
+// model is a LoRA adapter, a pod has room for it if the adapter is active or waiting on the pod, or the pod has
+// a free adapter slot (MaxActiveModels). Free slots that are reserved for the PriorityAdapters are only
+// room for the PriorityAdapters. A model is known to be an adapter if it is one of the PriorityAdapters,
+// if it is active or waiting on any pod, or if any pod serves it as a discovered adapter.
+func (d *Detector) IsModelSaturated(ctx context.Context, model string) bool {
+	return d.isModelSaturated(ctx, model, d.hasGoodCapacity)
+}
+
+// isModelSaturated checks if no pod has capacity by the hasCapacity function and room for the model, by the adapter
+// slots of the Config, so that detectors that decide the capacity of the pods differently share the adapter slots.
+func (d *Detector) isModelSaturated(ctx context.Context, model string, hasCapacity func(logr.Logger, backendmetrics.PodMetrics) bool) bool {
+	logger := log.FromContext(ctx).WithName(loggerName)
+	allPodsMetrics := d.datastore.PodList(backendmetrics.AllPodsPredicate)
+	if len(allPodsMetrics) == 0 {
+		logger.V(logutil.VERBOSE).Info("No pods found in datastore; system is considered SATURATED (no capacity).", "model", model)
+		return true
+	}
 
-		if metrics == nil {
-			logger.V(logutil.TRACE).Info("Pod has nil metrics, skipping for saturation check",
-				"pod", podNn)
+	isAdapter := d.isAdapter(model, allPodsMetrics)
+	for _, podMetric := range allPodsMetrics {
+		if !hasCapacity(logger, podMetric) {
 			continue
 		}
-
-		// Check for metric staleness
-		if time.Since(metrics.UpdateTime) > d.config.MetricsStalenessThreshold {
-			logger.V(logutil.TRACE).Info("Pod metrics are stale, considered as not having good capacity",
-				"pod", podNn, "updateTime", metrics.UpdateTime, "stalenessThreshold", d.config.MetricsStalenessThreshold)
+		if isAdapter && !d.hasAdapterSlot(podMetric.GetMetrics(), model) {
+			logger.V(logutil.TRACE).Info("Pod has no adapter slot for the model, considered as not having good capacity",
+				"pod", podMetric.GetPod().NamespacedName, "model", model, "maxActiveModels", podMetric.GetMetrics().MaxActiveModels)
 			continue
 		}
+		return false
+	}
 
-		// Check queue depth
-		if metrics.WaitingQueueSize > d.config.QueueDepthThreshold {
-			logger.V(logutil.TRACE).Info("Pod WaitingQueueSize is above threshold, considered as not having good capacity",
-				"pod", podNn, "waitingQueueSize", metrics.WaitingQueueSize, "threshold", d.config.QueueDepthThreshold)
-			continue // WaitingQueueSize is above threshold, considered saturated.
-		}
+	logger.V(logutil.VERBOSE).Info("No pods found with good capacity for the model; system is considered SATURATED.", "model", model)
+	return true
+}
+
+// PodsWithAdapterSlot returns the pods that have an adapter slot for the model (see IsModelSaturated) that is not
+// reserved for the PriorityAdapters, regardless of their capacity, so that the reserved slots are kept for the
+// PriorityAdapters by the requests of every priority. It returns all the pods when no slots are reserved, for the
+// PriorityAdapters, and for base models. Besides the adapters known to IsModelSaturated, a model that is not
+// loaded on any pod is known to be an adapter if the models of the pods were discovered and no pod serves it as
+// a base model. Pods without metrics are assumed to have a slot.
+func (d *Detector) PodsWithAdapterSlot(ctx context.Context, model string, pods []schedulingtypes.Pod) []schedulingtypes.Pod {
+	if d.config.LoraReservedSlots == 0 || slices.Contains(d.config.PriorityAdapters, model) {
+		return pods
+	}
+	if !d.takesAdapterSlot(model, d.datastore.PodList(backendmetrics.AllPodsPredicate)) {
+		return pods
+	}
 
-		// Check KV cache utilization
-		if metrics.KVCacheUsagePercent > d.config.KVCacheUtilThreshold {
-			logger.V(logutil.TRACE).Info("Pod KVCacheUsagePercent is above threshold, considered as not having good capacity",
-				"pod", podNn, "kvCacheUsagePercent", metrics.KVCacheUsagePercent, "threshold", d.config.KVCacheUtilThreshold)
-			continue // KVCacheUsagePercent is above threshold, considered saturated.
+	filtered := make([]schedulingtypes.Pod, 0, len(pods))
+	for _, pod := range pods {
+		if metrics := pod.GetMetrics(); metrics == nil || d.hasAdapterSlot(metrics, model) {
+			filtered = append(filtered, pod)
 		}
+	}
+	log.FromContext(ctx).WithName(loggerName).V(logutil.TRACE).Info("Filtered pods by their unreserved adapter slots",
+		"model", model, "pods", len(pods), "filteredPods", len(filtered))
+	return filtered
+}
 
-		logger.V(logutil.TRACE).Info("Found pod with good capacity", "pod", podNn, "waitingQueue", metrics.WaitingQueueSize,
-			"queueThreshold", d.config.QueueDepthThreshold, "kvCacheUtil", metrics.KVCacheUsagePercent, "kvCacheThreshold", d.config.KVCacheUtilThreshold)
+// hasGoodCapacity returns true if the pod has fresh metrics, and its waiting queue and KV cache utilization are
+// within the thresholds.
+func (d *Detector) hasGoodCapacity(logger logr.Logger, podMetric backendmetrics.PodMetrics) bool {
+	metrics := podMetric.GetMetrics()
+	podNn := "unknown-pod"
+	if podMetric.GetPod() != nil {
+		podNn = podMetric.GetPod().NamespacedName.String()
+	}
 
-		return false // Found at least one pod with good capacity, so system is NOT saturated.
+	if metrics == nil {
+		logger.V(logutil.TRACE).Info("Pod has nil metrics, skipping for saturation check",
+			"pod", podNn)
+		return false
 	}
 
-	logger.V(logutil.VERBOSE).Info("No pods found with good capacity; system is considered SATURATED.")
+	// Check for metric staleness
+	if time.Since(metrics.UpdateTime) > d.config.MetricsStalenessThreshold {
+		logger.V(logutil.TRACE).Info("Pod metrics are stale, considered as not having good capacity",
+			"pod", podNn, "updateTime", metrics.UpdateTime, "stalenessThreshold", d.config.MetricsStalenessThreshold)
+		return false
+	}
+
+	// Check queue depth
+	if metrics.WaitingQueueSize > d.config.QueueDepthThreshold {
+		logger.V(logutil.TRACE).Info("Pod WaitingQueueSize is above threshold, considered as not having good capacity",
+			"pod", podNn, "waitingQueueSize", metrics.WaitingQueueSize, "threshold", d.config.QueueDepthThreshold)
+		return false // WaitingQueueSize is above threshold, considered saturated.
+	}
+
+	// Check KV cache utilization
+	if metrics.KVCacheUsagePercent > d.config.KVCacheUtilThreshold {
+		logger.V(logutil.TRACE).Info("Pod KVCacheUsagePercent is above threshold, considered as not having good capacity",
+			"pod", podNn, "kvCacheUsagePercent", metrics.KVCacheUsagePercent, "threshold", d.config.KVCacheUtilThreshold)
+		return false // KVCacheUsagePercent is above threshold, considered saturated.
+	}
+
+	logger.V(logutil.TRACE).Info("Found pod with good capacity", "pod", podNn, "waitingQueue", metrics.WaitingQueueSize,
+		"queueThreshold", d.config.QueueDepthThreshold, "kvCacheUtil", metrics.KVCacheUsagePercent, "kvCacheThreshold", d.config.KVCacheUtilThreshold)
 	return true
 }
+
+// isAdapter returns true if the model is known to be a LoRA adapter.
+func (d *Detector) isAdapter(model string, allPodsMetrics []backendmetrics.PodMetrics) bool {
+	if slices.Contains(d.config.PriorityAdapters, model) {
+		return true
+	}
+	for _, podMetric := range allPodsMetrics {
+		if metrics := podMetric.GetMetrics(); metrics != nil {
+			if _, active := metrics.ActiveModels[model]; active {
+				return true
+			}
+			if _, waiting := metrics.WaitingModels[model]; waiting {
+				return true
+			}
+		}
+		if served, found := models.GetServedModels(podMetric); found {
+			if _, adapter := served.Adapters[model]; adapter {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// takesAdapterSlot returns true if the model is known to be a LoRA adapter (see isAdapter), or if the models of
+// the pods were discovered and no pod serves the model as a base model.
+func (d *Detector) takesAdapterSlot(model string, allPodsMetrics []backendmetrics.PodMetrics) bool {
+	if d.isAdapter(model, allPodsMetrics) {
+		return true
+	}
+	discovered := false
+	for _, podMetric := range allPodsMetrics {
+		if served, found := models.GetServedModels(podMetric); found {
+			if slices.Contains(served.BaseModels, model) {
+				return false
+			}
+			discovered = true
+		}
+	}
+	return discovered
+}
+
+// hasAdapterSlot returns true if the adapter is active or waiting on the pod, or the pod has a free adapter slot
+// for it. Pods that don't report their MaxActiveModels are assumed to have a slot.
+func (d *Detector) hasAdapterSlot(metrics *backendmetrics.MetricsState, adapter string) bool {
+	if metrics.MaxActiveModels <= 0 {
+		return true
+	}
+	_, active := metrics.ActiveModels[adapter]
+	_, waiting := metrics.WaitingModels[adapter]
+	if active || waiting {
+		return true
+	}
+
+	used := len(metrics.ActiveModels)
+	priorityUsed := 0
+	for loaded := range metrics.ActiveModels {
+		if slices.Contains(d.config.PriorityAdapters, loaded) {
+			priorityUsed++
+		}
+	}
+	for loaded := range metrics.WaitingModels {
+		if _, active := metrics.ActiveModels[loaded]; active {
+			continue
+		}
+		used++
+		if slices.Contains(d.config.PriorityAdapters, loaded) {
+			priorityUsed++
+		}
+	}
+	free := metrics.MaxActiveModels - used
+	if slices.Contains(d.config.PriorityAdapters, adapter) {
+		return free > 0
+	}
+	// the reserved slots that are not used by the priority adapters are not free for other adapters.
+	return free-max(d.config.LoraReservedSlots-priorityUsed, 0) > 0
+}
+
+// RetryAfter estimates how long it takes until a saturated system has capacity for new requests again, to be
+// returned to the clients of rejected requests. The estimate is based on the pod that is closest to having
+// good capacity: one second, plus one second for every QueueDepthThreshold requests its waiting queue exceeds
+// the threshold by, bounded between one and thirty seconds. Pods without fresh metrics are ignored, and if no
+// pod has fresh metrics, the minimum is returned.
+func (d *Detector) RetryAfter(ctx context.Context) time.Duration {
+	threshold := max(d.config.QueueDepthThreshold, 1)
+	var closest time.Duration
+	for _, podMetric := range d.datastore.PodList(backendmetrics.AllPodsPredicate) {
+		metrics := podMetric.GetMetrics()
+		if metrics == nil || time.Since(metrics.UpdateTime) > d.config.MetricsStalenessThreshold {
+			continue
+		}
+		excess := max(metrics.WaitingQueueSize-d.config.QueueDepthThreshold, 0)
+		retryAfter := minRetryAfter + time.Duration(excess/threshold)*time.Second
+		if closest == 0 || retryAfter < closest {
+			closest = retryAfter
+		}
+	}
+	if closest == 0 {
+		closest = minRetryAfter
+	}
+	retryAfter := min(closest, maxRetryAfter)
+	log.FromContext(ctx).WithName(loggerName).V(logutil.TRACE).Info("Estimated retry after", "retryAfter", retryAfter)
+	return retryAfter
+}
@@ -18,6 +18,7 @@ package saturationdetector
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"strconv"
@@ -25,10 +26,13 @@ import (
 	"time"
 
 	"github.com/go-logr/logr"
+	"github.com/google/go-cmp/cmp"
 	"k8s.io/apimachinery/pkg/types"
 
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend"
 	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
+	schedulingtypes "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+	"sigs.k8s.io/gateway-api-inference-extension/test/utils"
 )
 
 // --- Mock Implementations ---
@@ -326,3 +330,290 @@ func TestDetector_IsSaturated(t *testing.T) {
 		})
 	}
 }
+
+func TestDetector_RetryAfter(t *testing.T) {
+	baseTime := time.Now()
+	config := &Config{
+		QueueDepthThreshold:       5,
+		KVCacheUtilThreshold:      0.90,
+		MetricsStalenessThreshold: 100 * time.Millisecond,
+	}
+
+	tests := []struct {
+		name string
+		pods []*backendmetrics.FakePodMetrics
+		want time.Duration
+	}{
+		{
+			name: "No pods in datastore",
+			pods: []*backendmetrics.FakePodMetrics{},
+			want: time.Second,
+		},
+		{
+			name: "Pod closest to capacity is used",
+			pods: []*backendmetrics.FakePodMetrics{
+				newMockPodMetrics("pod1", &backendmetrics.MetricsState{UpdateTime: baseTime, WaitingQueueSize: 30}),
+				newMockPodMetrics("pod2", &backendmetrics.MetricsState{UpdateTime: baseTime, WaitingQueueSize: 16}),
+				newMockPodMetrics("pod3", &backendmetrics.MetricsState{UpdateTime: baseTime.Add(-time.Second), WaitingQueueSize: 0}), // stale
+			},
+			want: 3 * time.Second,
+		},
+		{
+			name: "Estimate is bounded",
+			pods: []*backendmetrics.FakePodMetrics{
+				newMockPodMetrics("pod1", &backendmetrics.MetricsState{UpdateTime: baseTime, WaitingQueueSize: 1000}),
+			},
+			want: 30 * time.Second,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			detector := NewDetector(config, &mockDatastore{pods: test.pods}, logr.Discard())
+
+			if got := detector.RetryAfter(context.Background()); got != test.want {
+				t.Errorf("RetryAfter() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestDetector_IsModelSaturated(t *testing.T) {
+	baseTime := time.Now()
+	config := &Config{
+		QueueDepthThreshold:       5,
+		KVCacheUtilThreshold:      0.90,
+		MetricsStalenessThreshold: 100 * time.Millisecond,
+		PriorityAdapters:          []string{"premium-lora"},
+		LoraReservedSlots:         1,
+	}
+	loraMetrics := func(active []string, waiting []string) *backendmetrics.MetricsState {
+		metrics := &backendmetrics.MetricsState{
+			UpdateTime:      baseTime,
+			ActiveModels:    map[string]int{},
+			WaitingModels:   map[string]int{},
+			MaxActiveModels: 3,
+		}
+		for _, adapter := range active {
+			metrics.ActiveModels[adapter] = 1
+		}
+		for _, adapter := range waiting {
+			metrics.WaitingModels[adapter] = 1
+		}
+		return metrics
+	}
+
+	tests := []struct {
+		name            string
+		model           string
+		pods            []*backendmetrics.FakePodMetrics
+		expectedSaturat bool
+	}{
+		{
+			name:            "No pods in datastore",
+			model:           "base-model",
+			pods:            []*backendmetrics.FakePodMetrics{},
+			expectedSaturat: true,
+		},
+		{
+			name:  "Base model with all adapter slots used",
+			model: "base-model",
+			pods: []*backendmetrics.FakePodMetrics{
+				newMockPodMetrics("pod1", loraMetrics([]string{"lora1", "lora2", "lora3"}, nil)),
+			},
+			expectedSaturat: false,
+		},
+		{
+			name:  "Adapter active on a pod with all adapter slots used",
+			model: "lora1",
+			pods: []*backendmetrics.FakePodMetrics{
+				newMockPodMetrics("pod1", loraMetrics([]string{"lora1", "lora2"}, []string{"lora3"})),
+			},
+			expectedSaturat: false,
+		},
+		{
+			name:  "Adapter with a free adapter slot on a pod",
+			model: "lora4",
+			pods: []*backendmetrics.FakePodMetrics{
+				newMockPodMetrics("pod1", loraMetrics([]string{"lora1", "lora2"}, []string{"lora3"})),
+				newMockPodMetrics("pod2", loraMetrics([]string{"lora4", "lora5", "lora6"}, nil)),
+				newMockPodMetrics("pod3", loraMetrics(nil, nil)),
+			},
+			expectedSaturat: false,
+		},
+		{
+			name:  "Adapter active only on a pod without good capacity",
+			model: "lora4",
+			pods: []*backendmetrics.FakePodMetrics{
+				newMockPodMetrics("pod1", loraMetrics([]string{"lora1", "lora2"}, []string{"lora3"})),
+				newMockPodMetrics("pod2", &backendmetrics.MetricsState{
+					UpdateTime:       baseTime,
+					WaitingQueueSize: 10,
+					ActiveModels:     map[string]int{"lora4": 1},
+					MaxActiveModels:  3,
+				}),
+			},
+			expectedSaturat: true,
+		},
+		{
+			name:  "Adapter with only the reserved slot free",
+			model: "lora3",
+			pods: []*backendmetrics.FakePodMetrics{
+				newMockPodMetrics("pod1", loraMetrics([]string{"lora1", "lora2"}, nil)),
+				newMockPodMetrics("pod2", &backendmetrics.MetricsState{
+					UpdateTime:       baseTime,
+					WaitingQueueSize: 10,
+					ActiveModels:     map[string]int{"lora3": 1},
+					MaxActiveModels:  3,
+				}),
+			},
+			expectedSaturat: true,
+		},
+		{
+			name:  "Priority adapter with only the reserved slot free",
+			model: "premium-lora",
+			pods: []*backendmetrics.FakePodMetrics{
+				newMockPodMetrics("pod1", loraMetrics([]string{"lora1", "lora2"}, nil)),
+			},
+			expectedSaturat: false,
+		},
+		{
+			name:  "Adapter with the reserved slot used by a priority adapter",
+			model: "lora3",
+			pods: []*backendmetrics.FakePodMetrics{
+				newMockPodMetrics("pod1", loraMetrics([]string{"premium-lora", "lora1"}, nil)),
+			},
+			expectedSaturat: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			detector := NewDetector(config, &mockDatastore{pods: test.pods}, logr.Discard())
+
+			if got := detector.IsModelSaturated(context.Background(), test.model); got != test.expectedSaturat {
+				t.Errorf("IsModelSaturated(%s) = %v, want %v", test.model, got, test.expectedSaturat)
+			}
+		})
+	}
+}
+
+func TestDetector_PodsWithAdapterSlot(t *testing.T) {
+	loraMetrics := func(active ...string) *backendmetrics.MetricsState {
+		metrics := &backendmetrics.MetricsState{ActiveModels: map[string]int{}, WaitingModels: map[string]int{}, MaxActiveModels: 3}
+		for _, adapter := range active {
+			metrics.ActiveModels[adapter] = 1
+		}
+		return metrics
+	}
+	// the reserved slot of pod2 is the only free one, and pod3 doesn't have capacity.
+	podMetrics := map[string]*backendmetrics.MetricsState{
+		"pod1": loraMetrics("lora1"),
+		"pod2": loraMetrics("lora1", "lora2"),
+		"pod3": {WaitingQueueSize: 100, ActiveModels: map[string]int{"lora3": 1}, MaxActiveModels: 3},
+	}
+	var datastorePods []*backendmetrics.FakePodMetrics
+	var pods []schedulingtypes.Pod
+	for _, name := range []string{"pod1", "pod2", "pod3"} {
+		datastorePods = append(datastorePods, newMockPodMetrics(name, podMetrics[name]))
+		pods = append(pods, &schedulingtypes.PodMetrics{
+			Pod:          &backend.Pod{NamespacedName: types.NamespacedName{Name: name, Namespace: "ns1"}},
+			MetricsState: podMetrics[name],
+		})
+	}
+
+	tests := []struct {
+		name              string
+		loraReservedSlots int
+		model             string
+		wantPods          []string
+	}{
+		{name: "adapter", loraReservedSlots: 1, model: "lora3", wantPods: []string{"pod1", "pod3"}},
+		{name: "adapter without reserved slots", model: "lora3", wantPods: []string{"pod1", "pod2", "pod3"}},
+		{name: "priority adapter", loraReservedSlots: 1, model: "premium-lora", wantPods: []string{"pod1", "pod2", "pod3"}},
+		{name: "base model", loraReservedSlots: 1, model: "base-model", wantPods: []string{"pod1", "pod2", "pod3"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := &Config{PriorityAdapters: []string{"premium-lora"}, LoraReservedSlots: test.loraReservedSlots}
+			detector := NewDetector(config, &mockDatastore{pods: datastorePods}, logr.Discard())
+
+			var got []string
+			for _, pod := range detector.PodsWithAdapterSlot(context.Background(), test.model, pods) {
+				got = append(got, pod.GetPod().NamespacedName.Name)
+			}
+			if diff := cmp.Diff(test.wantPods, got); diff != "" {
+				t.Errorf("Unexpected pods (-want +got): %v", diff)
+			}
+		})
+	}
+}
+
+func TestLoadConfigFromEnvLoraReservation(t *testing.T) {
+	t.Setenv(EnvSdLoraPriorityAdapters, "premium-lora, gold-lora,")
+	t.Setenv(EnvSdLoraReservedSlots, "2")
+
+	config := LoadConfigFromEnv()
+
+	if diff := cmp.Diff([]string{"premium-lora", "gold-lora"}, config.PriorityAdapters); diff != "" {
+		t.Errorf("Unexpected priority adapters (-want +got): %v", diff)
+	}
+	if config.LoraReservedSlots != 2 {
+		t.Errorf("LoraReservedSlots = %d, want 2", config.LoraReservedSlots)
+	}
+}
+
+func TestThresholdSaturationDetectorFactory(t *testing.T) {
+	tests := []struct {
+		name      string
+		params    string
+		expected  *Config
+		expectErr bool
+	}{
+		{
+			name:   "defaults",
+			params: "{}",
+			expected: &Config{
+				QueueDepthThreshold:       DefaultQueueDepthThreshold,
+				KVCacheUtilThreshold:      DefaultKVCacheUtilThreshold,
+				MetricsStalenessThreshold: DefaultMetricsStalenessThreshold,
+			},
+		},
+		{
+			name: "all parameters",
+			params: `{"queueDepthThreshold": 10, "kvCacheUtilThreshold": 0.9, "metricsStalenessThreshold": "1s",
+				"priorityAdapters": ["sql-lora"], "loraReservedSlots": 1}`,
+			expected: &Config{
+				QueueDepthThreshold:       10,
+				KVCacheUtilThreshold:      0.9,
+				MetricsStalenessThreshold: time.Second,
+				PriorityAdapters:          []string{"sql-lora"},
+				LoraReservedSlots:         1,
+			},
+		},
+		{name: "invalid kv cache threshold", params: `{"kvCacheUtilThreshold": 0}`, expectErr: true},
+		{name: "reserved slots without priority adapters", params: `{"loraReservedSlots": 1}`, expectErr: true},
+		{name: "malformed", params: `{"queueDepthThreshold": "ten"}`, expectErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			plugin, err := ThresholdSaturationDetectorFactory(&mockDatastore{})("threshold", json.RawMessage(test.params), utils.NewTestHandle(context.Background()))
+			if test.expectErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			detector := plugin.(*Detector)
+			if diff := cmp.Diff(test.expected, detector.config); diff != "" {
+				t.Errorf("unexpected config (-want +got): %s", diff)
+			}
+			if detector.TypedName().Name != "threshold" {
+				t.Errorf("unexpected name %s", detector.TypedName().Name)
+			}
+		})
+	}
+}
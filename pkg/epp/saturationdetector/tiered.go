@@ -0,0 +1,194 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package saturationdetector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/plugins"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/requestcontrol"
+)
+
+const (
+	TieredSaturationDetectorType = "tiered-saturation-detector"
+)
+
+// Tier holds the saturation thresholds of the requests of a range of priorities.
+type Tier struct {
+	// Priority is the highest priority of the requests of the tier. The tier holds the requests of this priority
+	// and of lower priorities, down to the priority of the next lower tier.
+	Priority int `json:"priority"`
+	// QueueDepthThreshold is the backend waiting queue size above which a pod has no capacity for the requests
+	// of the tier. If not set, the waiting queue is not considered.
+	QueueDepthThreshold *int `json:"queueDepthThreshold,omitempty"`
+	// KVCacheUtilThreshold is the KV cache utilization (0.0 to 1.0) above which a pod has no capacity for the
+	// requests of the tier. If not set, the KV cache utilization is not considered.
+	KVCacheUtilThreshold *float64 `json:"kvCacheUtilThreshold,omitempty"`
+}
+
+// TieredParameters are the parameters of the TieredDetector.
+type TieredParameters struct {
+	// Tiers are the saturation thresholds of the requests of each range of priorities. Requests with a priority
+	// above the highest tier are never shed.
+	Tiers []Tier `json:"tiers"`
+	// MetricsStalenessThreshold is how old the metrics of a pod can be for the pod to have capacity.
+	MetricsStalenessThreshold metav1.Duration `json:"metricsStalenessThreshold"`
+}
+
+// compile-time type assertion
+var (
+	_ SaturationDetector               = &TieredDetector{}
+	_ requestcontrol.AdmissionDetector = &TieredDetector{}
+)
+
+// TieredSaturationDetectorFactory returns the factory function of the TieredDetector, which lists the pods
+// from the datastore.
+func TieredSaturationDetectorFactory(datastore Datastore) plugins.FactoryFunc {
+	return func(name string, rawParameters json.RawMessage, _ plugins.Handle) (plugins.Plugin, error) {
+		parameters := TieredParameters{
+			MetricsStalenessThreshold: metav1.Duration{Duration: DefaultMetricsStalenessThreshold},
+		}
+		if rawParameters != nil {
+			if err := json.Unmarshal(rawParameters, &parameters); err != nil {
+				return nil, fmt.Errorf("failed to parse the parameters of the '%s' saturation detector - %w", TieredSaturationDetectorType, err)
+			}
+		}
+		if len(parameters.Tiers) == 0 {
+			// the default shedding of the requests with a negative priority
+			queueDepthThreshold, kvCacheUtilThreshold := DefaultQueueDepthThreshold, DefaultKVCacheUtilThreshold
+			parameters.Tiers = []Tier{{Priority: -1, QueueDepthThreshold: &queueDepthThreshold, KVCacheUtilThreshold: &kvCacheUtilThreshold}}
+		}
+		detector, err := NewTieredDetector(parameters, datastore)
+		if err != nil {
+			return nil, fmt.Errorf("invalid parameters of the '%s' saturation detector - %w", TieredSaturationDetectorType, err)
+		}
+
+		return detector.WithName(name), nil
+	}
+}
+
+// NewTieredDetector initializes a new TieredDetector and returns its pointer, or an error if the parameters
+// are invalid.
+func NewTieredDetector(parameters TieredParameters, datastore Datastore) (*TieredDetector, error) {
+	tiers := slices.Clone(parameters.Tiers)
+	slices.SortFunc(tiers, func(a, b Tier) int { return a.Priority - b.Priority })
+
+	detector := &TieredDetector{
+		typedName: plugins.TypedName{Type: TieredSaturationDetectorType, Name: TieredSaturationDetectorType},
+	}
+	for i, tier := range tiers {
+		if tier.QueueDepthThreshold == nil && tier.KVCacheUtilThreshold == nil {
+			return nil, fmt.Errorf("the tier of priority %d must have a queueDepthThreshold or a kvCacheUtilThreshold", tier.Priority)
+		}
+		config := &Config{
+			QueueDepthThreshold:       math.MaxInt,
+			KVCacheUtilThreshold:      1,
+			MetricsStalenessThreshold: parameters.MetricsStalenessThreshold.Duration,
+		}
+		if tier.QueueDepthThreshold != nil {
+			config.QueueDepthThreshold = *tier.QueueDepthThreshold
+		}
+		if tier.KVCacheUtilThreshold != nil {
+			config.KVCacheUtilThreshold = *tier.KVCacheUtilThreshold
+		}
+		if err := validateConfig(config); err != nil {
+			return nil, fmt.Errorf("invalid tier of priority %d - %w", tier.Priority, err)
+		}
+		if i > 0 {
+			lower := detector.tiers[i-1]
+			switch {
+			case tier.Priority == lower.priority:
+				return nil, fmt.Errorf("priority %d is used by more than one tier", tier.Priority)
+			case config.QueueDepthThreshold < lower.config.QueueDepthThreshold || config.KVCacheUtilThreshold < lower.config.KVCacheUtilThreshold:
+				return nil, fmt.Errorf("the thresholds of the tier of priority %d must not be below the thresholds of the tier of priority %d",
+					tier.Priority, lower.priority)
+			}
+		}
+		detector.tiers = append(detector.tiers, tieredDetector{
+			priority: tier.Priority,
+			Detector: &Detector{datastore: datastore, config: config},
+		})
+	}
+	return detector, nil
+}
+
+// TieredDetector is a saturation detector that sheds the requests of different priorities at different saturation
+// levels, so that tiers of traffic degrade one after another rather than all at once. The requests of each tier are
+// considered saturated when no pod has capacity within the thresholds of the tier (see Detector). Requests with a
+// priority above the highest tier are never shed, and tiers of non-negative priorities make requests that are
+// otherwise never shed sheddable as a last resort.
+type TieredDetector struct {
+	typedName plugins.TypedName
+	tiers     []tieredDetector // sorted by ascending priority
+}
+
+// tieredDetector is the threshold based Detector of the requests of a tier.
+type tieredDetector struct {
+	priority int
+	*Detector
+}
+
+// TypedName returns the type and name tuple of this plugin instance.
+func (d *TieredDetector) TypedName() plugins.TypedName {
+	return d.typedName
+}
+
+// WithName sets the name of the detector.
+func (d *TieredDetector) WithName(name string) *TieredDetector {
+	d.typedName.Name = name
+	return d
+}
+
+// IsSaturated checks if the system is currently considered saturated for the requests of the lowest tier, the first
+// ones to be shed.
+func (d *TieredDetector) IsSaturated(ctx context.Context) bool {
+	return d.tiers[0].IsSaturated(ctx)
+}
+
+// RetryAfter estimates how long it takes until a saturated system has capacity for new requests again, based on the
+// thresholds of the lowest tier.
+func (d *TieredDetector) RetryAfter(ctx context.Context) time.Duration {
+	return d.tiers[0].RetryAfter(ctx)
+}
+
+// Admit checks if the system is currently considered saturated for the request, by the thresholds of the tier of
+// its priority and for its model. A shed request gets the estimate of how long it takes until the system has
+// capacity for the requests of its tier again. Requests above the highest tier are never shed.
+func (d *TieredDetector) Admit(ctx context.Context, request requestcontrol.AdmissionRequest) (bool, time.Duration) {
+	tier := d.tierOf(request.Priority)
+	if tier == nil || !tier.IsModelSaturated(ctx, request.TargetModel) {
+		return true, 0
+	}
+	return false, tier.RetryAfter(ctx)
+}
+
+// tierOf returns the tier of the requests of the priority, or nil if the priority is above the highest tier.
+func (d *TieredDetector) tierOf(priority int) *tieredDetector {
+	for i := range d.tiers {
+		if priority <= d.tiers[i].priority {
+			return &d.tiers[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package saturationdetector
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/requestcontrol"
+	"sigs.k8s.io/gateway-api-inference-extension/test/utils"
+)
+
+func TestTieredDetector_Admit(t *testing.T) {
+	params := `{"tiers": [
+		{"priority": -1, "kvCacheUtilThreshold": 0.85},
+		{"priority": -2, "kvCacheUtilThreshold": 0.7, "queueDepthThreshold": 5},
+		{"priority": 0, "kvCacheUtilThreshold": 0.98}
+	], "metricsStalenessThreshold": "1m"}`
+
+	tests := []struct {
+		name      string
+		kvCache   float64
+		queue     int
+		saturated map[int]bool // by priority
+	}{
+		{
+			name:      "below all thresholds",
+			kvCache:   0.5,
+			saturated: map[int]bool{-3: false, -2: false, -1: false, 0: false, 1: false},
+		},
+		{
+			name:      "above the lowest tier",
+			kvCache:   0.75,
+			saturated: map[int]bool{-3: true, -2: true, -1: false, 0: false, 1: false},
+		},
+		{
+			name:      "queue above the lowest tier only",
+			kvCache:   0.5,
+			queue:     100,
+			saturated: map[int]bool{-3: true, -2: true, -1: false, 0: false, 1: false},
+		},
+		{
+			name:      "above the middle tier",
+			kvCache:   0.9,
+			saturated: map[int]bool{-3: true, -2: true, -1: true, 0: false, 1: false},
+		},
+		{
+			name:      "above the highest tier",
+			kvCache:   0.99,
+			saturated: map[int]bool{-3: true, -2: true, -1: true, 0: true, 1: false},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			datastore := &mockDatastore{pods: []*backendmetrics.FakePodMetrics{
+				newMockPodMetrics("pod1", &backendmetrics.MetricsState{KVCacheUsagePercent: test.kvCache, WaitingQueueSize: test.queue,
+					UpdateTime: time.Now()}),
+			}}
+			parameters := TieredParameters{}
+			if err := json.Unmarshal([]byte(params), &parameters); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			detector, err := NewTieredDetector(parameters, datastore)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for priority, expected := range test.saturated {
+				request := requestcontrol.AdmissionRequest{Priority: priority, TargetModel: "model"}
+				if admitted, _ := detector.Admit(context.Background(), request); admitted == expected {
+					t.Errorf("Admit(%d) = %v, want %v", priority, admitted, !expected)
+				}
+			}
+			if got := detector.IsSaturated(context.Background()); got != test.saturated[-2] {
+				t.Errorf("IsSaturated() = %v, want the saturation of the lowest tier %v", got, test.saturated[-2])
+			}
+		})
+	}
+}
+
+func TestTieredDetector_AdmitRetryAfter(t *testing.T) {
+	parameters := TieredParameters{}
+	params := `{"tiers": [
+		{"priority": -2, "queueDepthThreshold": 5},
+		{"priority": -1, "queueDepthThreshold": 10}
+	], "metricsStalenessThreshold": "1m"}`
+	if err := json.Unmarshal([]byte(params), &parameters); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	datastore := &mockDatastore{pods: []*backendmetrics.FakePodMetrics{
+		newMockPodMetrics("pod1", &backendmetrics.MetricsState{WaitingQueueSize: 30, UpdateTime: time.Now()}),
+	}}
+	detector, err := NewTieredDetector(parameters, datastore)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// the queue exceeds the threshold of the lowest tier by 25, and the threshold of the highest tier by 20.
+	// Requests above the highest tier are admitted.
+	for priority, expected := range map[int]time.Duration{-3: 6 * time.Second, -2: 6 * time.Second, -1: 3 * time.Second, 0: 0} {
+		admitted, retryAfter := detector.Admit(context.Background(), requestcontrol.AdmissionRequest{Priority: priority})
+		if admitted != (expected == 0) || retryAfter != expected {
+			t.Errorf("Admit(%d) = %v, %v, want %v, %v", priority, admitted, retryAfter, expected == 0, expected)
+		}
+	}
+	if got := detector.RetryAfter(context.Background()); got != 6*time.Second {
+		t.Errorf("RetryAfter() = %v, want the estimate of the lowest tier %v", got, 6*time.Second)
+	}
+}
+
+func TestTieredSaturationDetectorFactory(t *testing.T) {
+	tests := []struct {
+		name      string
+		params    string
+		expectErr bool
+	}{
+		{name: "default tier", params: `{}`},
+		{name: "tier without thresholds", params: `{"tiers": [{"priority": -1}]}`, expectErr: true},
+		{name: "invalid threshold", params: `{"tiers": [{"priority": -1, "kvCacheUtilThreshold": 1.5}]}`, expectErr: true},
+		{
+			name:      "duplicate priority",
+			params:    `{"tiers": [{"priority": -1, "kvCacheUtilThreshold": 0.7}, {"priority": -1, "kvCacheUtilThreshold": 0.8}]}`,
+			expectErr: true,
+		},
+		{
+			name:      "higher tier with a lower threshold",
+			params:    `{"tiers": [{"priority": -2, "kvCacheUtilThreshold": 0.9}, {"priority": -1, "kvCacheUtilThreshold": 0.8}]}`,
+			expectErr: true,
+		},
+		{
+			name:      "higher tier considers a signal a lower tier doesn't",
+			params:    `{"tiers": [{"priority": -2, "queueDepthThreshold": 5}, {"priority": -1, "kvCacheUtilThreshold": 0.8}]}`,
+			expectErr: true,
+		},
+		{name: "malformed", params: `{"tiers": {}}`, expectErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := TieredSaturationDetectorFactory(&mockDatastore{})("tiered", json.RawMessage(test.params), utils.NewTestHandle(context.Background()))
+			if (err != nil) != test.expectErr {
+				t.Errorf("unexpected error %v, expected error %v", err, test.expectErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,233 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package saturationdetector
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/plugins"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/requestcontrol"
+	schedulingtypes "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/logging"
+)
+
+const (
+	WeightedSaturationDetectorType = "weighted-saturation-detector"
+
+	// the signals the WeightedDetector can combine.
+	QueueDepthSignal      = "queueDepth"
+	KVCacheUtilSignal     = "kvCacheUtilization"
+	RunningRequestsSignal = "runningRequests"
+
+	// DefaultSaturationScore is the default score above which a pod has insufficient capacity.
+	DefaultSaturationScore = 1.0
+)
+
+// Signal is a pod metric that contributes to the saturation score of a pod.
+type Signal struct {
+	// Name is the name of the metric, one of queueDepth, kvCacheUtilization and runningRequests.
+	Name string `json:"name"`
+	// Threshold is the value of the metric the metric is normalized by. A pod whose metric is at the threshold
+	// contributes a score of 1 for the signal.
+	Threshold float64 `json:"threshold"`
+	// Weight is the relative weight of the signal in the score of a pod.
+	Weight float64 `json:"weight"`
+}
+
+// WeightedParameters are the parameters of the WeightedDetector.
+type WeightedParameters struct {
+	// Signals are the pod metrics that are combined into the saturation score of a pod.
+	Signals []Signal `json:"signals"`
+	// SaturationScore is the score above which a pod has insufficient capacity.
+	SaturationScore float64 `json:"saturationScore"`
+	// MetricsStalenessThreshold is how old the metrics of a pod can be for the pod to have capacity.
+	MetricsStalenessThreshold metav1.Duration `json:"metricsStalenessThreshold"`
+	// PriorityAdapters are the LoRA adapters the reserved adapter slots of every pod are reserved for.
+	PriorityAdapters []string `json:"priorityAdapters"`
+	// LoraReservedSlots is the number of adapter slots of every pod that are reserved for the PriorityAdapters.
+	LoraReservedSlots int `json:"loraReservedSlots"`
+}
+
+// compile-time type assertion
+var (
+	_ SaturationDetector                     = &WeightedDetector{}
+	_ requestcontrol.ModelSaturationDetector = &WeightedDetector{}
+	_ requestcontrol.AdapterSlotReservation  = &WeightedDetector{}
+)
+
+// WeightedSaturationDetectorFactory returns the factory function of the WeightedDetector, which lists the pods
+// from the datastore.
+func WeightedSaturationDetectorFactory(datastore Datastore) plugins.FactoryFunc {
+	return func(name string, rawParameters json.RawMessage, _ plugins.Handle) (plugins.Plugin, error) {
+		parameters := WeightedParameters{
+			SaturationScore:           DefaultSaturationScore,
+			MetricsStalenessThreshold: metav1.Duration{Duration: DefaultMetricsStalenessThreshold},
+		}
+		if rawParameters != nil {
+			if err := json.Unmarshal(rawParameters, &parameters); err != nil {
+				return nil, fmt.Errorf("failed to parse the parameters of the '%s' saturation detector - %w", WeightedSaturationDetectorType, err)
+			}
+		}
+		if len(parameters.Signals) == 0 {
+			parameters.Signals = []Signal{
+				{Name: QueueDepthSignal, Threshold: DefaultQueueDepthThreshold, Weight: 1},
+				{Name: KVCacheUtilSignal, Threshold: DefaultKVCacheUtilThreshold, Weight: 1},
+			}
+		}
+		if err := validateWeightedParameters(parameters); err != nil {
+			return nil, fmt.Errorf("invalid parameters of the '%s' saturation detector - %w", WeightedSaturationDetectorType, err)
+		}
+
+		return NewWeightedDetector(parameters, datastore).WithName(name), nil
+	}
+}
+
+func validateWeightedParameters(parameters WeightedParameters) error {
+	for _, signal := range parameters.Signals {
+		switch {
+		case signal.Name != QueueDepthSignal && signal.Name != KVCacheUtilSignal && signal.Name != RunningRequestsSignal:
+			return fmt.Errorf("unknown signal '%s', must be one of %s, %s and %s", signal.Name, QueueDepthSignal,
+				KVCacheUtilSignal, RunningRequestsSignal)
+		case signal.Threshold <= 0:
+			return fmt.Errorf("threshold %v of signal '%s' must be positive", signal.Threshold, signal.Name)
+		case signal.Weight < 0:
+			return fmt.Errorf("weight %v of signal '%s' must not be negative", signal.Weight, signal.Name)
+		}
+	}
+	switch {
+	case totalWeight(parameters.Signals) <= 0:
+		return errors.New("the total weight of the signals must be positive")
+	case parameters.SaturationScore <= 0:
+		return fmt.Errorf("saturationScore %v must be positive", parameters.SaturationScore)
+	case parameters.MetricsStalenessThreshold.Duration <= 0:
+		return fmt.Errorf("metricsStalenessThreshold %v must be positive", parameters.MetricsStalenessThreshold.Duration)
+	case parameters.LoraReservedSlots < 0:
+		return fmt.Errorf("loraReservedSlots %d must not be negative", parameters.LoraReservedSlots)
+	case parameters.LoraReservedSlots > 0 && len(parameters.PriorityAdapters) == 0:
+		return errors.New("loraReservedSlots requires priorityAdapters")
+	}
+	return nil
+}
+
+func totalWeight(signals []Signal) float64 {
+	total := 0.0
+	for _, signal := range signals {
+		total += signal.Weight
+	}
+	return total
+}
+
+// NewWeightedDetector initializes a new WeightedDetector and returns its pointer.
+func NewWeightedDetector(parameters WeightedParameters, datastore Datastore) *WeightedDetector {
+	return &WeightedDetector{
+		typedName:  plugins.TypedName{Type: WeightedSaturationDetectorType, Name: WeightedSaturationDetectorType},
+		datastore:  datastore,
+		parameters: parameters,
+		adapterSlots: &Detector{datastore: datastore, config: &Config{
+			PriorityAdapters:  parameters.PriorityAdapters,
+			LoraReservedSlots: parameters.LoraReservedSlots,
+		}},
+	}
+}
+
+// WeightedDetector is a saturation detector that combines several pod metrics into a saturation score.
+// Every metric is normalized by its threshold, and the score of a pod is the weighted average of the normalized
+// metrics. The system is saturated if no pod with fresh metrics has a score at or below the SaturationScore, so
+// a pod that is high on one metric can still have capacity if it is low on the others.
+type WeightedDetector struct {
+	typedName  plugins.TypedName
+	datastore  Datastore
+	parameters WeightedParameters
+	// adapterSlots decides the room of the pods for LoRA adapters.
+	adapterSlots *Detector
+}
+
+// TypedName returns the type and name tuple of this plugin instance.
+func (d *WeightedDetector) TypedName() plugins.TypedName {
+	return d.typedName
+}
+
+// WithName sets the name of the detector.
+func (d *WeightedDetector) WithName(name string) *WeightedDetector {
+	d.typedName.Name = name
+	return d
+}
+
+// IsSaturated checks if the system is currently considered saturated.
+func (d *WeightedDetector) IsSaturated(ctx context.Context) bool {
+	logger := log.FromContext(ctx).WithName(loggerName)
+	for _, podMetric := range d.datastore.PodList(backendmetrics.AllPodsPredicate) {
+		if d.hasCapacity(logger, podMetric) {
+			return false
+		}
+	}
+
+	logger.V(logutil.VERBOSE).Info("No pods found with a saturation score within the limit; system is considered SATURATED.")
+	return true
+}
+
+// IsModelSaturated checks if the system is currently considered saturated for requests for the model. It is
+// saturated if no pod has a saturation score within the limit and room for the model, by the adapter slots of
+// the threshold based Detector (see Detector.IsModelSaturated).
+func (d *WeightedDetector) IsModelSaturated(ctx context.Context, model string) bool {
+	return d.adapterSlots.isModelSaturated(ctx, model, d.hasCapacity)
+}
+
+// PodsWithAdapterSlot returns the pods that have an adapter slot for the model that is not reserved for the
+// PriorityAdapters (see Detector.PodsWithAdapterSlot).
+func (d *WeightedDetector) PodsWithAdapterSlot(ctx context.Context, model string, pods []schedulingtypes.Pod) []schedulingtypes.Pod {
+	return d.adapterSlots.PodsWithAdapterSlot(ctx, model, pods)
+}
+
+// hasCapacity returns true if the pod has fresh metrics and a saturation score within the limit.
+func (d *WeightedDetector) hasCapacity(logger logr.Logger, podMetric backendmetrics.PodMetrics) bool {
+	metrics := podMetric.GetMetrics()
+	if metrics == nil || time.Since(metrics.UpdateTime) > d.parameters.MetricsStalenessThreshold.Duration {
+		return false
+	}
+	score := d.score(metrics)
+	logger.V(logutil.TRACE).Info("Computed saturation score", "pod", podMetric.GetPod().NamespacedName, "score", score,
+		"saturationScore", d.parameters.SaturationScore)
+	return score <= d.parameters.SaturationScore
+}
+
+// score returns the weighted average of the normalized signals of the pod metrics.
+func (d *WeightedDetector) score(metrics *backendmetrics.MetricsState) float64 {
+	score := 0.0
+	for _, signal := range d.parameters.Signals {
+		var value float64
+		switch signal.Name {
+		case QueueDepthSignal:
+			value = float64(metrics.WaitingQueueSize)
+		case KVCacheUtilSignal:
+			value = metrics.KVCacheUsagePercent
+		case RunningRequestsSignal:
+			value = float64(metrics.RunningQueueSize)
+		}
+		score += signal.Weight * value / signal.Threshold
+	}
+	return score / totalWeight(d.parameters.Signals)
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package saturationdetector
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
+	"sigs.k8s.io/gateway-api-inference-extension/test/utils"
+)
+
+func TestWeightedDetector_IsSaturated(t *testing.T) {
+	parameters := WeightedParameters{
+		Signals: []Signal{
+			{Name: QueueDepthSignal, Threshold: 10, Weight: 1},
+			{Name: KVCacheUtilSignal, Threshold: 0.8, Weight: 2},
+			{Name: RunningRequestsSignal, Threshold: 20, Weight: 1},
+		},
+		SaturationScore:           1,
+		MetricsStalenessThreshold: metav1.Duration{Duration: time.Second},
+	}
+
+	tests := []struct {
+		name     string
+		pods     []*backendmetrics.FakePodMetrics
+		expected bool
+	}{
+		{
+			name:     "no pods",
+			expected: true,
+		},
+		{
+			name: "pod high on one signal but low on the others",
+			pods: []*backendmetrics.FakePodMetrics{
+				// (15/10 + 2*0.4/0.8 + 10/20) / 4 = 0.75
+				newMockPodMetrics("pod1", &backendmetrics.MetricsState{WaitingQueueSize: 15, KVCacheUsagePercent: 0.4,
+					RunningQueueSize: 10, UpdateTime: time.Now()}),
+			},
+			expected: false,
+		},
+		{
+			name: "pod high on all signals",
+			pods: []*backendmetrics.FakePodMetrics{
+				// (12/10 + 2*0.8/0.8 + 20/20) / 4 = 1.05
+				newMockPodMetrics("pod1", &backendmetrics.MetricsState{WaitingQueueSize: 12, KVCacheUsagePercent: 0.8,
+					RunningQueueSize: 20, UpdateTime: time.Now()}),
+			},
+			expected: true,
+		},
+		{
+			name: "pod at the saturation score",
+			pods: []*backendmetrics.FakePodMetrics{
+				newMockPodMetrics("pod1", &backendmetrics.MetricsState{WaitingQueueSize: 10, KVCacheUsagePercent: 0.8,
+					RunningQueueSize: 20, UpdateTime: time.Now()}),
+			},
+			expected: false,
+		},
+		{
+			name: "pod with capacity has stale metrics",
+			pods: []*backendmetrics.FakePodMetrics{
+				newMockPodMetrics("pod1", &backendmetrics.MetricsState{UpdateTime: time.Now().Add(-time.Minute)}),
+				newMockPodMetrics("pod2", &backendmetrics.MetricsState{WaitingQueueSize: 30, KVCacheUsagePercent: 1, UpdateTime: time.Now()}),
+			},
+			expected: true,
+		},
+		{
+			name: "one of several pods has capacity",
+			pods: []*backendmetrics.FakePodMetrics{
+				newMockPodMetrics("pod1", &backendmetrics.MetricsState{WaitingQueueSize: 30, KVCacheUsagePercent: 1, UpdateTime: time.Now()}),
+				newMockPodMetrics("pod2", &backendmetrics.MetricsState{WaitingQueueSize: 1, UpdateTime: time.Now()}),
+			},
+			expected: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			detector := NewWeightedDetector(parameters, &mockDatastore{pods: test.pods})
+			if got := detector.IsSaturated(context.Background()); got != test.expected {
+				t.Errorf("IsSaturated() = %v, want %v", got, test.expected)
+			}
+		})
+	}
+}
+
+func TestWeightedDetector_IsModelSaturated(t *testing.T) {
+	parameters := WeightedParameters{
+		Signals:                   []Signal{{Name: QueueDepthSignal, Threshold: 10, Weight: 1}},
+		SaturationScore:           1,
+		MetricsStalenessThreshold: metav1.Duration{Duration: time.Second},
+		PriorityAdapters:          []string{"premium-lora"},
+		LoraReservedSlots:         1,
+	}
+	// pod1 has capacity with only its reserved adapter slot free, and pod2 has lora3 active without capacity.
+	pods := []*backendmetrics.FakePodMetrics{
+		newMockPodMetrics("pod1", &backendmetrics.MetricsState{ActiveModels: map[string]int{"lora1": 1, "lora2": 1},
+			MaxActiveModels: 3, UpdateTime: time.Now()}),
+		newMockPodMetrics("pod2", &backendmetrics.MetricsState{WaitingQueueSize: 20, ActiveModels: map[string]int{"lora3": 1},
+			MaxActiveModels: 3, UpdateTime: time.Now()}),
+	}
+	detector := NewWeightedDetector(parameters, &mockDatastore{pods: pods})
+
+	for model, expected := range map[string]bool{"lora3": true, "premium-lora": false, "base-model": false} {
+		if got := detector.IsModelSaturated(context.Background(), model); got != expected {
+			t.Errorf("IsModelSaturated(%s) = %v, want %v", model, got, expected)
+		}
+	}
+}
+
+func TestWeightedSaturationDetectorFactory(t *testing.T) {
+	tests := []struct {
+		name      string
+		params    string
+		expectErr bool
+	}{
+		{name: "defaults", params: "{}"},
+		{name: "valid", params: `{"signals": [{"name": "runningRequests", "threshold": 32, "weight": 1}], "saturationScore": 0.9}`},
+		{name: "unknown signal", params: `{"signals": [{"name": "latency", "threshold": 1, "weight": 1}]}`, expectErr: true},
+		{name: "zero threshold", params: `{"signals": [{"name": "queueDepth", "threshold": 0, "weight": 1}]}`, expectErr: true},
+		{name: "zero total weight", params: `{"signals": [{"name": "queueDepth", "threshold": 5, "weight": 0}]}`, expectErr: true},
+		{name: "zero saturation score", params: `{"saturationScore": 0}`, expectErr: true},
+		{name: "malformed", params: `{"signals": 1}`, expectErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := WeightedSaturationDetectorFactory(&mockDatastore{})("weighted", json.RawMessage(test.params), utils.NewTestHandle(context.Background()))
+			if (err != nil) != test.expectErr {
+				t.Errorf("unexpected error %v, expected error %v", err, test.expectErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datalayer/models"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/plugins"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/logging"
+)
+
+const (
+	ModelAwareFilterType = "model-aware-filter"
+)
+
+// ModelAwareFilterConfig is the configuration of the ModelAwareFilter.
+type ModelAwareFilterConfig struct {
+	// DynamicLoading keeps the endpoints that serve the base model of a requested adapter, which is loaded by
+	// other endpoints, so that the adapter can be loaded on them dynamically. A requested model that no endpoint
+	// serves keeps all the endpoints, since it may be loaded on any of them (e.g. by the lora-adapter-loader).
+	DynamicLoading bool `json:"dynamicLoading"`
+}
+
+// compile-time type assertion
+var _ framework.Filter = &ModelAwareFilter{}
+
+// ModelAwareFilterFactory defines the factory function for ModelAwareFilter.
+func ModelAwareFilterFactory(name string, rawParameters json.RawMessage, _ plugins.Handle) (plugins.Plugin, error) {
+	config := ModelAwareFilterConfig{}
+	if rawParameters != nil {
+		if err := json.Unmarshal(rawParameters, &config); err != nil {
+			return nil, fmt.Errorf("failed to parse the parameters of the '%s' filter - %w", ModelAwareFilterType, err)
+		}
+	}
+
+	return NewModelAwareFilter(config).WithName(name), nil
+}
+
+// NewModelAwareFilter initializes a new ModelAwareFilter and returns its pointer.
+func NewModelAwareFilter(config ModelAwareFilterConfig) *ModelAwareFilter {
+	return &ModelAwareFilter{
+		typedName: plugins.TypedName{Type: ModelAwareFilterType, Name: ModelAwareFilterType},
+		config:    config,
+	}
+}
+
+// ModelAwareFilter keeps the endpoints that serve the target model of the request, a base model or an adapter,
+// as discovered from their model list. Endpoints whose models were not discovered are assumed to serve every model.
+// If no endpoint serves the model, all the endpoints are kept for a model provider to load it.
+type ModelAwareFilter struct {
+	typedName plugins.TypedName
+	config    ModelAwareFilterConfig
+}
+
+// WithName sets the name of the filter.
+func (f *ModelAwareFilter) WithName(name string) *ModelAwareFilter {
+	f.typedName.Name = name
+	return f
+}
+
+// TypedName returns the type and name tuple of this plugin instance.
+func (f *ModelAwareFilter) TypedName() plugins.TypedName {
+	return f.typedName
+}
+
+// Filter filters out the pods that can't serve the target model of the request.
+func (f *ModelAwareFilter) Filter(ctx context.Context, _ *types.CycleState, request *types.LLMRequest, pods []types.Pod) []types.Pod {
+	baseModel := ""
+	if f.config.DynamicLoading {
+		if !slices.ContainsFunc(pods, func(pod types.Pod) bool { return servedByPod(pod, request.TargetModel) }) {
+			return pods
+		}
+		baseModel = adapterBaseModel(pods, request.TargetModel)
+	}
+
+	filteredPods := make([]types.Pod, 0, len(pods))
+	for _, pod := range pods {
+		served, found := models.GetServedModels(pod.GetAttributes())
+		if !found || served.Serves(request.TargetModel) || (baseModel != "" && slices.Contains(served.BaseModels, baseModel)) {
+			filteredPods = append(filteredPods, pod)
+		}
+	}
+	if len(filteredPods) == 0 {
+		// The director only schedules a request for a model no endpoint serves if a model provider loads it on
+		// demand, on any of the endpoints.
+		filteredPods = pods
+	}
+	log.FromContext(ctx).V(logutil.TRACE).Info("Filtered pods by the target model", "targetModel", request.TargetModel,
+		"baseModel", baseModel, "pods", len(pods), "filteredPods", len(filteredPods))
+	return filteredPods
+}
+
+// servedByPod returns true if the model was discovered on the pod.
+func servedByPod(pod types.Pod, model string) bool {
+	served, found := models.GetServedModels(pod.GetAttributes())
+	return found && served.Serves(model)
+}
+
+// adapterBaseModel returns the base model of the adapter, as discovered from the pods it is loaded on, or an empty
+// string if the model is not a loaded adapter.
+func adapterBaseModel(pods []types.Pod, adapter string) string {
+	for _, pod := range pods {
+		if served, found := models.GetServedModels(pod.GetAttributes()); found {
+			if baseModel, found := served.Adapters[adapter]; found {
+				return baseModel
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datalayer"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datalayer/models"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+)
+
+func TestModelAwareFilter(t *testing.T) {
+	pods := newTestPods("llama-with-adapter", "llama", "mistral", "not-discovered")
+	served := []*models.ServedModels{
+		{BaseModels: []string{"llama"}, Adapters: map[string]string{"sql-lora": "llama"}},
+		{BaseModels: []string{"llama"}},
+		{BaseModels: []string{"mistral"}},
+	}
+	for i, servedModels := range served {
+		attributes := datalayer.NewAttributes()
+		attributes.Put(models.ServedModelsAttributeKey, servedModels)
+		pods[i].(*types.PodMetrics).Attributes = attributes
+	}
+
+	tests := []struct {
+		name          string
+		rawParameters string
+		pods          []types.Pod
+		targetModel   string
+		wantPods      []string
+	}{
+		{
+			name:        "base model",
+			targetModel: "llama",
+			wantPods:    []string{"llama-with-adapter", "llama", "not-discovered"},
+		},
+		{
+			name:        "adapter",
+			targetModel: "sql-lora",
+			wantPods:    []string{"llama-with-adapter", "not-discovered"},
+		},
+		{
+			name:          "adapter with dynamic loading",
+			rawParameters: `{"dynamicLoading": true}`,
+			targetModel:   "sql-lora",
+			wantPods:      []string{"llama-with-adapter", "llama", "not-discovered"},
+		},
+		{
+			name:        "unknown model",
+			targetModel: "unknown",
+			wantPods:    []string{"not-discovered"},
+		},
+		{
+			name:        "unknown model without undiscovered pods",
+			pods:        pods[:3],
+			targetModel: "unknown",
+			wantPods:    []string{"llama-with-adapter", "llama", "mistral"},
+		},
+		{
+			name:          "unknown model with dynamic loading",
+			rawParameters: `{"dynamicLoading": true}`,
+			targetModel:   "unknown",
+			wantPods:      []string{"llama-with-adapter", "llama", "mistral", "not-discovered"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var rawParameters json.RawMessage
+			if test.rawParameters != "" {
+				rawParameters = json.RawMessage(test.rawParameters)
+			}
+			plugin, err := ModelAwareFilterFactory("models", rawParameters, nil)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			testPods := pods
+			if test.pods != nil {
+				testPods = test.pods
+			}
+			got := plugin.(*ModelAwareFilter).Filter(context.Background(), nil, &types.LLMRequest{TargetModel: test.targetModel}, testPods)
+
+			if diff := cmp.Diff(test.wantPods, podNames(got)); diff != "" {
+				t.Errorf("Unexpected filtered pods (-want +got): %v", diff)
+			}
+		})
+	}
+}
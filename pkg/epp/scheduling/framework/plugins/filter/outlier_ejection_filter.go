@@ -0,0 +1,488 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/metrics"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/plugins"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/requestcontrol"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/logging"
+)
+
+const (
+	OutlierEjectionFilterType = "outlier-ejection-filter"
+
+	// DefaultConsecutiveErrors is the default number of consecutive failures that eject an endpoint.
+	DefaultConsecutiveErrors = 5
+	// DefaultErrorRateThreshold is the default failure rate within the interval that ejects an endpoint.
+	DefaultErrorRateThreshold = 0.5
+	// DefaultOutlierInterval is the default interval the failure rate and the latency of an endpoint are measured over.
+	DefaultOutlierInterval = 30 * time.Second
+	// DefaultMinRequestVolume is the default minimum number of requests within the interval for the failure rate
+	// and the latency of an endpoint to be considered.
+	DefaultMinRequestVolume = 10
+	// DefaultLatencyFactor is the default multiple of the median latency of the endpoints that ejects an endpoint.
+	DefaultLatencyFactor = 3.0
+	// DefaultBaseEjectionTime is the default time an endpoint is ejected for on its first ejection.
+	DefaultBaseEjectionTime = 30 * time.Second
+	// DefaultMaxEjectionTime is the default maximum time an endpoint is ejected for.
+	DefaultMaxEjectionTime = 5 * time.Minute
+	// DefaultMaxEjectionPercent is the default maximum percentage of the candidate endpoints that are filtered out.
+	DefaultMaxEjectionPercent = 50
+
+	// ejection reasons, as reported in metrics and in the debug API.
+	consecutiveErrorsReason = "consecutive_errors"
+	errorRateReason         = "error_rate"
+	latencyReason           = "latency"
+
+	// windowBuckets is the number of buckets the interval of an endpoint's sliding window is split into.
+	windowBuckets = 10
+)
+
+// OutlierEjectionFilterConfig is the configuration of the OutlierEjectionFilter.
+type OutlierEjectionFilterConfig struct {
+	// ConsecutiveErrors is the number of consecutive failures that eject an endpoint. 0 disables it.
+	ConsecutiveErrors int `json:"consecutiveErrors"`
+	// ErrorRateThreshold is the failure rate, between 0 and 1, within the interval that ejects an endpoint.
+	// 0 disables it.
+	ErrorRateThreshold float64 `json:"errorRateThreshold"`
+	// Interval is the sliding interval the failure rate and the latency of an endpoint are measured over.
+	Interval metav1.Duration `json:"interval"`
+	// MinRequestVolume is the minimum number of requests within the interval for the failure rate and the
+	// latency of an endpoint to be considered.
+	MinRequestVolume int `json:"minRequestVolume"`
+	// LatencyFactor ejects an endpoint whose mean latency per output token within the interval is above this
+	// multiple of the median of the mean latencies of the other endpoints. 0 disables it.
+	LatencyFactor float64 `json:"latencyFactor"`
+	// BaseEjectionTime is the time an endpoint is ejected for on its first ejection. The time doubles on every
+	// further ejection, until the endpoint has not been ejected for MaxEjectionTime.
+	BaseEjectionTime metav1.Duration `json:"baseEjectionTime"`
+	// MaxEjectionTime is the maximum time an endpoint is ejected for.
+	MaxEjectionTime metav1.Duration `json:"maxEjectionTime"`
+	// MaxEjectionPercent is the maximum percentage of the candidate endpoints that are filtered out. The
+	// endpoints that were ejected first are filtered out, so the endpoints ejected beyond the limit remain
+	// candidates until the ejection of an endpoint ahead of them ends.
+	MaxEjectionPercent int `json:"maxEjectionPercent"`
+}
+
+// compile-time type assertion
+var (
+	_ framework.Filter                = &OutlierEjectionFilter{}
+	_ requestcontrol.OutlierTracker   = &OutlierEjectionFilter{}
+	_ requestcontrol.ResponseComplete = &OutlierEjectionFilter{}
+	_ plugins.DebugHandler            = &OutlierEjectionFilter{}
+)
+
+// OutlierEjectionFilterFactory defines the factory function for OutlierEjectionFilter.
+func OutlierEjectionFilterFactory(name string, rawParameters json.RawMessage, _ plugins.Handle) (plugins.Plugin, error) {
+	config := OutlierEjectionFilterConfig{
+		ConsecutiveErrors:  DefaultConsecutiveErrors,
+		ErrorRateThreshold: DefaultErrorRateThreshold,
+		Interval:           metav1.Duration{Duration: DefaultOutlierInterval},
+		MinRequestVolume:   DefaultMinRequestVolume,
+		LatencyFactor:      DefaultLatencyFactor,
+		BaseEjectionTime:   metav1.Duration{Duration: DefaultBaseEjectionTime},
+		MaxEjectionTime:    metav1.Duration{Duration: DefaultMaxEjectionTime},
+		MaxEjectionPercent: DefaultMaxEjectionPercent,
+	}
+	if rawParameters != nil {
+		if err := json.Unmarshal(rawParameters, &config); err != nil {
+			return nil, fmt.Errorf("failed to parse the parameters of the '%s' filter - %w", OutlierEjectionFilterType, err)
+		}
+	}
+	if err := validateOutlierEjectionFilterConfig(config); err != nil {
+		return nil, fmt.Errorf("invalid parameters of the '%s' filter - %w", OutlierEjectionFilterType, err)
+	}
+
+	return NewOutlierEjectionFilter(config).WithName(name), nil
+}
+
+func validateOutlierEjectionFilterConfig(config OutlierEjectionFilterConfig) error {
+	switch {
+	case config.ConsecutiveErrors < 0:
+		return fmt.Errorf("consecutiveErrors %d must not be negative", config.ConsecutiveErrors)
+	case config.ErrorRateThreshold < 0 || config.ErrorRateThreshold > 1:
+		return fmt.Errorf("errorRateThreshold %v must be between 0 and 1", config.ErrorRateThreshold)
+	case config.Interval.Duration <= 0:
+		return fmt.Errorf("interval %v must be positive", config.Interval.Duration)
+	case config.MinRequestVolume < 1:
+		return fmt.Errorf("minRequestVolume %d must be positive", config.MinRequestVolume)
+	case config.LatencyFactor != 0 && config.LatencyFactor <= 1:
+		return fmt.Errorf("latencyFactor %v must be greater than 1, or 0 to disable it", config.LatencyFactor)
+	case config.BaseEjectionTime.Duration <= 0:
+		return fmt.Errorf("baseEjectionTime %v must be positive", config.BaseEjectionTime.Duration)
+	case config.MaxEjectionTime.Duration < config.BaseEjectionTime.Duration:
+		return fmt.Errorf("maxEjectionTime %v must not be less than baseEjectionTime %v", config.MaxEjectionTime.Duration,
+			config.BaseEjectionTime.Duration)
+	case config.MaxEjectionPercent < 0 || config.MaxEjectionPercent > 100:
+		return fmt.Errorf("maxEjectionPercent %d must be between 0 and 100", config.MaxEjectionPercent)
+	}
+	return nil
+}
+
+// NewOutlierEjectionFilter initializes a new OutlierEjectionFilter and returns its pointer.
+func NewOutlierEjectionFilter(config OutlierEjectionFilterConfig) *OutlierEjectionFilter {
+	return &OutlierEjectionFilter{
+		typedName: plugins.TypedName{Type: OutlierEjectionFilterType, Name: OutlierEjectionFilterType},
+		config:    config,
+		now:       time.Now,
+		endpoints: map[string]*endpointState{},
+	}
+}
+
+// OutlierEjectionFilter detects endpoints that fail to serve requests, from the outcomes of the requests they
+// were sent, and filters them out of scheduling for a backoff period. An endpoint is ejected on consecutive
+// failures, on a high failure rate within the interval, or on a mean latency per output token within the interval
+// that is far above the other endpoints'. A failure is a server error response, or the gateway failing over to
+// another endpoint. When the ejection time passes, the endpoint returns to scheduling with a clean record.
+type OutlierEjectionFilter struct {
+	typedName plugins.TypedName
+	config    OutlierEjectionFilterConfig
+	now       func() time.Time
+
+	mu        sync.Mutex
+	endpoints map[string]*endpointState // by the namespaced name of the pod
+}
+
+// endpointState is the outlier detection state of an endpoint.
+type endpointState struct {
+	window              slidingWindow
+	consecutiveFailures int
+	ejections           int // the number of ejections since the endpoint was last healthy for maxEjectionTime
+	ejectionReason      string
+	ejectedAt           time.Time
+	ejectedUntil        time.Time // zero if the endpoint is not ejected
+	returnedAt          time.Time
+	lastSeen            time.Time
+}
+
+func (s *endpointState) isEjected() bool {
+	return !s.ejectedUntil.IsZero()
+}
+
+// WithName sets the name of the filter.
+func (f *OutlierEjectionFilter) WithName(name string) *OutlierEjectionFilter {
+	f.typedName.Name = name
+	return f
+}
+
+// TypedName returns the type and name tuple of this plugin instance.
+func (f *OutlierEjectionFilter) TypedName() plugins.TypedName {
+	return f.typedName
+}
+
+// Filter filters out the ejected pods, up to maxEjectionPercent of the given pods.
+func (f *OutlierEjectionFilter) Filter(ctx context.Context, _ *types.CycleState, _ *types.LLMRequest, pods []types.Pod) []types.Pod {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	now := f.now()
+	f.returnExpired(ctx, now)
+
+	ejectedPods := []types.Pod{}
+	for _, pod := range pods {
+		if state, found := f.endpoints[pod.GetPod().NamespacedName.String()]; found && state.isEjected() {
+			ejectedPods = append(ejectedPods, pod)
+		}
+	}
+	if len(ejectedPods) == 0 {
+		return pods
+	}
+
+	maxEjected := len(pods) * f.config.MaxEjectionPercent / 100
+	if len(ejectedPods) > maxEjected {
+		// filter out the pods that were ejected first, so that an ejection beyond the limit doesn't let a pod that is
+		// already filtered out back in, as with the maximum ejection percent of Envoy's outlier detection.
+		sort.SliceStable(ejectedPods, func(i, j int) bool {
+			return f.endpoints[ejectedPods[i].GetPod().NamespacedName.String()].ejectedAt.
+				Before(f.endpoints[ejectedPods[j].GetPod().NamespacedName.String()].ejectedAt)
+		})
+		ejectedPods = ejectedPods[:maxEjected]
+	}
+
+	filteredPods := make([]types.Pod, 0, len(pods)-len(ejectedPods))
+	for _, pod := range pods {
+		if !slices.Contains(ejectedPods, pod) {
+			filteredPods = append(filteredPods, pod)
+		}
+	}
+	log.FromContext(ctx).V(logutil.DEBUG).Info("Filtered out ejected pods", "ejectedPods", ejectedPods)
+	return filteredPods
+}
+
+// RecordFailure records a request the pod failed to serve, and ejects the pod if it is an outlier.
+func (f *OutlierEjectionFilter) RecordFailure(ctx context.Context, pod *backend.Pod) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	now := f.now()
+	name := pod.NamespacedName.String()
+	state := f.endpointState(ctx, name, now)
+	bucket := state.window.bucket(now)
+	bucket.requests++
+	bucket.failures++
+	state.consecutiveFailures++
+	if state.isEjected() {
+		return
+	}
+
+	if f.config.ConsecutiveErrors > 0 && state.consecutiveFailures >= f.config.ConsecutiveErrors {
+		f.eject(ctx, name, state, consecutiveErrorsReason, now)
+		return
+	}
+	if total := state.window.total(now); f.config.ErrorRateThreshold > 0 && total.requests >= f.config.MinRequestVolume &&
+		float64(total.failures)/float64(total.requests) >= f.config.ErrorRateThreshold {
+		f.eject(ctx, name, state, errorRateReason, now)
+	}
+}
+
+// RecordSuccess records a request the pod served.
+func (f *OutlierEjectionFilter) RecordSuccess(ctx context.Context, pod *backend.Pod) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	now := f.now()
+	state := f.endpointState(ctx, pod.NamespacedName.String(), now)
+	state.window.bucket(now).requests++
+	state.consecutiveFailures = 0
+}
+
+// ResponseComplete records the latency per output token of a completed response, and ejects the pod that served
+// it if its mean latency is an outlier. Responses without output tokens, such as the responses of embeddings and
+// rerank requests, or responses without usage, are not recorded, since their latency per output token is unknown.
+func (f *OutlierEjectionFilter) ResponseComplete(ctx context.Context, request *types.LLMRequest, response *requestcontrol.Response, targetPod *backend.Pod) {
+	if targetPod == nil || response.Cancelled || response.RequestReceivedTimestamp.IsZero() || response.ResponseCompleteTimestamp.IsZero() {
+		return
+	}
+	if response.Usage.CompletionTokens <= 0 || (request != nil && request.Data != nil && request.Data.IsPooling()) {
+		return
+	}
+	latency := response.ResponseCompleteTimestamp.Sub(response.RequestReceivedTimestamp) / time.Duration(response.Usage.CompletionTokens)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	now := f.now()
+	name := targetPod.NamespacedName.String()
+	state := f.endpointState(ctx, name, now)
+	bucket := state.window.bucket(now)
+	bucket.latency += latency
+	bucket.latencySamples++
+	if f.config.LatencyFactor == 0 || state.isEjected() {
+		return
+	}
+
+	meanLatency, ok := f.meanLatency(state, now)
+	if !ok {
+		return
+	}
+	otherMeanLatencies := []time.Duration{}
+	for otherName, other := range f.endpoints {
+		if otherName == name || other.isEjected() {
+			continue
+		}
+		if otherMeanLatency, ok := f.meanLatency(other, now); ok {
+			otherMeanLatencies = append(otherMeanLatencies, otherMeanLatency)
+		}
+	}
+	if len(otherMeanLatencies) < 2 {
+		return // not enough endpoints to tell an outlier
+	}
+	slices.Sort(otherMeanLatencies)
+	medianLatency := otherMeanLatencies[len(otherMeanLatencies)/2]
+	if float64(meanLatency) > f.config.LatencyFactor*float64(medianLatency) {
+		f.eject(ctx, name, state, latencyReason, now)
+	}
+}
+
+// ServeHTTP writes the outlier detection state of the tracked endpoints as JSON.
+func (f *OutlierEjectionFilter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	now := f.now()
+	f.returnExpired(r.Context(), now)
+	statuses := make([]endpointStatus, 0, len(f.endpoints))
+	for name, state := range f.endpoints {
+		total := state.window.total(now)
+		status := endpointStatus{
+			Endpoint:            name,
+			Ejected:             state.isEjected(),
+			EjectionReason:      state.ejectionReason,
+			Ejections:           state.ejections,
+			ConsecutiveFailures: state.consecutiveFailures,
+			Requests:            total.requests,
+			Failures:            total.failures,
+		}
+		if state.isEjected() {
+			status.EjectedUntil = &metav1.Time{Time: state.ejectedUntil}
+		} else {
+			status.EjectionReason = ""
+		}
+		if total.latencySamples > 0 {
+			status.MeanLatencyPerOutputToken = (total.latency / time.Duration(total.latencySamples)).String()
+		}
+		statuses = append(statuses, status)
+	}
+	f.mu.Unlock()
+
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Endpoint < statuses[j].Endpoint })
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(statuses); err != nil {
+		log.FromContext(r.Context()).V(logutil.DEFAULT).Error(err, "Failed to write outlier detection state")
+	}
+}
+
+// endpointStatus is the outlier detection state of an endpoint, as returned by the debug API.
+type endpointStatus struct {
+	Endpoint                  string       `json:"endpoint"`
+	Ejected                   bool         `json:"ejected"`
+	EjectionReason            string       `json:"ejectionReason,omitempty"`
+	EjectedUntil              *metav1.Time `json:"ejectedUntil,omitempty"`
+	Ejections                 int          `json:"ejections"`
+	ConsecutiveFailures       int          `json:"consecutiveFailures"`
+	Requests                  int          `json:"requests"`
+	Failures                  int          `json:"failures"`
+	MeanLatencyPerOutputToken string       `json:"meanLatencyPerOutputToken,omitempty"`
+}
+
+// endpointState returns the state of the named endpoint, returning the endpoint from ejection if its ejection
+// time passed. Must be called with the lock held.
+func (f *OutlierEjectionFilter) endpointState(ctx context.Context, name string, now time.Time) *endpointState {
+	state, found := f.endpoints[name]
+	if !found {
+		state = &endpointState{window: slidingWindow{bucketSize: max(f.config.Interval.Duration/windowBuckets, 1)}}
+		f.endpoints[name] = state
+	}
+	f.returnIfExpired(ctx, name, state, now)
+	state.lastSeen = now
+	return state
+}
+
+// eject ejects the endpoint, for an ejection time that doubles on every consecutive ejection. Must be called with
+// the lock held.
+func (f *OutlierEjectionFilter) eject(ctx context.Context, name string, state *endpointState, reason string, now time.Time) {
+	if !state.returnedAt.IsZero() && now.Sub(state.returnedAt) >= f.config.MaxEjectionTime.Duration {
+		state.ejections = 0 // the endpoint was healthy long enough to start over
+	}
+	ejectionTime := f.config.MaxEjectionTime.Duration
+	if state.ejections < 32 {
+		ejectionTime = min(f.config.BaseEjectionTime.Duration*time.Duration(1<<state.ejections), ejectionTime)
+	}
+	state.ejections++
+	state.ejectionReason = reason
+	state.ejectedAt = now
+	state.ejectedUntil = now.Add(ejectionTime)
+
+	metrics.RecordEndpointEjected(name, reason)
+	log.FromContext(ctx).V(logutil.DEFAULT).Info("Ejected endpoint from scheduling", "endpoint", name, "reason", reason,
+		"ejectionTime", ejectionTime, "ejections", state.ejections)
+}
+
+// returnExpired returns the endpoints whose ejection time passed, and forgets the endpoints that were not seen for
+// a while, e.g. pods that were deleted. Must be called with the lock held.
+func (f *OutlierEjectionFilter) returnExpired(ctx context.Context, now time.Time) {
+	idleTimeout := f.config.Interval.Duration + f.config.MaxEjectionTime.Duration
+	for name, state := range f.endpoints {
+		f.returnIfExpired(ctx, name, state, now)
+		if !state.isEjected() && now.Sub(state.lastSeen) > idleTimeout {
+			delete(f.endpoints, name)
+			metrics.DeleteEndpointEjection(name)
+		}
+	}
+}
+
+// returnIfExpired returns the endpoint to scheduling with a clean record if its ejection time passed. Must be called
+// with the lock held.
+func (f *OutlierEjectionFilter) returnIfExpired(ctx context.Context, name string, state *endpointState, now time.Time) {
+	if !state.isEjected() || now.Before(state.ejectedUntil) {
+		return
+	}
+	state.ejectedUntil = time.Time{}
+	state.returnedAt = now
+	state.consecutiveFailures = 0
+	state.window.reset()
+
+	metrics.RecordEndpointReturned(name)
+	log.FromContext(ctx).V(logutil.DEFAULT).Info("Returned ejected endpoint to scheduling", "endpoint", name)
+}
+
+// meanLatency returns the mean latency per output token of the endpoint within the interval, if it has enough samples.
+func (f *OutlierEjectionFilter) meanLatency(state *endpointState, now time.Time) (time.Duration, bool) {
+	total := state.window.total(now)
+	if total.latencySamples < f.config.MinRequestVolume {
+		return 0, false
+	}
+	return total.latency / time.Duration(total.latencySamples), true
+}
+
+// slidingWindow counts the requests, failures and latencies of an endpoint over a sliding interval, in buckets.
+type slidingWindow struct {
+	bucketSize time.Duration
+	buckets    [windowBuckets]windowBucket
+}
+
+type windowBucket struct {
+	start          time.Time
+	requests       int
+	failures       int
+	latency        time.Duration // the sum of the latency samples
+	latencySamples int
+}
+
+// bucket returns the bucket of the given time, clearing it if it holds counts of an earlier interval.
+func (w *slidingWindow) bucket(now time.Time) *windowBucket {
+	start := now.Truncate(w.bucketSize)
+	bucket := &w.buckets[(start.UnixNano()/int64(w.bucketSize))%windowBuckets]
+	if !bucket.start.Equal(start) {
+		*bucket = windowBucket{start: start}
+	}
+	return bucket
+}
+
+// total returns the sum of the buckets within the interval that ends at the given time.
+func (w *slidingWindow) total(now time.Time) windowBucket {
+	oldest := now.Truncate(w.bucketSize).Add(-w.bucketSize * (windowBuckets - 1))
+	total := windowBucket{}
+	for _, bucket := range w.buckets {
+		if bucket.start.Before(oldest) {
+			continue
+		}
+		total.requests += bucket.requests
+		total.failures += bucket.failures
+		total.latency += bucket.latency
+		total.latencySamples += bucket.latencySamples
+	}
+	return total
+}
+
+func (w *slidingWindow) reset() {
+	w.buckets = [windowBuckets]windowBucket{}
+}
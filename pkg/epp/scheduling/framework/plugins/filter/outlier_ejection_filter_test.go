@@ -0,0 +1,255 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/handlers"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/requestcontrol"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+)
+
+func newTestOutlierEjectionFilter(t *testing.T, rawParameters string) (*OutlierEjectionFilter, *time.Time) {
+	t.Helper()
+	plugin, err := OutlierEjectionFilterFactory("outliers", json.RawMessage(rawParameters), nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	filter := plugin.(*OutlierEjectionFilter)
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	filter.now = func() time.Time { return now }
+	return filter, &now
+}
+
+func newTestPods(names ...string) []types.Pod {
+	pods := make([]types.Pod, len(names))
+	for i, name := range names {
+		pods[i] = &types.PodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Namespace: "default", Name: name}}}
+	}
+	return pods
+}
+
+func podNames(pods []types.Pod) []string {
+	names := make([]string, len(pods))
+	for i, pod := range pods {
+		names[i] = pod.GetPod().NamespacedName.Name
+	}
+	return names
+}
+
+func TestOutlierEjectionFilterFactory(t *testing.T) {
+	tests := []struct {
+		name          string
+		rawParameters string
+		wantErr       bool
+	}{
+		{name: "defaults", rawParameters: `{}`},
+		{name: "disabled detections", rawParameters: `{"consecutiveErrors": 0, "errorRateThreshold": 0, "latencyFactor": 0}`},
+		{name: "negative consecutive errors", rawParameters: `{"consecutiveErrors": -1}`, wantErr: true},
+		{name: "error rate above 1", rawParameters: `{"errorRateThreshold": 1.5}`, wantErr: true},
+		{name: "latency factor below 1", rawParameters: `{"latencyFactor": 0.5}`, wantErr: true},
+		{name: "max ejection time below base", rawParameters: `{"baseEjectionTime": "1m", "maxEjectionTime": "30s"}`, wantErr: true},
+		{name: "max ejection percent above 100", rawParameters: `{"maxEjectionPercent": 101}`, wantErr: true},
+		{name: "invalid json", rawParameters: `{"interval": 10}`, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := OutlierEjectionFilterFactory("outliers", json.RawMessage(test.rawParameters), nil)
+			if gotErr := err != nil; gotErr != test.wantErr {
+				t.Errorf("Unexpected error, want error %t, got %v", test.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestOutlierEjectionFilterConsecutiveErrors(t *testing.T) {
+	ctx := context.Background()
+	filter, now := newTestOutlierEjectionFilter(t, `{"consecutiveErrors": 3, "errorRateThreshold": 0, "maxEjectionPercent": 100}`)
+	pods := newTestPods("pod1", "pod2")
+	pod1 := pods[0].GetPod()
+
+	// a success resets the consecutive failures.
+	filter.RecordFailure(ctx, pod1)
+	filter.RecordFailure(ctx, pod1)
+	filter.RecordSuccess(ctx, pod1)
+	filter.RecordFailure(ctx, pod1)
+	filter.RecordFailure(ctx, pod1)
+	if diff := cmp.Diff([]string{"pod1", "pod2"}, podNames(filter.Filter(ctx, nil, nil, pods))); diff != "" {
+		t.Errorf("Unexpected filtered pods (-want +got): %v", diff)
+	}
+
+	filter.RecordFailure(ctx, pod1)
+	if diff := cmp.Diff([]string{"pod2"}, podNames(filter.Filter(ctx, nil, nil, pods))); diff != "" {
+		t.Errorf("Unexpected filtered pods (-want +got): %v", diff)
+	}
+
+	// the pod returns after the base ejection time.
+	*now = now.Add(DefaultBaseEjectionTime)
+	if diff := cmp.Diff([]string{"pod1", "pod2"}, podNames(filter.Filter(ctx, nil, nil, pods))); diff != "" {
+		t.Errorf("Unexpected filtered pods after the ejection time (-want +got): %v", diff)
+	}
+
+	// the ejection time doubles on the second ejection.
+	for range 3 {
+		filter.RecordFailure(ctx, pod1)
+	}
+	*now = now.Add(DefaultBaseEjectionTime)
+	if diff := cmp.Diff([]string{"pod2"}, podNames(filter.Filter(ctx, nil, nil, pods))); diff != "" {
+		t.Errorf("Unexpected filtered pods during the second ejection (-want +got): %v", diff)
+	}
+	*now = now.Add(DefaultBaseEjectionTime)
+	if diff := cmp.Diff([]string{"pod1", "pod2"}, podNames(filter.Filter(ctx, nil, nil, pods))); diff != "" {
+		t.Errorf("Unexpected filtered pods after the second ejection (-want +got): %v", diff)
+	}
+}
+
+func TestOutlierEjectionFilterErrorRate(t *testing.T) {
+	ctx := context.Background()
+	filter, now := newTestOutlierEjectionFilter(t, `{"consecutiveErrors": 0, "errorRateThreshold": 0.5, "minRequestVolume": 4, "maxEjectionPercent": 100}`)
+	pods := newTestPods("pod1", "pod2")
+	pod1 := pods[0].GetPod()
+
+	// failures out of the interval are not counted.
+	filter.RecordFailure(ctx, pod1)
+	filter.RecordFailure(ctx, pod1)
+	*now = now.Add(DefaultOutlierInterval)
+	filter.RecordSuccess(ctx, pod1)
+	filter.RecordFailure(ctx, pod1)
+	filter.RecordSuccess(ctx, pod1)
+	if diff := cmp.Diff([]string{"pod1", "pod2"}, podNames(filter.Filter(ctx, nil, nil, pods))); diff != "" {
+		t.Errorf("Unexpected filtered pods (-want +got): %v", diff)
+	}
+
+	filter.RecordFailure(ctx, pod1)
+	if diff := cmp.Diff([]string{"pod2"}, podNames(filter.Filter(ctx, nil, nil, pods))); diff != "" {
+		t.Errorf("Unexpected filtered pods (-want +got): %v", diff)
+	}
+}
+
+func TestOutlierEjectionFilterLatency(t *testing.T) {
+	ctx := context.Background()
+	filter, _ := newTestOutlierEjectionFilter(t, `{"latencyFactor": 3, "minRequestVolume": 2, "maxEjectionPercent": 100}`)
+	pods := newTestPods("pod1", "pod2", "pod3")
+
+	received := time.Now()
+	complete := func(pod types.Pod, latency time.Duration, outputTokens int) {
+		response := &requestcontrol.Response{
+			Usage:                     handlers.Usage{CompletionTokens: outputTokens},
+			RequestReceivedTimestamp:  received,
+			ResponseCompleteTimestamp: received.Add(latency),
+		}
+		filter.ResponseComplete(ctx, nil, response, pod.GetPod())
+	}
+	for range 2 {
+		complete(pods[1], 100*time.Millisecond, 10)
+		complete(pods[2], 150*time.Millisecond, 10)
+		// longer responses are not slower per output token.
+		complete(pods[0], time.Second, 100)
+	}
+	if diff := cmp.Diff([]string{"pod1", "pod2", "pod3"}, podNames(filter.Filter(ctx, nil, nil, pods))); diff != "" {
+		t.Errorf("Unexpected filtered pods (-want +got): %v", diff)
+	}
+
+	// responses without output tokens and pooling responses are not latency samples.
+	for range 4 {
+		complete(pods[0], 5*time.Second, 0)
+		filter.ResponseComplete(ctx, &types.LLMRequest{Data: &types.LLMRequestData{
+			Embeddings: &types.EmbeddingsRequest{Input: types.EmbeddingsInput{Texts: []string{"embed this"}}},
+		}}, &requestcontrol.Response{
+			Usage:                     handlers.Usage{CompletionTokens: 1},
+			RequestReceivedTimestamp:  received,
+			ResponseCompleteTimestamp: received.Add(5 * time.Second),
+		}, pods[0].GetPod())
+	}
+	if diff := cmp.Diff([]string{"pod1", "pod2", "pod3"}, podNames(filter.Filter(ctx, nil, nil, pods))); diff != "" {
+		t.Errorf("Unexpected filtered pods (-want +got): %v", diff)
+	}
+
+	for range 4 {
+		complete(pods[0], 5*time.Second, 10)
+	}
+	if diff := cmp.Diff([]string{"pod2", "pod3"}, podNames(filter.Filter(ctx, nil, nil, pods))); diff != "" {
+		t.Errorf("Unexpected filtered pods (-want +got): %v", diff)
+	}
+}
+
+func TestOutlierEjectionFilterMaxEjectionPercent(t *testing.T) {
+	ctx := context.Background()
+	filter, now := newTestOutlierEjectionFilter(t, `{"consecutiveErrors": 1, "maxEjectionPercent": 50}`)
+	pods := newTestPods("pod1", "pod2", "pod3", "pod4")
+
+	for _, pod := range pods[:3] {
+		filter.RecordFailure(ctx, pod.GetPod())
+		*now = now.Add(time.Second)
+	}
+	// only the two pods that were ejected first are filtered out.
+	if diff := cmp.Diff([]string{"pod3", "pod4"}, podNames(filter.Filter(ctx, nil, nil, pods))); diff != "" {
+		t.Errorf("Unexpected filtered pods (-want +got): %v", diff)
+	}
+	// a single pod is never filtered out.
+	if diff := cmp.Diff([]string{"pod1"}, podNames(filter.Filter(ctx, nil, nil, pods[:1]))); diff != "" {
+		t.Errorf("Unexpected filtered pods (-want +got): %v", diff)
+	}
+}
+
+func TestOutlierEjectionFilterServeHTTP(t *testing.T) {
+	ctx := context.Background()
+	filter, now := newTestOutlierEjectionFilter(t, `{"consecutiveErrors": 2}`)
+	pods := newTestPods("pod1", "pod2")
+
+	filter.RecordFailure(ctx, pods[0].GetPod())
+	filter.RecordFailure(ctx, pods[0].GetPod())
+	filter.RecordSuccess(ctx, pods[1].GetPod())
+
+	recorder := httptest.NewRecorder()
+	filter.ServeHTTP(recorder, httptest.NewRequest("GET", "/debug/plugins/outliers", nil))
+
+	got := []endpointStatus{}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &got); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := []endpointStatus{
+		{
+			Endpoint:            "default/pod1",
+			Ejected:             true,
+			EjectionReason:      consecutiveErrorsReason,
+			EjectedUntil:        &metav1.Time{Time: now.Add(DefaultBaseEjectionTime)},
+			Ejections:           1,
+			ConsecutiveFailures: 2,
+			Requests:            2,
+			Failures:            2,
+		},
+		{
+			Endpoint: "default/pod2",
+			Requests: 1,
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected debug state (-want +got): %v", diff)
+	}
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package prefix
+
+import (
+	"encoding/json"
+
+	"github.com/cespare/xxhash/v2"
+
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+)
+
+const (
+	// RawHashingMode hashes the completions prompt, or the JSON encoding of the chat messages.
+	RawHashingMode = "raw"
+	// ChatTemplateHashingMode renders chat-completions and responses requests the way a model server
+	// applies a chat template (tools, documents, then every message with its role delimiters), and
+	// starts a new block at every segment boundary. Completions requests are hashed as in RawHashingMode.
+	ChatTemplateHashingMode = "chat-template"
+)
+
+// renderChatSegments renders a chat-completions request into segments, in the order a chat template
+// lays them out in the prompt. Each tool, document and message is a separate segment, so that the
+// blocks of a segment depend only on the segments preceding it. This keeps the blocks of a shared
+// preamble (e.g. tool definitions or a system prompt) and of earlier turns of a conversation
+// identical between requests.
+func renderChatSegments(chat *types.ChatCompletionsRequest) [][]byte {
+	segments := make([][]byte, 0, len(chat.Tools)+len(chat.Documents)+len(chat.Messages))
+	for _, tool := range chat.Tools {
+		segments = append(segments, renderJSONSegment("tool", tool))
+	}
+	for _, document := range chat.Documents {
+		segments = append(segments, renderJSONSegment("document", document))
+	}
+	for _, message := range chat.Messages {
+		segments = append(segments, renderSegment(message.Role, renderContent(message.Content)))
+	}
+	return segments
+}
+
+// renderContent renders the content of a message. Media parts are rendered as a placeholder holding
+// the media hash, the same way chat templates insert a placeholder token for the media, so that the
+// same media produces the same blocks and different media produces different blocks.
+func renderContent(content types.Content) []byte {
+	if content.Structured == nil {
+		return []byte(content.Raw)
+	}
+	var rendered []byte
+	for i, part := range content.Structured {
+		if i > 0 {
+			rendered = append(rendered, '\n')
+		}
+		if part.Media != nil {
+			rendered = append(rendered, "<|"+part.Media.Type+":"+part.Media.Hash+"|>"...)
+		} else {
+			rendered = append(rendered, part.Text...)
+		}
+	}
+	return rendered
+}
+
+// renderSegment wraps the content with role delimiters.
+func renderSegment(role string, content []byte) []byte {
+	segment := make([]byte, 0, len(role)+len(content)+6)
+	segment = append(segment, "<|"...)
+	segment = append(segment, role...)
+	segment = append(segment, "|>\n"...)
+	segment = append(segment, content...)
+	return append(segment, '\n')
+}
+
+// renderResponsesSegments renders a responses request into segments, the same way as
+// renderChatSegments. The instructions are rendered as a system message, and function calls and their
+// outputs as messages of their own.
+func renderResponsesSegments(responses *types.ResponsesRequest) [][]byte {
+	segments := make([][]byte, 0, len(responses.Tools)+len(responses.Input.Items)+2)
+	for _, tool := range responses.Tools {
+		segments = append(segments, renderJSONSegment("tool", tool))
+	}
+	if responses.Instructions != "" {
+		segments = append(segments, renderSegment("system", []byte(responses.Instructions)))
+	}
+	if responses.Input.Items == nil {
+		return append(segments, renderSegment("user", []byte(responses.Input.Raw)))
+	}
+	for _, item := range responses.Input.Items {
+		switch item.Type {
+		case types.MessageInputItem:
+			segments = append(segments, renderSegment(item.Role, renderContent(item.Content)))
+		case types.FunctionCallInputItem:
+			segments = append(segments, renderSegment(item.Type, []byte(item.Name+item.Arguments)))
+		case types.FunctionCallOutputInputItem:
+			segments = append(segments, renderSegment(item.Type, []byte(item.Output)))
+		}
+	}
+	return segments
+}
+
+// renderJSONSegment renders a JSON value as a segment. Object keys are sorted by the JSON encoder, so
+// the rendering is stable regardless of the key order in the request.
+func renderJSONSegment(role string, value any) []byte {
+	content, err := json.Marshal(value)
+	if err != nil { // values were unmarshalled from JSON, so this is not expected
+		return renderSegment(role, nil)
+	}
+	return renderSegment(role, content)
+}
+
+// hashChatPrompt calculates the block hashes of a chat-completions or responses request rendered by
+// renderChatSegments or renderResponsesSegments. Every segment is divided into blocks of
+// cacheBlockSize, where the last block of a segment may be shorter. The chat template and its
+// arguments are hashed together with the model into the first block hash, since they change the
+// prompt the model server renders.
+func hashChatPrompt(request *types.LLMRequest, cacheBlockSize int, maxPrefixBlocks int) []BlockHash {
+	h := xxhash.New()
+	_, _ = h.Write([]byte(request.TargetModel))
+	var segments [][]byte
+	if chat := request.Data.ChatCompletions; chat != nil {
+		_, _ = h.Write([]byte(chat.ChatTemplate))
+		if len(chat.ChatTemplateKWArgs) > 0 {
+			if kwargs, err := json.Marshal(chat.ChatTemplateKWArgs); err == nil {
+				_, _ = h.Write(kwargs)
+			}
+		}
+		segments = renderChatSegments(chat)
+	} else if responses := request.Data.Responses; responses != nil {
+		segments = renderResponsesSegments(responses)
+	}
+	prevBlockHash := BlockHash(h.Sum64())
+
+	res := make([]BlockHash, 0, maxPrefixBlocks)
+	for _, segment := range segments {
+		for start := 0; start < len(segment); start += cacheBlockSize {
+			if len(res) == maxPrefixBlocks {
+				return res
+			}
+			end := min(start+cacheBlockSize, len(segment))
+			h.Reset()
+			_, _ = h.Write(segment[start:end])
+			_, _ = h.Write(toBytes(prevBlockHash))
+			prevBlockHash = BlockHash(h.Sum64())
+			res = append(res, prevBlockHash)
+		}
+	}
+	return res
+}
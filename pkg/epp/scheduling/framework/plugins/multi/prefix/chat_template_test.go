@@ -0,0 +1,133 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package prefix
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+)
+
+func chatRequest(tools []interface{}, messages ...types.Message) *types.LLMRequest {
+	return &types.LLMRequest{
+		TargetModel: "test-model",
+		Data: &types.LLMRequestData{
+			ChatCompletions: &types.ChatCompletionsRequest{Tools: tools, Messages: messages},
+		},
+	}
+}
+
+func TestHashChatPromptConversationGrowth(t *testing.T) {
+	system := types.Message{Role: "system", Content: types.Content{Raw: "You are a helpful assistant"}}
+	user := types.Message{Role: "user", Content: types.Content{Raw: "Hello, how are you?"}}
+	assistant := types.Message{Role: "assistant", Content: types.Content{Raw: "I'm fine, thanks"}}
+
+	first := hashChatPrompt(chatRequest(nil, system, user), 8, DefaultMaxPrefixBlocks)
+	second := hashChatPrompt(chatRequest(nil, system, user, assistant, user), 8, DefaultMaxPrefixBlocks)
+
+	// Every block of the first turn, including the trailing partial block of the last message, is
+	// shared with the next turn.
+	assert.NotEmpty(t, first)
+	assert.Greater(t, len(second), len(first))
+	assert.Equal(t, first, second[:len(first)])
+}
+
+func TestHashChatPromptSharedTools(t *testing.T) {
+	tools := []interface{}{
+		map[string]interface{}{"type": "function", "function": map[string]interface{}{"name": "get_weather"}},
+		map[string]interface{}{"type": "function", "function": map[string]interface{}{"name": "get_time"}},
+	}
+	toolBlocks := len(hashChatPrompt(chatRequest(tools), 16, DefaultMaxPrefixBlocks))
+
+	req1 := hashChatPrompt(chatRequest(tools, types.Message{Role: "user", Content: types.Content{Raw: "Weather in Paris?"}}), 16, DefaultMaxPrefixBlocks)
+	req2 := hashChatPrompt(chatRequest(tools, types.Message{Role: "user", Content: types.Content{Raw: "What time is it?"}}), 16, DefaultMaxPrefixBlocks)
+
+	assert.Greater(t, toolBlocks, 0)
+	assert.Equal(t, req1[:toolBlocks], req2[:toolBlocks], "tool preamble should produce the same blocks")
+	assert.NotEqual(t, req1[toolBlocks], req2[toolBlocks], "different user messages should produce different blocks")
+}
+
+func TestHashChatPromptTemplateAndLimit(t *testing.T) {
+	req := chatRequest(nil, types.Message{Role: "user", Content: types.Content{Raw: "Hello, how are you?"}})
+	withTemplate := chatRequest(nil, types.Message{Role: "user", Content: types.Content{Raw: "Hello, how are you?"}})
+	withTemplate.Data.ChatCompletions.ChatTemplate = "{{ messages }}"
+
+	assert.NotEqual(t, hashChatPrompt(req, 4, DefaultMaxPrefixBlocks)[0], hashChatPrompt(withTemplate, 4, DefaultMaxPrefixBlocks)[0],
+		"a different chat template should produce different blocks")
+	assert.Len(t, hashChatPrompt(req, 4, 2), 2, "hashes should be limited to maxPrefixBlocks")
+}
+
+func TestPrefixPluginChatTemplateHashingMode(t *testing.T) {
+	config := DefaultConfig
+	config.HashingMode = ChatTemplateHashingMode
+	config.HashBlockSize = 4
+	plugin := New(context.Background(), config)
+
+	req := chatRequest(nil, types.Message{Role: "user", Content: types.Content{Raw: "hi"}})
+	// Shorter than a block in raw mode is still hashed in chat template mode.
+	assert.Equal(t, hashChatPrompt(req, 4, DefaultMaxPrefixBlocks),
+		hashPrompt(context.Background(), req, plugin.config.HashingMode, plugin.config.HashBlockSize, plugin.config.MaxPrefixBlocksToMatch))
+}
+
+func TestHashChatPromptMedia(t *testing.T) {
+	withImage := func(hash string) types.Message {
+		return types.Message{Role: "user", Content: types.Content{Structured: []types.ContentPart{
+			{Type: types.ImageURLContentPart, Media: &types.MediaRef{Type: types.ImageMedia, Hash: hash, Size: 1024}},
+			{Type: types.TextContentPart, Text: "describe this image"},
+		}}}
+	}
+	system := types.Message{Role: "system", Content: types.Content{Raw: "You are a helpful assistant"}}
+
+	image1 := hashChatPrompt(chatRequest(nil, system, withImage("1")), 8, DefaultMaxPrefixBlocks)
+	image1Again := hashChatPrompt(chatRequest(nil, system, withImage("1")), 8, DefaultMaxPrefixBlocks)
+	image2 := hashChatPrompt(chatRequest(nil, system, withImage("2")), 8, DefaultMaxPrefixBlocks)
+	systemBlocks := len(hashChatPrompt(chatRequest(nil, system), 8, DefaultMaxPrefixBlocks))
+
+	assert.Equal(t, image1, image1Again, "the same image should produce the same blocks")
+	assert.Equal(t, image1[:systemBlocks], image2[:systemBlocks])
+	assert.NotEqual(t, image1[len(image1)-1], image2[len(image2)-1], "different images should produce different blocks")
+}
+
+func TestHashChatPromptResponses(t *testing.T) {
+	responsesRequest := func(items ...types.ResponsesInputItem) *types.LLMRequest {
+		return &types.LLMRequest{
+			TargetModel: "test-model",
+			Data: &types.LLMRequestData{
+				Responses: &types.ResponsesRequest{
+					Instructions: "You are a helpful assistant",
+					Input:        types.ResponsesInput{Items: items},
+				},
+			},
+		}
+	}
+	user := types.ResponsesInputItem{Type: types.MessageInputItem, Role: "user", Content: types.Content{Raw: "What's the weather?"}}
+	call := types.ResponsesInputItem{Type: types.FunctionCallInputItem, Name: "get_weather", Arguments: `{"city":"Paris"}`}
+	output := types.ResponsesInputItem{Type: types.FunctionCallOutputInputItem, Output: "sunny"}
+
+	first := hashChatPrompt(responsesRequest(user), 8, DefaultMaxPrefixBlocks)
+	second := hashChatPrompt(responsesRequest(user, call, output), 8, DefaultMaxPrefixBlocks)
+	assert.NotEmpty(t, first)
+	assert.Greater(t, len(second), len(first))
+	assert.Equal(t, first, second[:len(first)])
+
+	// Raw hashing of responses requests covers the instructions and the input.
+	raw := hashPrompt(context.Background(), responsesRequest(user, call, output), RawHashingMode, 8, DefaultMaxPrefixBlocks)
+	assert.NotEmpty(t, raw)
+}
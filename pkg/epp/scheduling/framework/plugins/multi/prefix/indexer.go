@@ -18,97 +18,251 @@ package prefix
 
 import (
 	"context"
+	"slices"
+	"sort"
 	"sync"
 	"time"
 
-	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/hashicorp/golang-lru/v2/simplelru"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/metrics"
 	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/logging"
 )
 
+// numShards is the number of shards of the hash to pods lookup map. Must be a power of two.
+const numShards = 64
+
 // An indexer maintains an LRU cache of prompt prefix hashes and the server(s) that might have that
 // prefix cached.
+//
+// The lookup map is sharded by hash, so that concurrent requests rarely contend on the same lock, and
+// every pod has its own LRU cache with its own lock. Adds to the same pod are serialized by the pod
+// lock, which is held while the shards are updated, so that the lookup map never has entries that
+// were evicted from the pod LRU. The lock order is pod lock, then shard lock.
 type indexer struct {
+	shards [numShards]indexerShard
+
 	mu         sync.RWMutex
-	hashToPods map[BlockHash]podSet                         // the lookup data structure to find pods that have the BlockHash cached
-	podToLRU   map[ServerID]*lru.Cache[BlockHash, struct{}] // key is pod namespacedName, value is an LRU cache
+	podToLRU   map[ServerID]*podLRU // key is pod namespacedName, value is an LRU cache
 	maxLRUSize int
 }
 
+// indexerShard holds the pods that have a BlockHash cached, for a subset of the hashes. Most hashes
+// are cached by one or a few pods, so the pods are kept in a slice rather than a set.
+type indexerShard struct {
+	mu         sync.RWMutex
+	hashToPods map[BlockHash][]*podLRU
+}
+
+// podLRU is the LRU cache of the hashes of a single pod.
+type podLRU struct {
+	server ServerID
+
+	mu    sync.Mutex
+	cache *simplelru.LRU[BlockHash, struct{}]
+	// evicted collects the hashes evicted by the current Add, to remove them from the shards in a batch.
+	evicted []BlockHash
+}
+
 // newIndexer initializes an indexer with size limits and starts cache size reporting.
 func newIndexer(maxLRUSize int) *indexer {
 	ix := &indexer{
-		hashToPods: make(map[BlockHash]podSet),
-		podToLRU:   make(map[ServerID]*lru.Cache[BlockHash, struct{}]),
+		podToLRU:   make(map[ServerID]*podLRU),
 		maxLRUSize: maxLRUSize,
 	}
+	for i := range ix.shards {
+		ix.shards[i].hashToPods = make(map[BlockHash][]*podLRU)
+	}
 
 	go ix.ReportLRUSize(time.Second)
 	return ix
 }
 
-// Add adds a list of prefix hashes to the cache, tied to the server.
-func (i *indexer) Add(hashes []BlockHash, pod ServerID) {
-	i.mu.Lock()
-	// Check if the LRU pod exist
+func shardIndex(hash BlockHash) int {
+	return int(uint64(hash) & (numShards - 1))
+}
+
+func (i *indexer) shard(hash BlockHash) *indexerShard {
+	return &i.shards[shardIndex(hash)]
+}
+
+// getOrCreatePodLRU returns the LRU cache of the given pod, creating it if needed.
+func (i *indexer) getOrCreatePodLRU(pod ServerID) *podLRU {
+	i.mu.RLock()
 	lruForPod, exists := i.podToLRU[pod]
-	if !exists {
-		newLRU, _ := lru.NewWithEvict[BlockHash, struct{}](i.maxLRUSize, i.makeEvictionFn(pod))
-		i.podToLRU[pod] = newLRU
-		lruForPod = newLRU
+	i.mu.RUnlock()
+	if exists {
+		return lruForPod
 	}
 
-	i.mu.Unlock()
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if lruForPod, exists = i.podToLRU[pod]; !exists {
+		lruForPod = &podLRU{server: pod}
+		lruForPod.cache, _ = simplelru.NewLRU(i.maxLRUSize, func(hash BlockHash, _ struct{}) {
+			lruForPod.evicted = append(lruForPod.evicted, hash)
+		})
+		i.podToLRU[pod] = lruForPod
+	}
+	return lruForPod
+}
+
+// Add adds the prefix hashes of a prompt to the cache, tied to the server.
+// The hashes are added from the last to the first, so that the first blocks of a prompt, which are
+// shared by every prompt with the same prefix, are the most recently used and evicted last. As with
+// the prefix cache of the model servers, the blocks cached for a server are then always a prefix of
+// the prompts it served.
+func (i *indexer) Add(hashes []BlockHash, pod ServerID) {
+	if len(hashes) == 0 {
+		return
+	}
+	lruForPod := i.getOrCreatePodLRU(pod)
+
+	lruForPod.mu.Lock()
+	defer lruForPod.mu.Unlock()
 
 	// Add to LRU (may evict)
-	for _, hash := range hashes {
-		lruForPod.Add(hash, struct{}{})
+	for _, hash := range slices.Backward(hashes) {
+		lruForPod.cache.Add(hash, struct{}{})
 	}
 
-	// Update hashToPods once under lock
-	i.mu.Lock()
-	for _, hash := range hashes {
-		pods := i.hashToPods[hash]
-		if pods == nil {
-			pods = make(podSet)
+	// Update the shards in a batch, taking every shard lock once. A hash is kept in the shards if it is
+	// in the LRU after this call, which also covers hashes evicted by this call and re-added (or the
+	// other way around). An added hash can only be evicted by the same call if more hashes than the
+	// LRU capacity are added.
+	mayEvictAdded := len(hashes) > i.maxLRUSize
+	updates := groupByShard(hashes, lruForPod.evicted)
+	lruForPod.evicted = lruForPod.evicted[:0]
+	var shard *indexerShard
+	for _, update := range updates {
+		hash := update.hash
+		if next := i.shard(hash); next != shard {
+			if shard != nil {
+				shard.mu.Unlock()
+			}
+			shard = next
+			shard.mu.Lock()
 		}
-		pods[pod] = struct{}{}
-		i.hashToPods[hash] = pods
+		pods := shard.hashToPods[hash]
+		index := slices.Index(pods, lruForPod)
+		if (!update.evicted && !mayEvictAdded) || lruForPod.cache.Contains(hash) {
+			if index < 0 {
+				shard.hashToPods[hash] = append(pods, lruForPod)
+			}
+		} else if index >= 0 {
+			if len(pods) == 1 {
+				delete(shard.hashToPods, hash)
+			} else {
+				shard.hashToPods[hash] = slices.Delete(pods, index, index+1)
+			}
+		}
+	}
+	if shard != nil {
+		shard.mu.Unlock()
 	}
+}
 
-	i.mu.Unlock()
+// shardUpdate is an added or evicted hash to update in the shards.
+type shardUpdate struct {
+	hash    BlockHash
+	evicted bool
+}
+
+// groupByShard returns the given hashes ordered by their shard, using a counting sort. Within a shard,
+// added hashes are ordered before evicted hashes.
+func groupByShard(added []BlockHash, evicted []BlockHash) []shardUpdate {
+	var offsets [numShards + 1]int
+	for _, hash := range added {
+		offsets[shardIndex(hash)+1]++
+	}
+	for _, hash := range evicted {
+		offsets[shardIndex(hash)+1]++
+	}
+	for s := 1; s <= numShards; s++ {
+		offsets[s] += offsets[s-1]
+	}
+	res := make([]shardUpdate, len(added)+len(evicted))
+	for _, hash := range added {
+		s := shardIndex(hash)
+		res[offsets[s]] = shardUpdate{hash: hash}
+		offsets[s]++
+	}
+	for _, hash := range evicted {
+		s := shardIndex(hash)
+		res[offsets[s]] = shardUpdate{hash: hash, evicted: true}
+		offsets[s]++
+	}
+	return res
 }
 
 // Get returns a set of servers that have the given prefix hash cached.
 func (i *indexer) Get(hash BlockHash) podSet {
-	i.mu.RLock()
-	defer i.mu.RUnlock()
+	shard := i.shard(hash)
+	shard.mu.RLock()
+	defer shard.mu.RUnlock()
 
-	pods := i.hashToPods[hash]
+	pods := shard.hashToPods[hash]
 	res := make(podSet, len(pods))
-	for pod := range pods {
-		// Deep copy to avoid race condition.
-		res[pod] = struct{}{}
+	for _, pod := range pods {
+		res[pod.server] = struct{}{}
 	}
 
 	return res
 }
 
-// makeEvictionFn returns a per-pod LRU eviction callback that removes the pod from hashToPods on eviction.
-func (i *indexer) makeEvictionFn(pod ServerID) func(BlockHash, struct{}) {
-	return func(hash BlockHash, _ struct{}) {
-		i.mu.Lock()
-		defer i.mu.Unlock()
-		// Remove the pod from the hash→pods map
-		if podSet, ok := i.hashToPods[hash]; ok {
-			delete(podSet, pod)
-			if len(podSet) == 0 {
-				delete(i.hashToPods, hash)
-			}
-		}
+// hasAny returns whether any server has the given prefix hash cached, without allocating.
+func (i *indexer) hasAny(hash BlockHash) bool {
+	shard := i.shard(hash)
+	shard.mu.RLock()
+	defer shard.mu.RUnlock()
+	return len(shard.hashToPods[hash]) > 0
+}
+
+// contains returns whether the given pod has the given prefix hash cached, without allocating.
+func (i *indexer) contains(hash BlockHash, pod *podLRU) bool {
+	shard := i.shard(hash)
+	shard.mu.RLock()
+	defer shard.mu.RUnlock()
+	return slices.Contains(shard.hashToPods[hash], pod)
+}
+
+// MatchLongestPrefix returns the number of leading hashes every server has cached, for the servers
+// that have at least the first hash cached. Blocks cached after a missing block are not counted, since
+// a model server can only reuse the cached blocks of the prefix of a prompt.
+// Since a block hash depends on all preceding blocks, and Add makes sure that the first blocks of a
+// prompt are evicted last, a server that has block i cached has blocks 0..i cached as well, so the
+// longest prefix is found with a binary search.
+func (i *indexer) MatchLongestPrefix(hashes []BlockHash) map[ServerID]int {
+	longest := sort.Search(len(hashes), func(n int) bool { return !i.hasAny(hashes[n]) })
+	if longest == 0 {
+		return map[ServerID]int{}
 	}
+
+	shard := i.shard(hashes[0])
+	shard.mu.RLock()
+	candidates := slices.Clone(shard.hashToPods[hashes[0]])
+	shard.mu.RUnlock()
+
+	res := make(map[ServerID]int, len(candidates))
+	for _, pod := range candidates {
+		res[pod.server] = sort.Search(longest, func(n int) bool { return !i.contains(hashes[n], pod) })
+	}
+	return res
+}
+
+// Len returns the number of hashes cached for the pod.
+func (l *podLRU) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.cache.Len()
+}
+
+// Keys returns the hashes cached for the pod, from the least recently used to the most recently used.
+func (l *podLRU) Keys() []BlockHash {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.cache.Keys()
 }
 
 // ReportLRUSize starts a goroutine that periodically reports the LRU cache size metric.
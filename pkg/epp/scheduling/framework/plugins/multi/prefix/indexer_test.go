@@ -16,6 +16,9 @@ limitations under the License.
 package prefix
 
 import (
+	"fmt"
+	"math/rand"
+	"sync/atomic"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -45,3 +48,138 @@ func TestIndexer_AddAndGet(t *testing.T) {
 	servers = i.Get(BlockHash(4))
 	assert.Empty(t, servers, "Cache should not contain non-existent hash")
 }
+
+func TestIndexer_EvictionWithinAdd(t *testing.T) {
+	i := newIndexer(2)
+	server := ServerID{Namespace: "default", Name: "server1"}
+
+	// Hashes evicted by the same Add are removed from the lookup map. The last hashes of a prompt are
+	// evicted first.
+	i.Add([]BlockHash{1, 2, 3}, server)
+	assert.Empty(t, i.Get(3), "evicted hash should be removed")
+	assert.Contains(t, i.Get(1), server)
+	assert.Contains(t, i.Get(2), server)
+
+	// A hash evicted and re-added by the same Add is kept.
+	i.Add([]BlockHash{2, 4}, server)
+	assert.Empty(t, i.Get(1), "evicted hash should be removed")
+	assert.Contains(t, i.Get(2), server)
+	assert.Contains(t, i.Get(4), server)
+}
+
+func TestIndexer_MatchLongestPrefix(t *testing.T) {
+	i := newIndexer(100)
+	server1 := ServerID{Namespace: "default", Name: "server1"}
+	server2 := ServerID{Namespace: "default", Name: "server2"}
+	server3 := ServerID{Namespace: "default", Name: "server3"}
+
+	i.Add([]BlockHash{1, 2, 3, 4, 5}, server1)
+	i.Add([]BlockHash{1, 2}, server2)
+	i.Add([]BlockHash{6, 7}, server3)
+
+	assert.Equal(t, map[ServerID]int{server1: 5, server2: 2}, i.MatchLongestPrefix([]BlockHash{1, 2, 3, 4, 5, 8, 9}))
+	assert.Equal(t, map[ServerID]int{server1: 3, server2: 2}, i.MatchLongestPrefix([]BlockHash{1, 2, 3}))
+	assert.Empty(t, i.MatchLongestPrefix([]BlockHash{8, 1, 2}))
+	assert.Empty(t, i.MatchLongestPrefix(nil))
+}
+
+func TestIndexer_MatchLongestPrefixWithEviction(t *testing.T) {
+	i := newIndexer(6)
+	server1 := ServerID{Namespace: "default", Name: "server1"}
+	server2 := ServerID{Namespace: "default", Name: "server2"}
+
+	// The prompt is longer than the LRU capacity, so its last blocks are evicted.
+	i.Add([]BlockHash{1, 2, 3, 4, 5, 6, 7, 8}, server1)
+	assert.Equal(t, map[ServerID]int{server1: 6}, i.MatchLongestPrefix([]BlockHash{1, 2, 3, 4, 5, 6, 7, 8}))
+
+	// Prompts sharing the first blocks evict the blocks specific to older prompts first.
+	i.Add([]BlockHash{1, 2, 3, 4, 5, 6}, server2)
+	i.Add([]BlockHash{1, 2, 3, 9, 10}, server2)
+	i.Add([]BlockHash{1, 2, 11, 12}, server2)
+	assert.Equal(t, map[ServerID]int{server1: 6, server2: 3}, i.MatchLongestPrefix([]BlockHash{1, 2, 3, 4, 5, 6}))
+	assert.Equal(t, map[ServerID]int{server1: 3, server2: 4}, i.MatchLongestPrefix([]BlockHash{1, 2, 3, 9, 10}))
+	assert.Equal(t, map[ServerID]int{server1: 2, server2: 4}, i.MatchLongestPrefix([]BlockHash{1, 2, 11, 12}))
+}
+
+// benchmarkPrompts generates prompts of 256 blocks, where prompts share one of a few prefixes of up to
+// 128 blocks, as with common system prompts.
+func benchmarkPrompts(numPrompts int) [][]BlockHash {
+	r := rand.New(rand.NewSource(0))
+	prefixes := make([][]BlockHash, 16)
+	for p := range prefixes {
+		prefixes[p] = make([]BlockHash, 1+r.Intn(128))
+		for b := range prefixes[p] {
+			prefixes[p][b] = BlockHash(r.Uint64())
+		}
+	}
+	prompts := make([][]BlockHash, numPrompts)
+	for p := range prompts {
+		prompt := make([]BlockHash, 0, 256)
+		prompt = append(prompt, prefixes[r.Intn(len(prefixes))]...)
+		for len(prompt) < 256 {
+			prompt = append(prompt, BlockHash(r.Uint64()))
+		}
+		prompts[p] = prompt
+	}
+	return prompts
+}
+
+// BenchmarkIndexer simulates concurrent scheduling of requests with 256-block prompts over 100 pods,
+// where each request looks up the longest prefix and adds its blocks to the selected pod. The req/s
+// metric is the scheduling throughput of the indexer. With "repeated", prompts are drawn from a pool
+// and routed to the same pod, as with prefix affinity and multi-turn conversations. With "unique",
+// every request adds mostly new blocks, which is the worst case for the indexer.
+func BenchmarkIndexer(b *testing.B) {
+	for _, workload := range []struct {
+		name       string
+		numPrompts int
+		pod        func(n int) int
+	}{
+		{name: "repeated", numPrompts: 4096, pod: func(n int) int { return (n % 4096) % 100 }},
+		{name: "unique", numPrompts: 1 << 16, pod: func(n int) int { return n % 100 }},
+	} {
+		for _, parallelism := range []int{1, 16} {
+			b.Run(fmt.Sprintf("%s/parallelism=%d", workload.name, parallelism), func(b *testing.B) {
+				i := newIndexer(DefaultLRUCapacityPerServer)
+				prompts := benchmarkPrompts(workload.numPrompts)
+				servers := make([]ServerID, 100)
+				for s := range servers {
+					servers[s] = ServerID{Namespace: "default", Name: fmt.Sprintf("server%d", s)}
+				}
+				var next atomic.Int64
+
+				b.SetParallelism(parallelism)
+				b.ReportAllocs()
+				b.ResetTimer()
+				b.RunParallel(func(pb *testing.PB) {
+					for pb.Next() {
+						n := int(next.Add(1))
+						prompt := prompts[n%len(prompts)]
+						i.MatchLongestPrefix(prompt)
+						i.Add(prompt, servers[workload.pod(n)])
+					}
+				})
+				b.ReportMetric(float64(b.N)/b.Elapsed().Seconds(), "req/s")
+			})
+		}
+	}
+}
+
+// BenchmarkIndexer_MatchLongestPrefix measures the lookup of a 256-block prompt with a cached prefix.
+func BenchmarkIndexer_MatchLongestPrefix(b *testing.B) {
+	i := newIndexer(DefaultLRUCapacityPerServer)
+	prompts := benchmarkPrompts(1024)
+	for p, prompt := range prompts {
+		i.Add(prompt[:128], ServerID{Namespace: "default", Name: fmt.Sprintf("server%d", p%100)})
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		n := 0
+		for pb.Next() {
+			i.MatchLongestPrefix(prompts[n%len(prompts)])
+			n++
+		}
+	})
+}
@@ -20,11 +20,18 @@ import (
 	"context"
 	"encoding/binary"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
+	"os"
+	"time"
 
 	"github.com/cespare/xxhash/v2"
+	"github.com/google/uuid"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8stypes "k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/metrics"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/plugins"
@@ -52,6 +59,9 @@ const (
 	// token is about 128KB in size, so we can cache 500K tokens. Using the default block size of 16
 	// in vLLM, we will have 250K / 16 = 31.25K blocks.
 	DefaultLRUCapacityPerServer = 31250
+	// DefaultSnapshotInterval is the interval in which the indexer is persisted to the snapshot file,
+	// if a snapshot path is configured.
+	DefaultSnapshotInterval = time.Minute
 
 	PrefixCachePluginType = "prefix-cache-scorer"
 )
@@ -71,6 +81,29 @@ type Config struct {
 	MaxPrefixBlocksToMatch int `json:"maxPrefixBlocksToMatch"`
 	// Max capacity size of the LRU indexer in number of entries per server (pod).
 	LRUCapacityPerServer int `json:"lruCapacityPerServer"`
+	// SnapshotPath is the path of a local file the indexer is periodically persisted to, and restored
+	// from at startup. Snapshotting is disabled if empty.
+	SnapshotPath string `json:"snapshotPath,omitempty"`
+	// SnapshotInterval is the interval in which the indexer is persisted to SnapshotPath. The indexer is
+	// also persisted at shutdown.
+	SnapshotInterval metav1.Duration `json:"snapshotInterval,omitempty"`
+	// ReplicationListenAddress is the address (e.g. ":9004") on which the indexer replication stream is
+	// served to standby replicas. Serving the stream is disabled if empty.
+	ReplicationListenAddress string `json:"replicationListenAddress,omitempty"`
+	// ReplicationSourceURL is the URL of a replication stream to follow, typically pointing to the
+	// ReplicationPath of the leader through the EPP Service. Following is disabled if empty.
+	ReplicationSourceURL string `json:"replicationSourceURL,omitempty"`
+	// HashingMode selects how the prompt is turned into blocks, either RawHashingMode (default) or
+	// ChatTemplateHashingMode.
+	HashingMode string `json:"hashingMode,omitempty"`
+	// LoadBoundFactor bounds the load of a pod prefix affinity is rewarded for, as a multiple of the
+	// average load of the candidate pods. Pods at or above the bound are scored as if they had no prefix match,
+	// so that a hot prefix spreads to more pods instead of queuing on one. Disabled if zero.
+	LoadBoundFactor float64 `json:"loadBoundFactor,omitempty"`
+	// IgnorePoolingRequests excludes embeddings and rerank requests from prefix matching, for model
+	// servers that don't cache the prefix of pooling requests. Ignored requests get a zero score on all
+	// pods and are not added to the indexer.
+	IgnorePoolingRequests bool `json:"ignorePoolingRequests,omitempty"`
 }
 
 type Plugin struct {
@@ -78,6 +111,8 @@ type Plugin struct {
 	config      Config
 	pluginState *plugins.PluginState
 	indexer     Indexer
+	replicaID   string
+	replication *replicationServer
 }
 
 // podSet holds an pods servers that may have a specific prefix hash.
@@ -86,6 +121,12 @@ type podSet map[ServerID]struct{}
 type Indexer interface {
 	Get(hash BlockHash) podSet
 	Add(hashes []BlockHash, server ServerID)
+	// MatchLongestPrefix returns the number of leading hashes every server has cached.
+	MatchLongestPrefix(hashes []BlockHash) map[ServerID]int
+	// Snapshot returns a serializable copy of the indexer contents.
+	Snapshot() *IndexerSnapshot
+	// Restore adds the contents of the given snapshot to the indexer.
+	Restore(snapshot *IndexerSnapshot)
 }
 
 // BlockHash is a hash of the block of request body.
@@ -126,6 +167,7 @@ func (s *SchedulingContextState) Clone() plugins.StateData {
 var (
 	_ framework.Scorer          = &Plugin{}
 	_ requestcontrol.PreRequest = &Plugin{}
+	_ manager.Runnable          = &Plugin{}
 )
 
 // PrefixCachePluginFactory defines the factory function for Prefix plugin.
@@ -141,6 +183,16 @@ func PrefixCachePluginFactory(name string, rawParameters json.RawMessage, handle
 			return nil, fmt.Errorf("failed to parse the parameters of the %s plugin. Error: %s", PrefixCachePluginType, err)
 		}
 	}
+	switch parameters.HashingMode {
+	case "", RawHashingMode, ChatTemplateHashingMode:
+	default:
+		return nil, fmt.Errorf("invalid hashingMode %q for the %s plugin, must be one of %q, %q", parameters.HashingMode,
+			PrefixCachePluginType, RawHashingMode, ChatTemplateHashingMode)
+	}
+	if parameters.LoadBoundFactor != 0 && parameters.LoadBoundFactor < 1 {
+		return nil, fmt.Errorf("invalid loadBoundFactor %v for the %s plugin, must be at least 1", parameters.LoadBoundFactor,
+			PrefixCachePluginType)
+	}
 
 	return New(handle.Context(), parameters).WithName(name), nil
 }
@@ -156,12 +208,93 @@ func New(ctx context.Context, config Config) *Plugin {
 		)
 	}
 
-	return &Plugin{
+	if config.SnapshotInterval.Duration <= 0 {
+		config.SnapshotInterval.Duration = DefaultSnapshotInterval
+	}
+
+	p := &Plugin{
 		typedName:   plugins.TypedName{Type: PrefixCachePluginType, Name: PrefixCachePluginType},
 		config:      config,
 		pluginState: plugins.NewPluginState(ctx),
 		indexer:     newIndexer(capacity),
+		replicaID:   uuid.NewString(),
+	}
+	if config.ReplicationListenAddress != "" {
+		p.replication = newReplicationServer(p.replicaID, p.indexer)
+	}
+	p.restoreSnapshot(ctx)
+	return p
+}
+
+// Start runs the background work of the plugin (snapshotting and replication) until the given
+// context is done. It implements manager.Runnable, so that the final snapshot is written as part of
+// a graceful shutdown.
+func (p *Plugin) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithValues("plugin", p.typedName)
+	ctx = log.IntoContext(ctx, logger)
+
+	errCh := make(chan error, 1)
+	if p.replication != nil {
+		go func() {
+			errCh <- p.replication.run(ctx, p.config.ReplicationListenAddress)
+		}()
+		logger.V(logutil.DEFAULT).Info("Serving prefix index replication stream", "address", p.config.ReplicationListenAddress)
+	}
+	if p.config.ReplicationSourceURL != "" {
+		go follow(ctx, p.config.ReplicationSourceURL, p.replicaID, p.indexer)
+		logger.V(logutil.DEFAULT).Info("Following prefix index replication stream", "source", p.config.ReplicationSourceURL)
+	}
+
+	var snapshotTicker <-chan time.Time
+	if p.config.SnapshotPath != "" {
+		ticker := time.NewTicker(p.config.SnapshotInterval.Duration)
+		defer ticker.Stop()
+		snapshotTicker = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			p.saveSnapshot(ctx)
+			return nil
+		case err := <-errCh:
+			p.saveSnapshot(ctx)
+			return err
+		case <-snapshotTicker:
+			p.saveSnapshot(ctx)
+		}
+	}
+}
+
+// restoreSnapshot restores the indexer from the configured snapshot file, if one exists.
+func (p *Plugin) restoreSnapshot(ctx context.Context) {
+	if p.config.SnapshotPath == "" {
+		return
+	}
+	logger := log.FromContext(ctx)
+	snapshot, err := readSnapshotFile(p.config.SnapshotPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			logger.V(logutil.DEFAULT).Info("No prefix index snapshot found, starting with an empty index", "path", p.config.SnapshotPath)
+		} else {
+			logger.V(logutil.DEFAULT).Error(err, "Failed to restore prefix index snapshot, starting with an empty index", "path", p.config.SnapshotPath)
+		}
+		return
+	}
+	p.indexer.Restore(snapshot)
+	logger.V(logutil.DEFAULT).Info("Restored prefix index snapshot", "path", p.config.SnapshotPath, "pods", len(snapshot.Pods))
+}
+
+// saveSnapshot persists the indexer to the configured snapshot file.
+func (p *Plugin) saveSnapshot(ctx context.Context) {
+	if p.config.SnapshotPath == "" {
+		return
+	}
+	if err := writeSnapshotFile(p.config.SnapshotPath, p.indexer.Snapshot()); err != nil {
+		log.FromContext(ctx).V(logutil.DEFAULT).Error(err, "Failed to save prefix index snapshot", "path", p.config.SnapshotPath)
+		return
 	}
+	log.FromContext(ctx).V(logutil.TRACE).Info("Saved prefix index snapshot", "path", p.config.SnapshotPath)
 }
 
 // TypedName returns the type and name tuple of this plugin instance.
@@ -179,7 +312,10 @@ func (p *Plugin) WithName(name string) *Plugin {
 func (p *Plugin) Score(ctx context.Context, _ *types.CycleState, request *types.LLMRequest, pods []types.Pod) map[types.Pod]float64 {
 	loggerTrace := log.FromContext(ctx).V(logutil.TRACE)
 	// pre score step, hashing prompt and find longest prefix match.
-	hashes := hashPrompt(ctx, request, p.config.HashBlockSize, p.config.MaxPrefixBlocksToMatch)
+	var hashes []BlockHash
+	if !p.ignored(request) {
+		hashes = hashPrompt(ctx, request, p.config.HashingMode, p.config.HashBlockSize, p.config.MaxPrefixBlocksToMatch)
+	}
 	state := &SchedulingContextState{
 		PrefixHashes:       hashes,
 		PrefixCacheServers: p.matchLongestPrefix(ctx, hashes),
@@ -190,12 +326,21 @@ func (p *Plugin) Score(ctx context.Context, _ *types.CycleState, request *types.
 	// calculate the scores of pods
 	scores := make(map[types.Pod]float64, len(pods))
 
+	overloaded := p.overloadedServers(pods)
+	if len(overloaded) > 0 {
+		loggerTrace.Info(fmt.Sprintf("overloaded servers: %+v", overloaded))
+	}
+
 	total := len(state.PrefixHashes)
 	podScoreFunc := func(pod types.Pod) float64 {
 		if total == 0 {
 			return 0
 		}
-		matchLen := state.PrefixCacheServers[ServerID(pod.GetPod().NamespacedName)]
+		server := ServerID(pod.GetPod().NamespacedName)
+		if _, ok := overloaded[server]; ok {
+			return 0
+		}
+		matchLen := state.PrefixCacheServers[server]
 		return float64(matchLen) / float64(total)
 	}
 
@@ -205,6 +350,33 @@ func (p *Plugin) Score(ctx context.Context, _ *types.CycleState, request *types.
 	return scores
 }
 
+// overloadedServers returns the pods whose load, the number of running and waiting requests, is at or
+// above the bound set by LoadBoundFactor, i.e. that have no room for the current request. Following
+// consistent hashing with bounded loads, the bound is ceil(LoadBoundFactor * (total load + 1) / number of
+// pods), where one is added for the current request.
+func (p *Plugin) overloadedServers(pods []types.Pod) podSet {
+	if p.config.LoadBoundFactor <= 0 || len(pods) == 0 {
+		return nil
+	}
+	loads := make([]int, len(pods))
+	totalLoad := 0
+	for i, pod := range pods {
+		if m := pod.GetMetrics(); m != nil {
+			loads[i] = m.RunningQueueSize + m.WaitingQueueSize
+		}
+		totalLoad += loads[i]
+	}
+	bound := int(math.Ceil(p.config.LoadBoundFactor * float64(totalLoad+1) / float64(len(pods))))
+
+	overloaded := podSet{}
+	for i, pod := range pods {
+		if loads[i] >= bound {
+			overloaded[ServerID(pod.GetPod().NamespacedName)] = struct{}{}
+		}
+	}
+	return overloaded
+}
+
 // PreRequest records in the plugin cache the result of the scheduling selection.
 func (p *Plugin) PreRequest(ctx context.Context, request *types.LLMRequest, schedulingResult *types.SchedulingResult, _ int) {
 	primaryProfileResult := schedulingResult.ProfileResults[schedulingResult.PrimaryProfileName]
@@ -216,45 +388,45 @@ func (p *Plugin) PreRequest(ctx context.Context, request *types.LLMRequest, sche
 		log.FromContext(ctx).Error(err, "failed to read prefix plugin state", "requestID", request.RequestId)
 		return
 	}
+	if p.ignored(request) {
+		return
+	}
 
 	p.indexer.Add(state.PrefixHashes, ServerID(targetPod.NamespacedName))
+	if p.replication != nil {
+		p.replication.publish(state.PrefixHashes, ServerID(targetPod.NamespacedName))
+	}
 
 	total := len(state.PrefixHashes)
 	matchLen := state.PrefixCacheServers[ServerID(targetPod.NamespacedName)]
 	metrics.RecordPrefixCacheMatch(matchLen*p.config.HashBlockSize, total*p.config.HashBlockSize)
 }
 
+// ignored returns true if the request is excluded from prefix matching.
+func (p *Plugin) ignored(request *types.LLMRequest) bool {
+	return p.config.IgnorePoolingRequests && request.Data.IsPooling()
+}
+
 // matchLongestPrefix returns a map of servers and length of prefix that each server caches.
 func (p *Plugin) matchLongestPrefix(ctx context.Context, hashes []BlockHash) map[ServerID]int {
-	loggerTrace := log.FromContext(ctx).V(logutil.TRACE)
-	res := make(map[ServerID]int)
-	// Use a greedy strategy to search from the longest prefix.
-	// NOTE: It's possible to further optimize this with a binary search.
-	for i := 0; i < len(hashes); i++ {
-		hash := hashes[i]
-		cachedServers := p.indexer.Get(hash)
-		if len(cachedServers) == 0 {
-			break
-		} else {
-			loggerTrace.Info("Found cached servers", "cachedServers", cachedServers, "total # blocks", len(hashes), "longest prefix", i)
-			for server := range cachedServers {
-				// Update servers with their longest prefix match.
-				res[server]++
-			}
-		}
-	}
+	res := p.indexer.MatchLongestPrefix(hashes)
+	log.FromContext(ctx).V(logutil.TRACE).Info("Found cached servers", "cachedServers", res, "total # blocks", len(hashes))
 	return res
 }
 
 // hashPrompt divides the prompt into blocks and calculate the prefix cache for each block.
 // hash(0) is the hash of the model name, since different models generally don't share prefix cache.
 // For block i, hash(i) = hash(block i content, hash(i-1)).
-func hashPrompt(ctx context.Context, request *types.LLMRequest, cacheBlockSize int, maxPrefixBlocks int) []BlockHash {
+// In ChatTemplateHashingMode, chat-completions and responses requests are hashed by hashChatPrompt instead.
+func hashPrompt(ctx context.Context, request *types.LLMRequest, hashingMode string, cacheBlockSize int, maxPrefixBlocks int) []BlockHash {
 	loggerDebug := log.FromContext(ctx).V(logutil.DEBUG)
 	if request == nil || request.Data == nil {
 		loggerDebug.Info("Request or request data is nil, skipping hashing")
 		return nil
 	}
+	if hashingMode == ChatTemplateHashingMode && (request.Data.ChatCompletions != nil || request.Data.Responses != nil) {
+		return hashChatPrompt(request, cacheBlockSize, maxPrefixBlocks)
+	}
 
 	userInput, err := getUserInputBytes(request)
 	if err != nil {
@@ -299,6 +471,24 @@ func getUserInputBytes(request *types.LLMRequest) ([]byte, error) {
 		return []byte(request.Data.Completions.Prompt), nil
 	}
 
+	if responses := request.Data.Responses; responses != nil {
+		// the instructions come before the input in the prompt, the other fields (e.g. user) are not
+		// part of the prompt.
+		return json.Marshal(struct {
+			Instructions string               `json:"instructions,omitempty"`
+			Input        types.ResponsesInput `json:"input"`
+		}{Instructions: responses.Instructions, Input: responses.Input})
+	}
+
+	if embeddings := request.Data.Embeddings; embeddings != nil {
+		return json.Marshal(embeddings.Input)
+	}
+
+	if rerank := request.Data.Rerank; rerank != nil {
+		// the query comes before the documents in the prompt of every document.
+		return []byte(rerank.Text()), nil
+	}
+
 	// must be chat-completions request at this point, return bytes of entire messages
 	return json.Marshal(request.Data.ChatCompletions.Messages)
 }
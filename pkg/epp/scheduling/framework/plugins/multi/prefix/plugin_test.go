@@ -29,6 +29,7 @@ import (
 	k8stypes "k8s.io/apimachinery/pkg/types"
 
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend"
+	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/plugins"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
 )
@@ -194,6 +195,81 @@ func TestPrefixPluginCompletion(t *testing.T) {
 	plugin.PreRequest(context.Background(), req5, schedulingResult, 0)
 }
 
+func TestPrefixPluginLoadBound(t *testing.T) {
+	config := Config{
+		HashBlockSize:          4,
+		MaxPrefixBlocksToMatch: DefaultMaxPrefixBlocks,
+		LRUCapacityPerServer:   DefaultLRUCapacityPerServer,
+		LoadBoundFactor:        1.25,
+	}
+	plugin := New(context.Background(), config)
+
+	pod1 := &types.PodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: "pod1"}},
+		MetricsState: &backendmetrics.MetricsState{}}
+	pod2 := &types.PodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: "pod2"}},
+		MetricsState: &backendmetrics.MetricsState{}}
+	pod3 := &types.PodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: "pod3"}},
+		MetricsState: &backendmetrics.MetricsState{}}
+	pods := []types.Pod{pod1, pod2, pod3}
+
+	newRequest := func() *types.LLMRequest {
+		return &types.LLMRequest{
+			RequestId:   uuid.NewString(),
+			TargetModel: "test-model1",
+			Data: &types.LLMRequestData{
+				Completions: &types.CompletionsRequest{Prompt: "aaaabbbbcccc"},
+			},
+		}
+	}
+	schedule := func(req *types.LLMRequest, pod types.Pod) {
+		schedulingResult := &types.SchedulingResult{
+			PrimaryProfileName: "default",
+			ProfileResults: map[string]*types.ProfileRunResult{
+				"default": {TargetPods: []types.Pod{pod}},
+			},
+		}
+		plugin.PreRequest(context.Background(), req, schedulingResult, 0)
+	}
+
+	// The prefix is cached on pod1, and pod1 is not loaded.
+	req1 := newRequest()
+	plugin.Score(context.Background(), nil, req1, pods)
+	schedule(req1, pod1)
+
+	req2 := newRequest()
+	scores := plugin.Score(context.Background(), nil, req2, pods)
+	assert.Equal(t, float64(1), scores[pod1], "score for pod1")
+
+	// With all pods at a load of 1, the bound is ceil(1.25 * 4 / 3) = 2, so pod1 is below it.
+	pod1.MetricsState.RunningQueueSize = 1
+	pod2.MetricsState.RunningQueueSize = 1
+	pod3.MetricsState.RunningQueueSize = 1
+	scores = plugin.Score(context.Background(), nil, req2, pods)
+	assert.Equal(t, float64(1), scores[pod1], "score for pod1")
+
+	// With only pod1 at a load of 1, the bound is ceil(1.25 * 2 / 3) = 1. pod1 is at the bound, so it has no
+	// room for the request and its affinity is no longer rewarded.
+	pod2.MetricsState.RunningQueueSize = 0
+	pod3.MetricsState.RunningQueueSize = 0
+	scores = plugin.Score(context.Background(), nil, req2, pods)
+	assert.Equal(t, float64(0), scores[pod1], "score for pod1")
+
+	// pod1 is above 1.25 times the average load, so its affinity is no longer rewarded.
+	pod1.MetricsState.RunningQueueSize = 10
+	pod1.MetricsState.WaitingQueueSize = 2
+	pod2.MetricsState.RunningQueueSize = 1
+	scores = plugin.Score(context.Background(), nil, req2, pods)
+	assert.Equal(t, float64(0), scores[pod1], "score for pod1")
+
+	// The hot prefix spreads to pod2, which is then rewarded for it.
+	schedule(req2, pod2)
+	req3 := newRequest()
+	scores = plugin.Score(context.Background(), nil, req3, pods)
+	assert.Equal(t, float64(0), scores[pod1], "score for pod1")
+	assert.Equal(t, float64(1), scores[pod2], "score for pod2")
+	assert.Equal(t, float64(0), scores[pod3], "score for pod3")
+}
+
 func TestPrefixPluginChatCompletions(t *testing.T) {
 	config := Config{
 		HashBlockSize:          4,
@@ -212,8 +288,8 @@ func TestPrefixPluginChatCompletions(t *testing.T) {
 		Data: &types.LLMRequestData{
 			ChatCompletions: &types.ChatCompletionsRequest{
 				Messages: []types.Message{
-					{Role: "user", Content: "hello world"},
-					{Role: "assistant", Content: "hi there"},
+					{Role: "user", Content: types.Content{Raw: "hello world"}},
+					{Role: "assistant", Content: types.Content{Raw: "hi there"}},
 				},
 			},
 		},
@@ -247,8 +323,8 @@ func TestPrefixPluginChatCompletionsGrowth(t *testing.T) {
 		Data: &types.LLMRequestData{
 			ChatCompletions: &types.ChatCompletionsRequest{
 				Messages: []types.Message{
-					{Role: "system", Content: "You are a helpful assistant"},
-					{Role: "user", Content: "Hello, how are you?"},
+					{Role: "system", Content: types.Content{Raw: "You are a helpful assistant"}},
+					{Role: "user", Content: types.Content{Raw: "Hello, how are you?"}},
 				},
 			},
 		},
@@ -279,10 +355,10 @@ func TestPrefixPluginChatCompletionsGrowth(t *testing.T) {
 		Data: &types.LLMRequestData{
 			ChatCompletions: &types.ChatCompletionsRequest{
 				Messages: []types.Message{
-					{Role: "system", Content: "You are a helpful assistant"},
-					{Role: "user", Content: "Hello, how are you?"},
-					{Role: "assistant", Content: "I'm doing well, thank you! How can I help you today?"},
-					{Role: "user", Content: "Can you explain how prefix caching works?"},
+					{Role: "system", Content: types.Content{Raw: "You are a helpful assistant"}},
+					{Role: "user", Content: types.Content{Raw: "Hello, how are you?"}},
+					{Role: "assistant", Content: types.Content{Raw: "I'm doing well, thank you! How can I help you today?"}},
+					{Role: "user", Content: types.Content{Raw: "Can you explain how prefix caching works?"}},
 				},
 			},
 		},
@@ -311,12 +387,12 @@ func TestPrefixPluginChatCompletionsGrowth(t *testing.T) {
 		Data: &types.LLMRequestData{
 			ChatCompletions: &types.ChatCompletionsRequest{
 				Messages: []types.Message{
-					{Role: "system", Content: "You are a helpful assistant"},
-					{Role: "user", Content: "Hello, how are you?"},
-					{Role: "assistant", Content: "I'm doing well, thank you! How can I help you today?"},
-					{Role: "user", Content: "Can you explain how prefix caching works?"},
-					{Role: "assistant", Content: "Prefix caching is a technique where..."},
-					{Role: "user", Content: "That's very helpful, thank you!"},
+					{Role: "system", Content: types.Content{Raw: "You are a helpful assistant"}},
+					{Role: "user", Content: types.Content{Raw: "Hello, how are you?"}},
+					{Role: "assistant", Content: types.Content{Raw: "I'm doing well, thank you! How can I help you today?"}},
+					{Role: "user", Content: types.Content{Raw: "Can you explain how prefix caching works?"}},
+					{Role: "assistant", Content: types.Content{Raw: "Prefix caching is a technique where..."}},
+					{Role: "user", Content: types.Content{Raw: "That's very helpful, thank you!"}},
 				},
 			},
 		},
@@ -435,7 +511,7 @@ func BenchmarkPrefixPluginChatCompletionsStress(b *testing.B) {
 		b.Run(fmt.Sprintf("messages_%d_length_%d", scenario.messageCount, scenario.messageLength), func(b *testing.B) {
 			// Generate messages for this scenario
 			messages := make([]types.Message, scenario.messageCount)
-			messages[0] = types.Message{Role: "system", Content: "You are a helpful assistant."}
+			messages[0] = types.Message{Role: "system", Content: types.Content{Raw: "You are a helpful assistant."}}
 
 			for i := 1; i < scenario.messageCount; i++ {
 				role := "user"
@@ -443,7 +519,7 @@ func BenchmarkPrefixPluginChatCompletionsStress(b *testing.B) {
 					role = "assistant"
 				}
 				content := randomPrompt(scenario.messageLength)
-				messages[i] = types.Message{Role: role, Content: content}
+				messages[i] = types.Message{Role: role, Content: types.Content{Raw: content}}
 			}
 
 			pod := &types.PodMetrics{
@@ -477,3 +553,66 @@ func BenchmarkPrefixPluginChatCompletionsStress(b *testing.B) {
 		})
 	}
 }
+
+func TestPrefixPluginPoolingRequests(t *testing.T) {
+	pod1 := &types.PodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: "pod1"}},
+		MetricsState: &backendmetrics.MetricsState{}}
+	pod2 := &types.PodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: "pod2"}},
+		MetricsState: &backendmetrics.MetricsState{}}
+	pods := []types.Pod{pod1, pod2}
+
+	embeddingsRequest := func() *types.LLMRequest {
+		return &types.LLMRequest{
+			RequestId:   uuid.NewString(),
+			TargetModel: "test-model1",
+			Data: &types.LLMRequestData{
+				Embeddings: &types.EmbeddingsRequest{Input: types.EmbeddingsInput{Texts: []string{"aaaabbbb", "cccc"}}},
+			},
+		}
+	}
+	rerankRequest := func() *types.LLMRequest {
+		return &types.LLMRequest{
+			RequestId:   uuid.NewString(),
+			TargetModel: "test-model1",
+			Data: &types.LLMRequestData{
+				Rerank: &types.RerankRequest{Query: "aaaabbbb", Documents: []types.RerankDocument{"cccc", "dddd"}},
+			},
+		}
+	}
+	schedule := func(plugin *Plugin, req *types.LLMRequest, pod types.Pod) {
+		schedulingResult := &types.SchedulingResult{
+			PrimaryProfileName: "default",
+			ProfileResults: map[string]*types.ProfileRunResult{
+				"default": {TargetPods: []types.Pod{pod}},
+			},
+		}
+		plugin.PreRequest(context.Background(), req, schedulingResult, 0)
+	}
+
+	for _, newRequest := range []func() *types.LLMRequest{embeddingsRequest, rerankRequest} {
+		config := Config{
+			HashBlockSize:          4,
+			MaxPrefixBlocksToMatch: DefaultMaxPrefixBlocks,
+			LRUCapacityPerServer:   DefaultLRUCapacityPerServer,
+		}
+		plugin := New(context.Background(), config)
+
+		// Pooling requests are matched like any other request by default.
+		req1 := newRequest()
+		plugin.Score(context.Background(), nil, req1, pods)
+		schedule(plugin, req1, pod1)
+		scores := plugin.Score(context.Background(), nil, newRequest(), pods)
+		assert.Equal(t, float64(1), scores[pod1], "score for pod1")
+		assert.Equal(t, float64(0), scores[pod2], "score for pod2")
+
+		// Ignored pooling requests are neither matched nor indexed.
+		config.IgnorePoolingRequests = true
+		plugin = New(context.Background(), config)
+		req1 = newRequest()
+		plugin.Score(context.Background(), nil, req1, pods)
+		schedule(plugin, req1, pod1)
+		scores = plugin.Score(context.Background(), nil, newRequest(), pods)
+		assert.Equal(t, float64(0), scores[pod1], "score for pod1")
+		assert.Empty(t, plugin.indexer.MatchLongestPrefix(hashPrompt(context.Background(), req1, RawHashingMode, 4, DefaultMaxPrefixBlocks)))
+	}
+}
@@ -0,0 +1,217 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package prefix
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/logging"
+)
+
+const (
+	// ReplicationPath is the HTTP path on which the replication stream is served.
+	ReplicationPath = "/prefix-index/stream"
+	// replicaIDHeader identifies the follower, so that a replica never follows itself (e.g. when the
+	// replication source is a Service that routes to the current leader, which may be this replica).
+	replicaIDHeader = "X-Prefix-Index-Replica-Id"
+	// subscriberBufferSize is the number of events buffered per follower. A follower that falls behind
+	// by more than that is disconnected, and gets a fresh snapshot when it reconnects.
+	subscriberBufferSize = 4096
+	// followRetryInterval is the time to wait before reconnecting to the replication source.
+	followRetryInterval = 5 * time.Second
+)
+
+// replicationEvent is a single message in the replication stream. The first event of every stream
+// carries a full snapshot, all following events carry a single indexer addition.
+type replicationEvent struct {
+	Snapshot *IndexerSnapshot `json:"snapshot,omitempty"`
+	Server   ServerID         `json:"server,omitempty"`
+	Hashes   []BlockHash      `json:"hashes,omitempty"`
+}
+
+// replicationServer streams the indexer contents and every subsequent addition to followers.
+type replicationServer struct {
+	replicaID string
+	indexer   Indexer
+
+	mu          sync.Mutex
+	subscribers map[chan *replicationEvent]struct{}
+}
+
+func newReplicationServer(replicaID string, indexer Indexer) *replicationServer {
+	return &replicationServer{
+		replicaID:   replicaID,
+		indexer:     indexer,
+		subscribers: make(map[chan *replicationEvent]struct{}),
+	}
+}
+
+// publish sends an indexer addition to all connected followers.
+func (s *replicationServer) publish(hashes []BlockHash, server ServerID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.subscribers) == 0 {
+		return
+	}
+	event := &replicationEvent{Server: server, Hashes: hashes}
+	for ch := range s.subscribers {
+		select {
+		case ch <- event:
+		default: // the follower is too slow, disconnect it
+			delete(s.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+func (s *replicationServer) subscribe() chan *replicationEvent {
+	ch := make(chan *replicationEvent, subscriberBufferSize)
+	s.mu.Lock()
+	s.subscribers[ch] = struct{}{}
+	s.mu.Unlock()
+	return ch
+}
+
+func (s *replicationServer) unsubscribe(ch chan *replicationEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.subscribers[ch]; ok {
+		delete(s.subscribers, ch)
+		close(ch)
+	}
+}
+
+// ServeHTTP streams newline delimited replication events to a follower until it disconnects.
+func (s *replicationServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := log.FromContext(r.Context())
+	if r.Header.Get(replicaIDHeader) == s.replicaID {
+		http.Error(w, "replica can not follow itself", http.StatusConflict)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	// subscribe before taking the snapshot, so that no addition is lost in between.
+	ch := s.subscribe()
+	defer s.unsubscribe(ch)
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(&replicationEvent{Snapshot: s.indexer.Snapshot()}); err != nil {
+		logger.V(logutil.DEFAULT).Error(err, "Failed to send prefix index snapshot to follower")
+		return
+	}
+	flusher.Flush()
+	logger.V(logutil.DEFAULT).Info("Prefix index follower connected", "remote", r.RemoteAddr)
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-ch:
+			if !ok {
+				logger.V(logutil.DEFAULT).Info("Prefix index follower fell behind, disconnecting", "remote", r.RemoteAddr)
+				return
+			}
+			if err := encoder.Encode(event); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// run serves the replication stream on the given address until the context is done.
+func (s *replicationServer) run(ctx context.Context, address string) error {
+	mux := http.NewServeMux()
+	mux.Handle(ReplicationPath, s)
+	srv := &http.Server{
+		Addr:        address,
+		Handler:     mux,
+		BaseContext: func(_ net.Listener) context.Context { return ctx },
+	}
+
+	go func() {
+		<-ctx.Done()
+		_ = srv.Close()
+	}()
+
+	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return fmt.Errorf("prefix index replication server failed - %w", err)
+	}
+	return nil
+}
+
+// follow connects to the replication source and applies the received events to the indexer, until
+// the context is done. Connection failures are retried.
+func follow(ctx context.Context, sourceURL string, replicaID string, indexer Indexer) {
+	logger := log.FromContext(ctx).WithValues("source", sourceURL)
+	for {
+		if err := followOnce(ctx, sourceURL, replicaID, indexer); err != nil && ctx.Err() == nil {
+			logger.V(logutil.VERBOSE).Info("Prefix index replication stream interrupted", "error", err.Error())
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(followRetryInterval):
+		}
+	}
+}
+
+func followOnce(ctx context.Context, sourceURL string, replicaID string, indexer Indexer) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, sourceURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set(replicaIDHeader, replicaID)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	decoder := json.NewDecoder(resp.Body)
+	for {
+		event := &replicationEvent{}
+		if err := decoder.Decode(event); err != nil {
+			return err
+		}
+		if event.Snapshot != nil {
+			indexer.Restore(event.Snapshot)
+			log.FromContext(ctx).V(logutil.DEFAULT).Info("Restored prefix index from replication source", "source", sourceURL, "pods", len(event.Snapshot.Pods))
+			continue
+		}
+		indexer.Add(event.Hashes, event.Server)
+	}
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package prefix
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+const (
+	// snapshotVersion is the version of the snapshot format. Snapshots with a different version are
+	// ignored on restore.
+	snapshotVersion = 1
+)
+
+// IndexerSnapshot is a point-in-time, serializable copy of the indexer contents.
+type IndexerSnapshot struct {
+	Version int           `json:"version"`
+	Pods    []PodSnapshot `json:"pods"`
+}
+
+// PodSnapshot holds the block hashes cached for a single server.
+type PodSnapshot struct {
+	Server ServerID `json:"server"`
+	// Hashes are ordered from the least recently used to the most recently used, so that
+	// replaying them in order restores the LRU order.
+	Hashes []BlockHash `json:"hashes"`
+}
+
+// Snapshot returns a copy of the indexer contents, including the LRU order of every server.
+func (i *indexer) Snapshot() *IndexerSnapshot {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	snapshot := &IndexerSnapshot{
+		Version: snapshotVersion,
+		Pods:    make([]PodSnapshot, 0, len(i.podToLRU)),
+	}
+	for pod, lruCache := range i.podToLRU {
+		snapshot.Pods = append(snapshot.Pods, PodSnapshot{Server: pod, Hashes: lruCache.Keys()})
+	}
+	return snapshot
+}
+
+// Restore adds the contents of the given snapshot to the indexer. Existing entries are kept, and the
+// restored entries are considered more recently used than them.
+func (i *indexer) Restore(snapshot *IndexerSnapshot) {
+	if snapshot == nil {
+		return
+	}
+	for _, pod := range snapshot.Pods {
+		// Add adds the hashes from the last to the first, so they are given from the most recently used.
+		hashes := slices.Clone(pod.Hashes)
+		slices.Reverse(hashes)
+		i.Add(hashes, pod.Server)
+	}
+}
+
+// writeSnapshotFile atomically writes the snapshot to the given path, by writing to a temporary file
+// in the same directory and renaming it.
+func writeSnapshotFile(path string, snapshot *IndexerSnapshot) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary snapshot file - %w", err)
+	}
+	defer func() {
+		_ = os.Remove(tmp.Name()) // no-op once the file has been renamed
+	}()
+
+	if err := json.NewEncoder(tmp).Encode(snapshot); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to encode snapshot - %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close temporary snapshot file - %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to rename snapshot file - %w", err)
+	}
+	return nil
+}
+
+// readSnapshotFile reads a snapshot from the given path.
+func readSnapshotFile(path string) (*IndexerSnapshot, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	snapshot := &IndexerSnapshot{}
+	if err := json.NewDecoder(file).Decode(snapshot); err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot - %w", err)
+	}
+	if snapshot.Version != snapshotVersion {
+		return nil, fmt.Errorf("unsupported snapshot version %d, expected %d", snapshot.Version, snapshotVersion)
+	}
+	return snapshot, nil
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package prefix
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	server1 := ServerID{Namespace: "default", Name: "server1"}
+	server2 := ServerID{Namespace: "default", Name: "server2"}
+
+	i := newIndexer(3)
+	i.Add([]BlockHash{1, 2, 3}, server1)
+	i.Add([]BlockHash{2}, server1) // 2 becomes the most recently used entry
+	i.Add([]BlockHash{4}, server2)
+
+	path := filepath.Join(t.TempDir(), "prefix-index.json")
+	require.NoError(t, writeSnapshotFile(path, i.Snapshot()))
+
+	snapshot, err := readSnapshotFile(path)
+	require.NoError(t, err)
+
+	restored := newIndexer(3)
+	restored.Restore(snapshot)
+	assert.Equal(t, []BlockHash{3, 1, 2}, restored.podToLRU[server1].Keys(), "LRU order should be preserved")
+	assert.Contains(t, restored.Get(4), server2)
+
+	// The least recently used entry should be evicted first after restoring.
+	restored.Add([]BlockHash{5}, server1)
+	assert.Empty(t, restored.Get(3))
+	assert.Contains(t, restored.Get(2), server1)
+}
+
+func TestPluginRestoresSnapshotAtStartup(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prefix-index.json")
+	config := DefaultConfig
+	config.SnapshotPath = path
+
+	ctx, cancel := context.WithCancel(context.Background())
+	plugin := New(ctx, config)
+	server := ServerID{Namespace: "default", Name: "server1"}
+	plugin.indexer.Add([]BlockHash{7, 8}, server)
+
+	done := make(chan error)
+	go func() { done <- plugin.Start(ctx) }()
+	cancel() // shutdown should write the final snapshot
+	require.NoError(t, <-done)
+
+	restarted := New(context.Background(), config)
+	assert.Contains(t, restarted.indexer.Get(7), server)
+	assert.Contains(t, restarted.indexer.Get(8), server)
+}
+
+func TestReplication(t *testing.T) {
+	server1 := ServerID{Namespace: "default", Name: "server1"}
+	server2 := ServerID{Namespace: "default", Name: "server2"}
+
+	leaderIndexer := newIndexer(10)
+	leaderIndexer.Add([]BlockHash{1, 2}, server1)
+	leader := newReplicationServer("leader", leaderIndexer)
+	srv := httptest.NewServer(leader)
+	defer srv.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel() // disconnect the follower before closing the server
+
+	followerIndexer := newIndexer(10)
+	go follow(ctx, srv.URL, "follower", followerIndexer)
+
+	// The follower starts with the leader's snapshot.
+	assert.Eventually(t, func() bool { return len(followerIndexer.Get(2)) == 1 }, 5*time.Second, 10*time.Millisecond)
+
+	// Additions on the leader are streamed to the follower.
+	leaderIndexer.Add([]BlockHash{3}, server2)
+	leader.publish([]BlockHash{3}, server2)
+	assert.Eventually(t, func() bool { return len(followerIndexer.Get(3)) == 1 }, 5*time.Second, 10*time.Millisecond)
+
+	// A replica can not follow itself.
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+	req.Header.Set(replicaIDHeader, "leader")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+}
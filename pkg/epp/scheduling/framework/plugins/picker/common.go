@@ -16,11 +16,37 @@ limitations under the License.
 
 package picker
 
+import (
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+)
+
 const (
-	DefaultMaxNumOfEndpoints = 1 // common default to all pickers
+	DefaultMaxNumOfEndpoints         = 1 // common default to all pickers
+	DefaultMaxNumOfFallbackEndpoints = 0 // common default to all pickers
 )
 
 // pickerParameters defines the common parameters for all pickers
 type pickerParameters struct {
-	MaxNumOfEndpoints int `json:"maxNumOfEndpoints"`
+	MaxNumOfEndpoints         int `json:"maxNumOfEndpoints"`
+	MaxNumOfFallbackEndpoints int `json:"maxNumOfFallbackEndpoints"`
+}
+
+// newProfileRunResult returns the result of picking from the ordered pods, with the first maxNumOfEndpoints pods
+// as the target pods, and the maxNumOfFallbackEndpoints pods that follow as the fallback pods.
+func newProfileRunResult(orderedPods []*types.ScoredPod, maxNumOfEndpoints int, maxNumOfFallbackEndpoints int) *types.ProfileRunResult {
+	numOfTargetPods := min(maxNumOfEndpoints, len(orderedPods))
+	numOfFallbackPods := min(maxNumOfFallbackEndpoints, len(orderedPods)-numOfTargetPods)
+
+	targetPods := make([]types.Pod, numOfTargetPods)
+	for i, scoredPod := range orderedPods[:numOfTargetPods] {
+		targetPods[i] = scoredPod
+	}
+	result := &types.ProfileRunResult{TargetPods: targetPods}
+	if numOfFallbackPods > 0 {
+		result.FallbackPods = make([]types.Pod, numOfFallbackPods)
+		for i, scoredPod := range orderedPods[numOfTargetPods : numOfTargetPods+numOfFallbackPods] {
+			result.FallbackPods[i] = scoredPod
+		}
+	}
+	return result
 }
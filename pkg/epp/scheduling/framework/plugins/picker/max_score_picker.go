@@ -41,14 +41,14 @@ var _ framework.Picker = &MaxScorePicker{}
 
 // MaxScorePickerFactory defines the factory function for MaxScorePicker.
 func MaxScorePickerFactory(name string, rawParameters json.RawMessage, _ plugins.Handle) (plugins.Plugin, error) {
-	parameters := pickerParameters{MaxNumOfEndpoints: DefaultMaxNumOfEndpoints}
+	parameters := pickerParameters{MaxNumOfEndpoints: DefaultMaxNumOfEndpoints, MaxNumOfFallbackEndpoints: DefaultMaxNumOfFallbackEndpoints}
 	if rawParameters != nil {
 		if err := json.Unmarshal(rawParameters, &parameters); err != nil {
 			return nil, fmt.Errorf("failed to parse the parameters of the '%s' picker - %w", MaxScorePickerType, err)
 		}
 	}
 
-	return NewMaxScorePicker(parameters.MaxNumOfEndpoints).WithName(name), nil
+	return NewMaxScorePicker(parameters.MaxNumOfEndpoints).WithFallbackEndpoints(parameters.MaxNumOfFallbackEndpoints).WithName(name), nil
 }
 
 // NewMaxScorePicker initializes a new MaxScorePicker and returns its pointer.
@@ -67,6 +67,8 @@ func NewMaxScorePicker(maxNumOfEndpoints int) *MaxScorePicker {
 type MaxScorePicker struct {
 	typedName         plugins.TypedName
 	maxNumOfEndpoints int // maximum number of endpoints to pick
+	// maxNumOfFallbackEndpoints is the maximum number of endpoints to pick as fallbacks, after the picked endpoints
+	maxNumOfFallbackEndpoints int
 }
 
 // WithName sets the picker's name
@@ -75,6 +77,13 @@ func (p *MaxScorePicker) WithName(name string) *MaxScorePicker {
 	return p
 }
 
+// WithFallbackEndpoints sets the maximum number of endpoints to pick as fallbacks for the picked endpoints, in the
+// order they would have been picked in.
+func (p *MaxScorePicker) WithFallbackEndpoints(maxNumOfFallbackEndpoints int) *MaxScorePicker {
+	p.maxNumOfFallbackEndpoints = max(maxNumOfFallbackEndpoints, 0)
+	return p
+}
+
 // TypedName returns the type and name tuple of this plugin instance.
 func (p *MaxScorePicker) TypedName() plugins.TypedName {
 	return p.typedName
@@ -105,15 +114,5 @@ func (p *MaxScorePicker) Pick(ctx context.Context, cycleState *types.CycleState,
 		return 0
 	})
 
-	// if we have enough pods to return keep only the "maxNumOfEndpoints" highest scored pods
-	if p.maxNumOfEndpoints < len(scoredPods) {
-		scoredPods = scoredPods[:p.maxNumOfEndpoints]
-	}
-
-	targetPods := make([]types.Pod, len(scoredPods))
-	for i, scoredPod := range scoredPods {
-		targetPods[i] = scoredPod
-	}
-
-	return &types.ProfileRunResult{TargetPods: targetPods}
+	return newProfileRunResult(scoredPods, p.maxNumOfEndpoints, p.maxNumOfFallbackEndpoints)
 }
@@ -135,3 +135,107 @@ func TestPickMaxScorePicker(t *testing.T) {
 		})
 	}
 }
+
+func TestPickFallbackPods(t *testing.T) {
+	pod1 := &types.PodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: "pod1"}}}
+	pod2 := &types.PodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: "pod2"}}}
+	pod3 := &types.PodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: "pod3"}}}
+	pod4 := &types.PodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: "pod4"}}}
+
+	tests := []struct {
+		name               string
+		picker             framework.Picker
+		input              []*types.ScoredPod
+		wantTargets        []types.Pod
+		wantFallbacks      []types.Pod
+		wantNumOfPods      int // for random pickers, the total number of target and fallback pods
+		wantNumOfFallbacks int
+	}{
+		{
+			name:   "No fallback pods by default",
+			picker: NewMaxScorePicker(1),
+			input: []*types.ScoredPod{
+				{Pod: pod1, Score: 10},
+				{Pod: pod2, Score: 25},
+			},
+			wantTargets: []types.Pod{
+				&types.ScoredPod{Pod: pod2, Score: 25},
+			},
+		},
+		{
+			name:   "Fallback pods sorted by highest score",
+			picker: NewMaxScorePicker(1).WithFallbackEndpoints(2),
+			input: []*types.ScoredPod{
+				{Pod: pod1, Score: 10},
+				{Pod: pod2, Score: 25},
+				{Pod: pod3, Score: 15},
+				{Pod: pod4, Score: 5},
+			},
+			wantTargets: []types.Pod{
+				&types.ScoredPod{Pod: pod2, Score: 25},
+			},
+			wantFallbacks: []types.Pod{
+				&types.ScoredPod{Pod: pod3, Score: 15},
+				&types.ScoredPod{Pod: pod1, Score: 10},
+			},
+		},
+		{
+			name:   "Fallback pods limited by the number of candidates",
+			picker: NewMaxScorePicker(2).WithFallbackEndpoints(3),
+			input: []*types.ScoredPod{
+				{Pod: pod1, Score: 10},
+				{Pod: pod2, Score: 25},
+				{Pod: pod3, Score: 15},
+			},
+			wantTargets: []types.Pod{
+				&types.ScoredPod{Pod: pod2, Score: 25},
+				&types.ScoredPod{Pod: pod3, Score: 15},
+			},
+			wantFallbacks: []types.Pod{
+				&types.ScoredPod{Pod: pod1, Score: 10},
+			},
+		},
+		{
+			name:   "Random picker fallback pods",
+			picker: NewRandomPicker(1).WithFallbackEndpoints(2),
+			input: []*types.ScoredPod{
+				{Pod: pod1, Score: 10},
+				{Pod: pod2, Score: 25},
+				{Pod: pod3, Score: 15},
+				{Pod: pod4, Score: 5},
+			},
+			wantNumOfPods:      3,
+			wantNumOfFallbacks: 2,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := test.picker.Pick(context.Background(), types.NewCycleState(), test.input)
+
+			if test.wantNumOfPods > 0 {
+				if got := len(result.TargetPods) + len(result.FallbackPods); got != test.wantNumOfPods {
+					t.Errorf("Unexpected number of picked pods, want %d, got %d", test.wantNumOfPods, got)
+				}
+				if got := len(result.FallbackPods); got != test.wantNumOfFallbacks {
+					t.Errorf("Unexpected number of fallback pods, want %d, got %d", test.wantNumOfFallbacks, got)
+				}
+				picked := map[string]bool{}
+				for _, pod := range append(result.TargetPods, result.FallbackPods...) {
+					if picked[pod.String()] {
+						t.Errorf("Pod %s picked more than once", pod)
+					}
+					picked[pod.String()] = true
+				}
+				return
+			}
+
+			if diff := cmp.Diff(test.wantTargets, result.TargetPods); diff != "" {
+				t.Errorf("Unexpected target pods (-want +got): %v", diff)
+			}
+			if diff := cmp.Diff(test.wantFallbacks, result.FallbackPods); diff != "" {
+				t.Errorf("Unexpected fallback pods (-want +got): %v", diff)
+			}
+		})
+	}
+}
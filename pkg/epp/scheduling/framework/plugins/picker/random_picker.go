@@ -39,14 +39,14 @@ const (
 var _ framework.Picker = &RandomPicker{}
 
 func RandomPickerFactory(name string, rawParameters json.RawMessage, _ plugins.Handle) (plugins.Plugin, error) {
-	parameters := pickerParameters{MaxNumOfEndpoints: DefaultMaxNumOfEndpoints}
+	parameters := pickerParameters{MaxNumOfEndpoints: DefaultMaxNumOfEndpoints, MaxNumOfFallbackEndpoints: DefaultMaxNumOfFallbackEndpoints}
 	if rawParameters != nil {
 		if err := json.Unmarshal(rawParameters, &parameters); err != nil {
 			return nil, fmt.Errorf("failed to parse the parameters of the '%s' picker - %w", RandomPickerType, err)
 		}
 	}
 
-	return NewRandomPicker(parameters.MaxNumOfEndpoints).WithName(name), nil
+	return NewRandomPicker(parameters.MaxNumOfEndpoints).WithFallbackEndpoints(parameters.MaxNumOfFallbackEndpoints).WithName(name), nil
 }
 
 // NewRandomPicker initializes a new RandomPicker and returns its pointer.
@@ -65,6 +65,8 @@ func NewRandomPicker(maxNumOfEndpoints int) *RandomPicker {
 type RandomPicker struct {
 	typedName         plugins.TypedName
 	maxNumOfEndpoints int
+	// maxNumOfFallbackEndpoints is the maximum number of endpoints to pick as fallbacks, after the picked endpoints
+	maxNumOfFallbackEndpoints int
 }
 
 // WithName sets the name of the picker.
@@ -73,6 +75,13 @@ func (p *RandomPicker) WithName(name string) *RandomPicker {
 	return p
 }
 
+// WithFallbackEndpoints sets the maximum number of endpoints to pick as fallbacks for the picked endpoints, in the
+// order they would have been picked in.
+func (p *RandomPicker) WithFallbackEndpoints(maxNumOfFallbackEndpoints int) *RandomPicker {
+	p.maxNumOfFallbackEndpoints = max(maxNumOfFallbackEndpoints, 0)
+	return p
+}
+
 // TypedName returns the type and name tuple of this plugin instance.
 func (p *RandomPicker) TypedName() plugins.TypedName {
 	return p.typedName
@@ -93,15 +102,5 @@ func (p *RandomPicker) Pick(ctx context.Context, _ *types.CycleState, scoredPods
 		scoredPods[i], scoredPods[j] = scoredPods[j], scoredPods[i]
 	})
 
-	// if we have enough pods to return keep only the relevant subset
-	if p.maxNumOfEndpoints < len(scoredPods) {
-		scoredPods = scoredPods[:p.maxNumOfEndpoints]
-	}
-
-	targetPods := make([]types.Pod, len(scoredPods))
-	for i, scoredPod := range scoredPods {
-		targetPods[i] = scoredPod
-	}
-
-	return &types.ProfileRunResult{TargetPods: targetPods}
+	return newProfileRunResult(scoredPods, p.maxNumOfEndpoints, p.maxNumOfFallbackEndpoints)
 }
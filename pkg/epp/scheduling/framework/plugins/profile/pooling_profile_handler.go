@@ -0,0 +1,132 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package profile
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/plugins"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+)
+
+const (
+	PoolingProfileHandlerType = "pooling-profile-handler"
+
+	// DefaultPoolingProfile is the name of the profile pooling requests are scheduled with by default.
+	DefaultPoolingProfile = "pooling"
+)
+
+// compile-time type assertion
+var _ framework.ProfileHandler = &PoolingProfileHandler{}
+
+// PoolingProfileHandlerConfig is the configuration of the PoolingProfileHandler.
+type PoolingProfileHandlerConfig struct {
+	// PoolingProfile is the name of the profile embeddings and rerank requests are scheduled with.
+	PoolingProfile string `json:"poolingProfile"`
+}
+
+// PoolingProfileHandlerFactory defines the factory function for PoolingProfileHandler.
+func PoolingProfileHandlerFactory(name string, rawParameters json.RawMessage, _ plugins.Handle) (plugins.Plugin, error) {
+	config := PoolingProfileHandlerConfig{PoolingProfile: DefaultPoolingProfile}
+	if rawParameters != nil {
+		if err := json.Unmarshal(rawParameters, &config); err != nil {
+			return nil, fmt.Errorf("failed to parse the parameters of the '%s' profile handler - %w", PoolingProfileHandlerType, err)
+		}
+	}
+	if config.PoolingProfile == "" {
+		return nil, fmt.Errorf("the poolingProfile of the '%s' profile handler must not be empty", PoolingProfileHandlerType)
+	}
+
+	return NewPoolingProfileHandler(config.PoolingProfile).WithName(name), nil
+}
+
+// NewPoolingProfileHandler initializes a new PoolingProfileHandler and returns its pointer.
+func NewPoolingProfileHandler(poolingProfile string) *PoolingProfileHandler {
+	return &PoolingProfileHandler{
+		typedName:      plugins.TypedName{Type: PoolingProfileHandlerType, Name: PoolingProfileHandlerType},
+		poolingProfile: poolingProfile,
+	}
+}
+
+// PoolingProfileHandler schedules pooling requests (embeddings and rerank) with a dedicated profile, and
+// all other requests with the single other profile. Pooling requests have no decode phase, so they are
+// short and of predictable cost, and are best spread by load rather than by cache affinity.
+type PoolingProfileHandler struct {
+	typedName      plugins.TypedName
+	poolingProfile string
+}
+
+// TypedName returns the type and name tuple of this plugin instance.
+func (h *PoolingProfileHandler) TypedName() plugins.TypedName {
+	return h.typedName
+}
+
+// WithName sets the name of the profile handler.
+func (h *PoolingProfileHandler) WithName(name string) *PoolingProfileHandler {
+	h.typedName.Name = name
+	return h
+}
+
+// PoolingProfile returns the name of the profile pooling requests are scheduled with.
+func (h *PoolingProfileHandler) PoolingProfile() string {
+	return h.poolingProfile
+}
+
+// Pick selects the SchedulingProfiles to run from the list of candidate profiles, while taking into consideration the request properties and the
+// previously executed cycles along with their results.
+func (h *PoolingProfileHandler) Pick(_ context.Context, _ *types.CycleState, request *types.LLMRequest, profiles map[string]*framework.SchedulerProfile,
+	profileResults map[string]*types.ProfileRunResult) map[string]*framework.SchedulerProfile {
+	if len(profileResults) > 0 { // a profile has been executed already in a previous call
+		return map[string]*framework.SchedulerProfile{}
+	}
+
+	picked := map[string]*framework.SchedulerProfile{}
+	for name, profile := range profiles {
+		if (name == h.poolingProfile) == request.Data.IsPooling() {
+			picked[name] = profile
+		}
+	}
+	return picked
+}
+
+// ProcessResults handles the outcome of the profile runs after all profiles ran.
+// It specifies in the SchedulingResult the key of the primary profile that should be used to get the request selected destination.
+// When a profile run fails, its result in the profileResults map is nil.
+func (h *PoolingProfileHandler) ProcessResults(_ context.Context, _ *types.CycleState, _ *types.LLMRequest,
+	profileResults map[string]*types.ProfileRunResult) (*types.SchedulingResult, error) {
+	if len(profileResults) != 1 {
+		return nil, errors.New("pooling profile handler is intended to be used with a pooling profile and a single other profile, failed to process multiple profiles")
+	}
+
+	var profileName string
+	for name := range profileResults {
+		profileName = name
+	}
+
+	if profileResults[profileName] == nil { // there was an error while running the profile
+		return nil, fmt.Errorf("failed to run scheduler profile '%s'", profileName)
+	}
+
+	return &types.SchedulingResult{
+		ProfileResults:     profileResults,
+		PrimaryProfileName: profileName,
+	}, nil
+}
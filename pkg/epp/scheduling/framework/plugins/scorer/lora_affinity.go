@@ -62,19 +62,28 @@ func (s *LoraAffinityScorer) WithName(name string) *LoraAffinityScorer {
 
 func (s *LoraAffinityScorer) Score(_ context.Context, _ *types.CycleState, request *types.LLMRequest, pods []types.Pod) map[types.Pod]float64 {
 	scores := make(map[types.Pod]float64, len(pods))
+	pooling := request.Data.IsPooling()
 
 	// Assign a score to each pod for loading the target adapter.
 	for _, pod := range pods {
 		_, active := pod.GetMetrics().ActiveModels[request.TargetModel]
 		_, waiting := pod.GetMetrics().WaitingModels[request.TargetModel]
+		hasCapacity := len(pod.GetMetrics().ActiveModels)+len(pod.GetMetrics().WaitingModels) < pod.GetMetrics().MaxActiveModels
 
 		// Determine the model server's suitability score based on adapter load status and capacity.
 		switch {
 		// Ideal: The adapter is already active on this model server.
 		case active:
 			scores[pod] = 1.0
+		// Pooling requests (embeddings and rerank) have no decode phase, so loading an adapter is not
+		// amortized over generated tokens. For these, a model server already loading the adapter is
+		// preferred over one that would start loading it.
+		case pooling && waiting:
+			scores[pod] = 0.8
+		case pooling && hasCapacity:
+			scores[pod] = 0.6
 		// Good: The model server has capacity to load at least one more adapter.
-		case len(pod.GetMetrics().ActiveModels)+len(pod.GetMetrics().WaitingModels) < pod.GetMetrics().MaxActiveModels:
+		case hasCapacity:
 			scores[pod] = 0.8
 		// Moderate: The adapter is already in the queue to be loaded on this model server.
 		case waiting:
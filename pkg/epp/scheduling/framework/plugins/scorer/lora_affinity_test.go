@@ -148,6 +148,37 @@ func TestLoraAffinityScorer(t *testing.T) {
 				"pod5": 0.0,
 			},
 		},
+		{
+			name: "Pooling request prefers pods loading the target model",
+			request: &types.LLMRequest{
+				TargetModel: "active-model-1",
+				Data: &types.LLMRequestData{
+					Embeddings: &types.EmbeddingsRequest{Input: types.EmbeddingsInput{Texts: []string{"embed this"}}},
+				},
+			},
+			pods: []types.Pod{
+				&types.PodMetrics{
+					Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: "pod1"}},
+					MetricsState: &backendmetrics.MetricsState{
+						ActiveModels:    map[string]int{"active-model-2": 1},
+						WaitingModels:   map[string]int{"active-model-1": 1},
+						MaxActiveModels: 5,
+					},
+				},
+				&types.PodMetrics{
+					Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: "pod2"}},
+					MetricsState: &backendmetrics.MetricsState{
+						ActiveModels:    map[string]int{"active-model-2": 1},
+						WaitingModels:   map[string]int{},
+						MaxActiveModels: 5,
+					},
+				},
+			},
+			expectedScoresPod: map[string]float64{
+				"pod1": 0.8,
+				"pod2": 0.6,
+			},
+		},
 		{
 			name:              "Empty pods slice",
 			request:           &types.LLMRequest{TargetModel: "modelA"},
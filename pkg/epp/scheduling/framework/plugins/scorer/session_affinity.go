@@ -0,0 +1,263 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scorer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/cespare/xxhash/v2"
+
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/plugins"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+)
+
+const (
+	SessionAffinityScorerType = "session-affinity-scorer"
+
+	// DefaultSessionHeader is the request header the session key is read from by default.
+	DefaultSessionHeader = "x-session-id"
+	// DefaultSessionLoadFactor is the default bound of the load of the pod a session is pinned to, as a
+	// multiple of the average pod load.
+	DefaultSessionLoadFactor = 1.25
+	// DefaultVirtualNodesPerPod is the default number of points every pod has on the hash ring.
+	DefaultVirtualNodesPerPod = 100
+)
+
+// SessionAffinityScorerConfig is the configuration of the SessionAffinityScorer.
+type SessionAffinityScorerConfig struct {
+	// SessionHeader is the request header holding the session key. If the header is not set, the
+	// `user` field of the request body is used, and then the `conversation_id` field.
+	SessionHeader string `json:"sessionHeader"`
+	// LoadFactor bounds the load (running and waiting requests) of the pod a session is pinned to, as a
+	// multiple of the average load of the candidate pods. Must be at least 1.
+	LoadFactor float64 `json:"loadFactor"`
+	// VirtualNodesPerPod is the number of points every pod has on the hash ring. More points spread the
+	// sessions more evenly.
+	VirtualNodesPerPod int `json:"virtualNodesPerPod"`
+}
+
+// compile-time type assertion
+var _ framework.Scorer = &SessionAffinityScorer{}
+
+// SessionAffinityScorerFactory defines the factory function for SessionAffinityScorer.
+func SessionAffinityScorerFactory(name string, rawParameters json.RawMessage, _ plugins.Handle) (plugins.Plugin, error) {
+	config := SessionAffinityScorerConfig{
+		SessionHeader:      DefaultSessionHeader,
+		LoadFactor:         DefaultSessionLoadFactor,
+		VirtualNodesPerPod: DefaultVirtualNodesPerPod,
+	}
+	if rawParameters != nil {
+		if err := json.Unmarshal(rawParameters, &config); err != nil {
+			return nil, fmt.Errorf("failed to parse the parameters of the '%s' scorer - %w", SessionAffinityScorerType, err)
+		}
+	}
+	if config.LoadFactor < 1 {
+		return nil, fmt.Errorf("invalid loadFactor %v for the '%s' scorer, must be at least 1", config.LoadFactor, SessionAffinityScorerType)
+	}
+	if config.VirtualNodesPerPod <= 0 {
+		return nil, fmt.Errorf("invalid virtualNodesPerPod %d for the '%s' scorer, must be positive", config.VirtualNodesPerPod,
+			SessionAffinityScorerType)
+	}
+
+	return NewSessionAffinityScorer(config).WithName(name), nil
+}
+
+// NewSessionAffinityScorer initializes a new SessionAffinityScorer and returns its pointer.
+func NewSessionAffinityScorer(config SessionAffinityScorerConfig) *SessionAffinityScorer {
+	config.SessionHeader = strings.ToLower(config.SessionHeader) // header keys are lower case
+	return &SessionAffinityScorer{
+		typedName: plugins.TypedName{Type: SessionAffinityScorerType, Name: SessionAffinityScorerType},
+		config:    config,
+	}
+}
+
+// SessionAffinityScorer pins the requests of a session to a pod, using consistent hashing with bounded
+// loads. The session key is hashed onto a ring of the candidate pods, and the first pod on the ring
+// whose load is below the bound gets the maximum score, while all other pods get zero.
+// Adding or removing a pod only moves the sessions of its neighbours on the ring, and a session whose
+// pod is at or above the load bound falls back to the next pod on the ring, until the load drops again.
+// Requests without a session key get zero for all pods.
+type SessionAffinityScorer struct {
+	typedName plugins.TypedName
+	config    SessionAffinityScorerConfig
+
+	mu   sync.Mutex
+	ring *hashRing // the ring of the pods seen as candidates, rebuilt when a new pod is seen
+}
+
+// hashRing is a consistent hashing ring of pods. Since the points of a pod don't depend on the other
+// pods, walking the ring of a superset of the candidate pods and skipping the other pods gives the
+// same result as the ring of the candidate pods, so filters selecting a subset of the pods don't
+// cause the ring to be rebuilt.
+type hashRing struct {
+	// members are the pods on the ring, and whether they were candidates of a request since the ring
+	// was built. Members that were not are dropped when the ring is rebuilt.
+	members map[string]bool
+	points  []ringPoint // sorted by hash
+}
+
+type ringPoint struct {
+	hash uint64
+	pod  string
+}
+
+// TypedName returns the type and name tuple of this plugin instance.
+func (s *SessionAffinityScorer) TypedName() plugins.TypedName {
+	return s.typedName
+}
+
+// WithName sets the name of the scorer.
+func (s *SessionAffinityScorer) WithName(name string) *SessionAffinityScorer {
+	s.typedName.Name = name
+	return s
+}
+
+// Score returns the scoring result for the given list of pods based on context.
+func (s *SessionAffinityScorer) Score(_ context.Context, _ *types.CycleState, request *types.LLMRequest, pods []types.Pod) map[types.Pod]float64 {
+	scores := make(map[types.Pod]float64, len(pods))
+	for _, pod := range pods {
+		scores[pod] = 0
+	}
+	key := s.sessionKey(request)
+	if key == "" || len(pods) == 0 {
+		return scores
+	}
+
+	podsByName := make(map[string]types.Pod, len(pods))
+	names := make([]string, 0, len(pods))
+	loads := make(map[string]int, len(pods))
+	totalLoad := 0
+	for _, pod := range pods {
+		name := pod.GetPod().NamespacedName.String()
+		podsByName[name] = pod
+		names = append(names, name)
+		if m := pod.GetMetrics(); m != nil {
+			loads[name] = m.RunningQueueSize + m.WaitingQueueSize
+		}
+		totalLoad += loads[name]
+	}
+	// The bound of consistent hashing with bounded loads, where one is added for the current request.
+	// At least one pod is always below it, since LoadFactor is at least 1.
+	bound := int(math.Ceil(s.config.LoadFactor * float64(totalLoad+1) / float64(len(pods))))
+
+	points := s.getRing(names).points
+	keyHash := xxhash.Sum64String(key)
+	start := sort.Search(len(points), func(i int) bool { return points[i].hash >= keyHash })
+	visited := make(map[string]struct{}, len(pods))
+	for i := 0; i < len(points) && len(visited) < len(pods); i++ {
+		name := points[(start+i)%len(points)].pod
+		if _, ok := podsByName[name]; !ok {
+			continue
+		}
+		if _, ok := visited[name]; ok {
+			continue
+		}
+		visited[name] = struct{}{}
+		if loads[name] < bound {
+			scores[podsByName[name]] = 1
+			break
+		}
+	}
+	return scores
+}
+
+// sessionKey returns the session key of the request, or an empty string if it has none.
+func (s *SessionAffinityScorer) sessionKey(request *types.LLMRequest) string {
+	if key := request.Headers[s.config.SessionHeader]; key != "" {
+		return key
+	}
+	if request.Data == nil {
+		return ""
+	}
+	if completions := request.Data.Completions; completions != nil {
+		return completions.User
+	}
+	if chat := request.Data.ChatCompletions; chat != nil {
+		if chat.User != "" {
+			return chat.User
+		}
+		return chat.ConversationID
+	}
+	if responses := request.Data.Responses; responses != nil {
+		return responses.User
+	}
+	if embeddings := request.Data.Embeddings; embeddings != nil {
+		return embeddings.User
+	}
+	if rerank := request.Data.Rerank; rerank != nil {
+		return rerank.User
+	}
+	return ""
+}
+
+// getRing returns a ring that has the given pods, reusing the last ring if it has all of them.
+func (s *SessionAffinityScorer) getRing(names []string) *hashRing {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ring != nil {
+		complete := true
+		for _, name := range names {
+			if _, ok := s.ring.members[name]; !ok {
+				complete = false
+				break
+			}
+		}
+		if complete {
+			for _, name := range names {
+				s.ring.members[name] = true
+			}
+			return s.ring
+		}
+	}
+
+	members := make(map[string]bool, len(names))
+	if s.ring != nil {
+		for name, seen := range s.ring.members {
+			if seen {
+				members[name] = false
+			}
+		}
+	}
+	for _, name := range names {
+		members[name] = true
+	}
+	ring := &hashRing{
+		members: members,
+		points:  make([]ringPoint, 0, len(members)*s.config.VirtualNodesPerPod),
+	}
+	for name := range members {
+		for i := 0; i < s.config.VirtualNodesPerPod; i++ {
+			ring.points = append(ring.points, ringPoint{hash: xxhash.Sum64String(name + "#" + strconv.Itoa(i)), pod: name})
+		}
+	}
+	sort.Slice(ring.points, func(i, j int) bool {
+		if ring.points[i].hash != ring.points[j].hash {
+			return ring.points[i].hash < ring.points[j].hash
+		}
+		return ring.points[i].pod < ring.points[j].pod
+	})
+	s.ring = ring
+	return ring
+}
@@ -0,0 +1,188 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scorer
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend"
+	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+)
+
+func newSessionTestPods(n int) []types.Pod {
+	pods := make([]types.Pod, n)
+	for i := range pods {
+		pods[i] = &types.PodMetrics{
+			Pod:          &backend.Pod{NamespacedName: k8stypes.NamespacedName{Namespace: "default", Name: fmt.Sprintf("pod%d", i)}},
+			MetricsState: &backendmetrics.MetricsState{},
+		}
+	}
+	return pods
+}
+
+// pinnedPod returns the pod with the maximum score, or nil if there is none.
+func pinnedPod(t *testing.T, scores map[types.Pod]float64) types.Pod {
+	var pinned types.Pod
+	for pod, score := range scores {
+		if score == 1 {
+			assert.Nil(t, pinned, "only one pod should be pinned")
+			pinned = pod
+		} else {
+			assert.Equal(t, float64(0), score)
+		}
+	}
+	return pinned
+}
+
+func TestSessionAffinityScorerSessionKey(t *testing.T) {
+	scorer := NewSessionAffinityScorer(SessionAffinityScorerConfig{
+		SessionHeader:      "X-Session-Id",
+		LoadFactor:         DefaultSessionLoadFactor,
+		VirtualNodesPerPod: DefaultVirtualNodesPerPod,
+	})
+
+	tests := []struct {
+		name    string
+		request *types.LLMRequest
+		want    string
+	}{
+		{
+			name: "header takes precedence",
+			request: &types.LLMRequest{
+				Headers: map[string]string{"x-session-id": "session"},
+				Data:    &types.LLMRequestData{ChatCompletions: &types.ChatCompletionsRequest{User: "user", ConversationID: "conversation"}},
+			},
+			want: "session",
+		},
+		{
+			name: "user field",
+			request: &types.LLMRequest{
+				Data: &types.LLMRequestData{ChatCompletions: &types.ChatCompletionsRequest{User: "user", ConversationID: "conversation"}},
+			},
+			want: "user",
+		},
+		{
+			name: "conversation id",
+			request: &types.LLMRequest{
+				Data: &types.LLMRequestData{ChatCompletions: &types.ChatCompletionsRequest{ConversationID: "conversation"}},
+			},
+			want: "conversation",
+		},
+		{
+			name: "completions user field",
+			request: &types.LLMRequest{
+				Data: &types.LLMRequestData{Completions: &types.CompletionsRequest{User: "user"}},
+			},
+			want: "user",
+		},
+		{
+			name:    "no session key",
+			request: &types.LLMRequest{Data: &types.LLMRequestData{Completions: &types.CompletionsRequest{}}},
+			want:    "",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, scorer.sessionKey(test.request))
+		})
+	}
+}
+
+func TestSessionAffinityScorer(t *testing.T) {
+	scorer := NewSessionAffinityScorer(SessionAffinityScorerConfig{
+		SessionHeader:      DefaultSessionHeader,
+		LoadFactor:         DefaultSessionLoadFactor,
+		VirtualNodesPerPod: DefaultVirtualNodesPerPod,
+	})
+	pods := newSessionTestPods(5)
+	request := func(session string) *types.LLMRequest {
+		return &types.LLMRequest{Headers: map[string]string{DefaultSessionHeader: session}}
+	}
+
+	// Requests without a session key are not pinned.
+	assert.Nil(t, pinnedPod(t, scorer.Score(context.Background(), nil, &types.LLMRequest{}, pods)))
+
+	// Requests of a session are pinned to the same pod.
+	pinned := make(map[string]types.Pod)
+	for i := 0; i < 200; i++ {
+		session := fmt.Sprintf("session%d", i)
+		pinned[session] = pinnedPod(t, scorer.Score(context.Background(), nil, request(session), pods))
+		assert.NotNil(t, pinned[session])
+		assert.Equal(t, pinned[session], pinnedPod(t, scorer.Score(context.Background(), nil, request(session), pods)))
+	}
+
+	// Removing a pod only moves the sessions of that pod.
+	removed := pods[2]
+	remaining := append(append([]types.Pod{}, pods[:2]...), pods[3:]...)
+	for session, pod := range pinned {
+		got := pinnedPod(t, scorer.Score(context.Background(), nil, request(session), remaining))
+		if pod == removed {
+			assert.NotEqual(t, removed, got)
+		} else {
+			assert.Equal(t, pod, got, "session %s should not move", session)
+		}
+	}
+
+	// A session falls back to another pod while its pod is above the load bound, and returns after.
+	session := "session0"
+	pod := pinned[session].(*types.PodMetrics)
+	pod.MetricsState.WaitingQueueSize = 10
+	fallback := pinnedPod(t, scorer.Score(context.Background(), nil, request(session), pods))
+	assert.NotEqual(t, pod, fallback)
+	assert.Equal(t, fallback, pinnedPod(t, scorer.Score(context.Background(), nil, request(session), pods)),
+		"the fallback pod should be stable")
+	pod.MetricsState.WaitingQueueSize = 0
+	assert.Equal(t, pod, pinnedPod(t, scorer.Score(context.Background(), nil, request(session), pods)))
+}
+
+func TestSessionAffinityScorerCandidateSubsets(t *testing.T) {
+	config := SessionAffinityScorerConfig{
+		SessionHeader:      DefaultSessionHeader,
+		LoadFactor:         DefaultSessionLoadFactor,
+		VirtualNodesPerPod: DefaultVirtualNodesPerPod,
+	}
+	scorer := NewSessionAffinityScorer(config)
+	pods := newSessionTestPods(6)
+	subset := []types.Pod{pods[1], pods[3], pods[4]}
+	request := func(session string) *types.LLMRequest {
+		return &types.LLMRequest{Headers: map[string]string{DefaultSessionHeader: session}}
+	}
+
+	scorer.Score(context.Background(), nil, request("session"), pods)
+	ring := scorer.ring
+	for i := 0; i < 100; i++ {
+		session := fmt.Sprintf("session%d", i)
+		// The subset is scored as by a scorer that only saw the subset, without rebuilding the ring.
+		want := pinnedPod(t, NewSessionAffinityScorer(config).Score(context.Background(), nil, request(session), subset))
+		assert.Equal(t, want, pinnedPod(t, scorer.Score(context.Background(), nil, request(session), subset)))
+	}
+	assert.Same(t, ring, scorer.ring, "the ring should not be rebuilt for a subset of its pods")
+
+	// Pods that are not candidates for a whole ring generation are dropped when it is rebuilt.
+	added := newSessionTestPods(7)[6]
+	scorer.Score(context.Background(), nil, request("session"), append(append([]types.Pod{}, subset...), added))
+	assert.NotSame(t, ring, scorer.ring)
+	assert.Len(t, scorer.ring.members, 7)
+	scorer.Score(context.Background(), nil, request("session"), []types.Pod{pods[0], newSessionTestPods(8)[7]})
+	assert.Len(t, scorer.ring.members, 6, "the pods not seen since the last rebuild should be dropped")
+}
@@ -0,0 +1,266 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package types
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cespare/xxhash/v2"
+)
+
+// Content part types of the OpenAI chat-completions API.
+const (
+	TextContentPart       = "text"
+	RefusalContentPart    = "refusal"
+	ImageURLContentPart   = "image_url"
+	InputAudioContentPart = "input_audio"
+	FileContentPart       = "file"
+)
+
+// Content part types of the OpenAI responses API.
+const (
+	InputTextContentPart  = "input_text"
+	OutputTextContentPart = "output_text"
+	InputImageContentPart = "input_image"
+	InputFileContentPart  = "input_file"
+)
+
+// Media types of MediaRef.
+const (
+	ImageMedia = "image"
+	AudioMedia = "audio"
+	FileMedia  = "file"
+	// OtherMedia is the media type of content parts of unknown types, such as the video parts of some
+	// model servers, which are referenced as a whole.
+	OtherMedia = "other"
+)
+
+// Content is the content of a chat-completions message. It is either a plain string (Raw), or a
+// list of content parts (Structured) as in the OpenAI multimodal format.
+type Content struct {
+	Raw        string
+	Structured []ContentPart
+}
+
+// ContentPart is a single part of a structured message content. Text parts hold their text, media
+// parts (images, audio and files) hold a reference to their media.
+type ContentPart struct {
+	Type  string    `json:"type"`
+	Text  string    `json:"text,omitempty"`
+	Media *MediaRef `json:"media,omitempty"`
+}
+
+// MediaRef references the media of a content part by hash and size, so that plugins can use the
+// media (e.g. for prefix matching or request size accounting) without holding the data itself.
+type MediaRef struct {
+	// Type is the media type, one of ImageMedia, AudioMedia, FileMedia or OtherMedia.
+	Type string `json:"type"`
+	// Hash is the hex encoded xxhash of the media data, or of the URL or ID for referenced media, or of
+	// the whole part for OtherMedia.
+	Hash string `json:"hash"`
+	// Size is the size in bytes of inline (base64 encoded) media data, zero for referenced media, and
+	// the size of the whole part for OtherMedia.
+	Size int `json:"size"`
+}
+
+// Text returns the text of the content, where the text parts of structured content are separated by
+// a new line.
+func (c Content) Text() string {
+	if c.Structured == nil {
+		return c.Raw
+	}
+	texts := make([]string, 0, len(c.Structured))
+	for _, part := range c.Structured {
+		if part.Media == nil {
+			texts = append(texts, part.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+// Media returns the media references of the content, in order.
+func (c Content) Media() []MediaRef {
+	var media []MediaRef
+	for _, part := range c.Structured {
+		if part.Media != nil {
+			media = append(media, *part.Media)
+		}
+	}
+	return media
+}
+
+// MarshalJSON encodes raw content as a string and structured content as a list of parts, where media
+// is encoded as its reference.
+func (c Content) MarshalJSON() ([]byte, error) {
+	if c.Structured == nil {
+		return json.Marshal(c.Raw)
+	}
+	return json.Marshal(c.Structured)
+}
+
+// UnmarshalJSON decodes the content of an OpenAI chat-completions message or responses input message,
+// which is either a string or a list of content parts. The media data of the parts is replaced with
+// its reference. Parts of unknown types are kept as OtherMedia, so that new part types supported by
+// the model servers don't fail the request.
+func (c *Content) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	switch {
+	case bytes.Equal(data, []byte("null")):
+		*c = Content{}
+		return nil
+	case len(data) > 0 && data[0] == '"':
+		*c = Content{}
+		return json.Unmarshal(data, &c.Raw)
+	}
+
+	type contentPart struct {
+		Type    string `json:"type"`
+		Text    string `json:"text"`
+		Refusal string `json:"refusal"`
+		// ImageURL is an object with a url in chat-completions, and a string in responses.
+		ImageURL   json.RawMessage `json:"image_url"`
+		InputAudio *struct {
+			Data   string `json:"data"`
+			Format string `json:"format"`
+		} `json:"input_audio"`
+		File *struct {
+			FileData string `json:"file_data"`
+			FileID   string `json:"file_id"`
+		} `json:"file"`
+		// FileData, FileID and FileURL are set on responses input_image and input_file parts.
+		FileData string `json:"file_data"`
+		FileID   string `json:"file_id"`
+		FileURL  string `json:"file_url"`
+	}
+
+	var rawParts []json.RawMessage
+	if err := json.Unmarshal(data, &rawParts); err != nil {
+		return fmt.Errorf("content must be a string or a list of content parts: %w", err)
+	}
+
+	*c = Content{Structured: make([]ContentPart, 0, len(rawParts))}
+	for i, rawPart := range rawParts {
+		var part contentPart
+		if err := json.Unmarshal(rawPart, &part); err != nil {
+			return fmt.Errorf("content part %d: %w", i, err)
+		}
+		res := ContentPart{Type: part.Type}
+		switch part.Type {
+		case TextContentPart, InputTextContentPart, OutputTextContentPart:
+			res.Text = part.Text
+		case RefusalContentPart:
+			res.Text = part.Refusal
+		case ImageURLContentPart:
+			var imageURL struct {
+				URL string `json:"url"`
+			}
+			if len(part.ImageURL) > 0 {
+				if err := json.Unmarshal(part.ImageURL, &imageURL); err != nil {
+					return fmt.Errorf("content part %d: invalid image_url: %w", i, err)
+				}
+			}
+			if imageURL.URL == "" {
+				return fmt.Errorf("content part %d: image_url part must have a url", i)
+			}
+			res.Media = NewMediaRef(ImageMedia, imageURL.URL)
+		case InputImageContentPart:
+			var imageURL string
+			if len(part.ImageURL) > 0 {
+				if err := json.Unmarshal(part.ImageURL, &imageURL); err != nil {
+					return fmt.Errorf("content part %d: invalid image_url: %w", i, err)
+				}
+			}
+			switch {
+			case imageURL != "":
+				res.Media = NewMediaRef(ImageMedia, imageURL)
+			case part.FileID != "":
+				res.Media = NewMediaRefByID(ImageMedia, part.FileID)
+			default:
+				return fmt.Errorf("content part %d: input_image part must have an image_url or file_id", i)
+			}
+		case InputFileContentPart:
+			switch {
+			case part.FileData != "":
+				res.Media = NewMediaRef(FileMedia, part.FileData)
+			case part.FileURL != "":
+				res.Media = NewMediaRef(FileMedia, part.FileURL)
+			case part.FileID != "":
+				res.Media = NewMediaRefByID(FileMedia, part.FileID)
+			default:
+				return fmt.Errorf("content part %d: input_file part must have file_data, file_url or file_id", i)
+			}
+		case InputAudioContentPart:
+			if part.InputAudio == nil || part.InputAudio.Data == "" {
+				return fmt.Errorf("content part %d: input_audio part must have data", i)
+			}
+			res.Media = &MediaRef{Type: AudioMedia, Hash: hashMedia(part.InputAudio.Data), Size: base64Size(part.InputAudio.Data)}
+		case FileContentPart:
+			if part.File == nil || (part.File.FileData == "" && part.File.FileID == "") {
+				return fmt.Errorf("content part %d: file part must have file_data or file_id", i)
+			}
+			if part.File.FileData != "" {
+				res.Media = NewMediaRef(FileMedia, part.File.FileData)
+			} else {
+				res.Media = NewMediaRefByID(FileMedia, part.File.FileID)
+			}
+		default:
+			res.Media = NewOtherMediaRef(rawPart)
+		}
+		c.Structured = append(c.Structured, res)
+	}
+	return nil
+}
+
+// NewMediaRef returns the reference of media given by a URL, which is either a base64 data URL with
+// inline data, or a URL to download the media from. Base64 encoded data without a data URL prefix is
+// referenced as inline data.
+func NewMediaRef(mediaType string, url string) *MediaRef {
+	ref := &MediaRef{Type: mediaType, Hash: hashMedia(url)}
+	if strings.HasPrefix(url, "data:") {
+		if _, data, ok := strings.Cut(url, ";base64,"); ok {
+			ref.Size = base64Size(data)
+		}
+	} else if !strings.Contains(url, "://") { // file_data is base64 encoded without a data URL prefix
+		ref.Size = base64Size(url)
+	}
+	return ref
+}
+
+// NewMediaRefByID returns the reference of media given by an ID, such as the ID of an uploaded file.
+func NewMediaRefByID(mediaType string, id string) *MediaRef {
+	return &MediaRef{Type: mediaType, Hash: hashMedia(id)}
+}
+
+// NewOtherMediaRef returns the reference of a content part of an unknown type, given as a whole.
+func NewOtherMediaRef(part []byte) *MediaRef {
+	return &MediaRef{Type: OtherMedia, Hash: hashMedia(string(part)), Size: len(part)}
+}
+
+// base64Size returns the decoded size of base64 encoded data, without decoding it.
+func base64Size(data string) int {
+	padding := len(data) - len(strings.TrimRight(data, "="))
+	return base64.RawStdEncoding.DecodedLen(len(data) - padding)
+}
+
+func hashMedia(data string) string {
+	return strconv.FormatUint(xxhash.Sum64String(data), 16)
+}
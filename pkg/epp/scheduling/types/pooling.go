@@ -0,0 +1,177 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// EmbeddingsRequest is a structured representation of the fields we parse out of the /v1/embeddings
+// request body.
+// This struct includes fields usable for plugins and scheduling decisions - and not the entire
+// API spec.
+type EmbeddingsRequest struct {
+	// Input is the text or the tokens to embed.
+	Input EmbeddingsInput `json:"input"`
+	// Dimensions is the optional number of dimensions of the output embeddings.
+	Dimensions int `json:"dimensions,omitempty"`
+	// User is the optional identifier of the end-user sending the request.
+	User string `json:"user,omitempty"`
+}
+
+func (r *EmbeddingsRequest) String() string {
+	if r == nil {
+		return nilString
+	}
+
+	inputLen := 0
+	for _, text := range r.Input.Texts {
+		inputLen += len(text)
+	}
+	for _, tokens := range r.Input.Tokens {
+		inputLen += len(tokens)
+	}
+
+	return fmt.Sprintf("{InputCount: %d, InputLength: %d}", r.Input.Len(), inputLen)
+}
+
+// EmbeddingsInput is the input of an embeddings request, which is a string, a list of strings, a list of
+// tokens or a list of token lists. Every string or token list is embedded separately. Exactly one of
+// Texts and Tokens is set.
+type EmbeddingsInput struct {
+	Texts  []string
+	Tokens [][]int
+}
+
+// Len returns the number of inputs to embed.
+func (i EmbeddingsInput) Len() int {
+	return len(i.Texts) + len(i.Tokens)
+}
+
+// MarshalJSON encodes the input as a list of strings or a list of token lists.
+func (i EmbeddingsInput) MarshalJSON() ([]byte, error) {
+	if i.Tokens != nil {
+		return json.Marshal(i.Tokens)
+	}
+	return json.Marshal(i.Texts)
+}
+
+// UnmarshalJSON decodes the input of an embeddings request.
+func (i *EmbeddingsInput) UnmarshalJSON(data []byte) error {
+	*i = EmbeddingsInput{}
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '"' {
+		var text string
+		if err := json.Unmarshal(data, &text); err != nil {
+			return err
+		}
+		i.Texts = []string{text}
+		return nil
+	}
+
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		return fmt.Errorf("input must be a string, a list of strings or a list of tokens: %w", err)
+	}
+	if len(items) == 0 {
+		return nil
+	}
+	first := bytes.TrimSpace(items[0])
+	switch {
+	case len(first) > 0 && first[0] == '"':
+		if err := json.Unmarshal(data, &i.Texts); err != nil {
+			return fmt.Errorf("input must be a list of strings: %w", err)
+		}
+	case len(first) > 0 && first[0] == '[':
+		if err := json.Unmarshal(data, &i.Tokens); err != nil {
+			return fmt.Errorf("input must be a list of token lists: %w", err)
+		}
+	default:
+		var tokens []int
+		if err := json.Unmarshal(data, &tokens); err != nil {
+			return fmt.Errorf("input must be a list of tokens: %w", err)
+		}
+		i.Tokens = [][]int{tokens}
+	}
+	return nil
+}
+
+// RerankRequest is a structured representation of the fields we parse out of the /rerank request body,
+// as served by vLLM and compatible with the Jina and Cohere rerank APIs.
+// This struct includes fields usable for plugins and scheduling decisions - and not the entire
+// API spec.
+type RerankRequest struct {
+	// Query is the query the documents are ranked against.
+	Query string `json:"query"`
+	// Documents are the documents to rank.
+	Documents []RerankDocument `json:"documents"`
+	// TopN is the optional number of most relevant documents to return.
+	TopN int `json:"top_n,omitempty"`
+	// User is the optional identifier of the end-user sending the request.
+	User string `json:"user,omitempty"`
+}
+
+func (r *RerankRequest) String() string {
+	if r == nil {
+		return nilString
+	}
+
+	documentsLen := 0
+	for _, document := range r.Documents {
+		documentsLen += len(document)
+	}
+
+	return fmt.Sprintf("{QueryLength: %d, DocumentCount: %d, DocumentsLength: %d}", len(r.Query), len(r.Documents), documentsLen)
+}
+
+// RerankDocument is the text of a document of a rerank request, which is given either as a string or as
+// an object with a text field.
+type RerankDocument string
+
+// UnmarshalJSON decodes a document given as a string or as an object with a text field.
+func (d *RerankDocument) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '{' {
+		var document struct {
+			Text string `json:"text"`
+		}
+		if err := json.Unmarshal(data, &document); err != nil {
+			return err
+		}
+		*d = RerankDocument(document.Text)
+		return nil
+	}
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return fmt.Errorf("document must be a string or an object with a text field: %w", err)
+	}
+	*d = RerankDocument(text)
+	return nil
+}
+
+// Text returns the query followed by the documents, each on a new line.
+func (r *RerankRequest) Text() string {
+	texts := make([]string, 0, len(r.Documents)+1)
+	texts = append(texts, r.Query)
+	for _, document := range r.Documents {
+		texts = append(texts, string(document))
+	}
+	return strings.Join(texts, "\n")
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// Input item types of the OpenAI responses API that are parsed into typed fields. Items of other
+// types (e.g. reasoning items) are kept with their type only.
+const (
+	MessageInputItem            = "message"
+	FunctionCallInputItem       = "function_call"
+	FunctionCallOutputInputItem = "function_call_output"
+)
+
+// ResponsesRequest is a structured representation of the fields we parse out of the /v1/responses
+// request body.
+// This struct includes fields usable for plugins and scheduling decisions - and not the entire
+// API spec.
+type ResponsesRequest struct {
+	// Input is the text, image or file input of the request.
+	Input ResponsesInput `json:"input"`
+	// Instructions is the system (or developer) message inserted into the model's context.
+	Instructions string `json:"instructions,omitempty"`
+	// PreviousResponseID is the ID of the previous response of a multi-turn conversation, whose
+	// context the model server continues from.
+	PreviousResponseID string        `json:"previous_response_id,omitempty"`
+	Tools              []interface{} `json:"tools,omitempty"`
+	// User is the optional identifier of the end-user sending the request.
+	User string `json:"user,omitempty"`
+}
+
+func (r *ResponsesRequest) String() string {
+	if r == nil {
+		return nilString
+	}
+
+	inputLen := len(r.Input.Raw)
+	for _, item := range r.Input.Items {
+		inputLen += len(item.Content.Text()) + len(item.Arguments) + len(item.Output)
+	}
+
+	return fmt.Sprintf("{InputLength: %d, InstructionsLength: %d, PreviousResponseID: %s}",
+		inputLen, len(r.Instructions), r.PreviousResponseID)
+}
+
+// Media returns the media references of all input items, in order.
+func (r *ResponsesRequest) Media() []MediaRef {
+	var media []MediaRef
+	for _, item := range r.Input.Items {
+		media = append(media, item.Content.Media()...)
+	}
+	return media
+}
+
+// ResponsesInput is the input of a responses request. It is either a plain string (Raw), which is
+// equivalent to a single user message, or a list of input items (Items).
+type ResponsesInput struct {
+	Raw   string
+	Items []ResponsesInputItem
+}
+
+// ResponsesInputItem is a single input item of a responses request.
+type ResponsesInputItem struct {
+	// Type is the item type, which defaults to MessageInputItem.
+	Type string `json:"type,omitempty"`
+	// Role and Content are set on message items.
+	Role    string  `json:"role,omitempty"`
+	Content Content `json:"content"`
+	// CallID, Name and Arguments are set on function call items, CallID and Output on function call
+	// output items. An output that is not a string is kept as JSON.
+	CallID    string `json:"call_id,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Arguments string `json:"arguments,omitempty"`
+	Output    string `json:"output,omitempty"`
+}
+
+// MarshalJSON encodes raw input as a string and structured input as a list of items.
+func (i ResponsesInput) MarshalJSON() ([]byte, error) {
+	if i.Items == nil {
+		return json.Marshal(i.Raw)
+	}
+	return json.Marshal(i.Items)
+}
+
+// UnmarshalJSON decodes the input of a responses request, which is either a string or a list of
+// input items.
+func (i *ResponsesInput) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '"' {
+		*i = ResponsesInput{}
+		return json.Unmarshal(data, &i.Raw)
+	}
+
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		return fmt.Errorf("input must be a string or a list of input items: %w", err)
+	}
+	*i = ResponsesInput{Items: make([]ResponsesInputItem, 0, len(items))}
+	for n, raw := range items {
+		var item struct {
+			Type string `json:"type"`
+		}
+		if err := json.Unmarshal(raw, &item); err != nil {
+			return fmt.Errorf("input item %d: %w", n, err)
+		}
+		if item.Type == "" {
+			item.Type = MessageInputItem
+		}
+
+		res := ResponsesInputItem{Type: item.Type}
+		switch item.Type {
+		case MessageInputItem, FunctionCallInputItem, FunctionCallOutputInputItem:
+			var fields struct {
+				Role      string          `json:"role"`
+				Content   Content         `json:"content"`
+				CallID    string          `json:"call_id"`
+				Name      string          `json:"name"`
+				Arguments string          `json:"arguments"`
+				Output    json.RawMessage `json:"output"`
+			}
+			if err := json.Unmarshal(raw, &fields); err != nil {
+				return fmt.Errorf("input item %d: %w", n, err)
+			}
+			res.Role, res.Content, res.CallID, res.Name, res.Arguments = fields.Role, fields.Content, fields.CallID, fields.Name, fields.Arguments
+			// The output is either a string or a list of content parts, which is kept as JSON.
+			if len(fields.Output) > 0 && json.Unmarshal(fields.Output, &res.Output) != nil {
+				res.Output = string(fields.Output)
+			}
+		}
+		if res.Type == MessageInputItem && res.Role == "" {
+			return fmt.Errorf("input item %d: message must have a role", n)
+		}
+		i.Items = append(i.Items, res)
+	}
+	return nil
+}
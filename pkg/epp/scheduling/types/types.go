@@ -18,9 +18,11 @@ package types
 
 import (
 	"fmt"
+	"time"
 
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend"
 	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datalayer"
 )
 
 const nilString = "<nil>"
@@ -35,6 +37,10 @@ type LLMRequest struct {
 	Data *LLMRequestData
 	// Headers is a map of the request headers.
 	Headers map[string]string
+	// TTFTSLO is the time to first token objective of the request, or 0 if it has none.
+	TTFTSLO time.Duration
+	// E2ELatencySLO is the end-to-end latency objective of the request, or 0 if it has none.
+	E2ELatencySLO time.Duration
 }
 
 func (r *LLMRequest) String() string {
@@ -48,12 +54,25 @@ func (r *LLMRequest) String() string {
 
 // LLMRequestData contains the request-body fields that we parse out as user input,
 // to be used in forming scheduling decisions.
-// An LLMRequestData must contain exactly one of CompletionsRequest or ChatCompletionsRequest.
+// An LLMRequestData must contain exactly one of CompletionsRequest, ChatCompletionsRequest,
+// ResponsesRequest, EmbeddingsRequest or RerankRequest.
 type LLMRequestData struct {
 	// CompletionsRequest is the representation of the OpenAI /v1/completions request body.
 	Completions *CompletionsRequest `json:"completions,omitempty"`
 	// ChatCompletionsRequest is the representation of the OpenAI /v1/chat_completions request body.
 	ChatCompletions *ChatCompletionsRequest `json:"chat_completions,omitempty"`
+	// ResponsesRequest is the representation of the OpenAI /v1/responses request body.
+	Responses *ResponsesRequest `json:"responses,omitempty"`
+	// EmbeddingsRequest is the representation of the OpenAI /v1/embeddings request body.
+	Embeddings *EmbeddingsRequest `json:"embeddings,omitempty"`
+	// RerankRequest is the representation of the /rerank request body.
+	Rerank *RerankRequest `json:"rerank,omitempty"`
+}
+
+// IsPooling returns true for embeddings and rerank requests. These requests run the prompt through the
+// model once and pool its output, without a decode phase, so their cost is the prefill alone.
+func (d *LLMRequestData) IsPooling() bool {
+	return d != nil && (d.Embeddings != nil || d.Rerank != nil)
 }
 
 // CompletionsRequest is a structured representation of the fields we parse out of the
@@ -63,6 +82,8 @@ type LLMRequestData struct {
 type CompletionsRequest struct {
 	// Prompt is the prompt that was sent in the request body.
 	Prompt string `json:"prompt,omitempty"`
+	// User is the optional identifier of the end-user sending the request.
+	User string `json:"user,omitempty"`
 }
 
 func (r *CompletionsRequest) String() string {
@@ -81,6 +102,9 @@ type ChatCompletionsRequest struct {
 	/* parameters from the official OpenAI chat-completions API */
 	Messages []Message     `json:"messages,omitempty"`
 	Tools    []interface{} `json:"tools,omitempty"`
+	User     string        `json:"user,omitempty"`
+	/* identifier of the conversation a multi-turn request belongs to, set by some clients and gateways */
+	ConversationID string `json:"conversation_id,omitempty"`
 	/* parameters from the HuggingFace transformers chat-templates API */
 	Documents                 []interface{}          `json:"documents,omitempty"`
 	ChatTemplate              string                 `json:"chat_template,omitempty"`
@@ -96,22 +120,38 @@ func (r *ChatCompletionsRequest) String() string {
 	}
 
 	messagesLen := 0
+	mediaCount, mediaSize := 0, 0
 	for _, msg := range r.Messages {
-		messagesLen += len(msg.Content)
+		messagesLen += len(msg.Content.Text())
+		for _, media := range msg.Content.Media() {
+			mediaCount++
+			mediaSize += media.Size
+		}
 	}
 
-	return fmt.Sprintf("{MessagesLength: %d}", messagesLen)
+	return fmt.Sprintf("{MessagesLength: %d, MediaCount: %d, MediaSize: %d}", messagesLen, mediaCount, mediaSize)
+}
+
+// Media returns the media references of all messages, in order.
+func (r *ChatCompletionsRequest) Media() []MediaRef {
+	var media []MediaRef
+	for _, msg := range r.Messages {
+		media = append(media, msg.Content.Media()...)
+	}
+	return media
 }
 
 // Message represents a single message in a chat-completions request.
 type Message struct {
-	Role    string
-	Content string // TODO: support multi-modal content
+	Role    string  `json:"role"`
+	Content Content `json:"content"`
 }
 
 type Pod interface {
 	GetPod() *backend.Pod
 	GetMetrics() *backendmetrics.MetricsState
+	// GetAttributes returns the extended attributes of the endpoint, e.g. its discovered models. May be nil.
+	GetAttributes() datalayer.AttributeMap
 	String() string
 }
 
@@ -136,14 +176,21 @@ func (pm *PodMetrics) GetMetrics() *backendmetrics.MetricsState {
 	return pm.MetricsState
 }
 
+func (pm *PodMetrics) GetAttributes() datalayer.AttributeMap {
+	return pm.Attributes
+}
+
 type PodMetrics struct {
 	*backend.Pod
 	*backendmetrics.MetricsState
+	Attributes datalayer.AttributeMap
 }
 
 // ProfileRunResult captures the profile run result.
 type ProfileRunResult struct {
 	TargetPods []Pod
+	// FallbackPods are the pods to fail over to, in order, if the target pods fail to serve the request.
+	FallbackPods []Pod
 }
 
 // SchedulingResult captures the result of the scheduling cycle.
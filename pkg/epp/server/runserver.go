@@ -31,6 +31,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
 	"sigs.k8s.io/gateway-api-inference-extension/internal/runnable"
@@ -55,6 +56,8 @@ type ExtProcServerRunner struct {
 	CertPath                         string
 	RefreshPrometheusMetricsInterval time.Duration
 	MetricsStalenessThreshold        time.Duration
+	StatusRefreshInterval            time.Duration // The status of the pool and its objectives is not written if 0
+	ConfigLoadResult                 string        // Reported in the status of the pool
 	Director                         *requestcontrol.Director
 	SaturationDetector               requestcontrol.SaturationDetector
 	UseExperimentalDatalayerV2       bool // Pluggable data layer feature flag
@@ -84,6 +87,7 @@ const (
 	DefaultConfigText                       = ""                            // default for --config-text
 	DefaultPoolGroup                        = "inference.networking.k8s.io" // default for --pool-group
 	DefaultMetricsStalenessThreshold        = 2 * time.Second
+	DefaultStatusRefreshInterval            = time.Duration(0) // default for --status-refresh-interval, status is not written
 )
 
 // NewDefaultExtProcServerRunner creates a runner with default values.
@@ -104,25 +108,39 @@ func NewDefaultExtProcServerRunner() *ExtProcServerRunner {
 		HealthChecking:                   DefaultHealthChecking,
 		RefreshPrometheusMetricsInterval: DefaultRefreshPrometheusMetricsInterval,
 		MetricsStalenessThreshold:        DefaultMetricsStalenessThreshold,
+		StatusRefreshInterval:            DefaultStatusRefreshInterval,
 		// Dependencies can be assigned later.
 	}
 }
 
 // SetupWithManager sets up the runner with the given manager.
 func (r *ExtProcServerRunner) SetupWithManager(ctx context.Context, mgr ctrl.Manager) error {
+	// The controllers only run on the elected leader when leader election is enabled, so that only the leader
+	// writes status.
+	var statusWriter client.StatusWriter
+	if r.StatusRefreshInterval > 0 {
+		statusWriter = mgr.GetClient().Status()
+	}
+
 	// Create the controllers and register them with the manager
 	if err := (&controller.InferencePoolReconciler{
-		Datastore: r.Datastore,
-		Reader:    mgr.GetClient(),
-		PoolGKNN:  r.PoolGKNN,
+		Datastore:                 r.Datastore,
+		Reader:                    mgr.GetClient(),
+		PoolGKNN:                  r.PoolGKNN,
+		StatusWriter:              statusWriter,
+		StatusRefreshInterval:     r.StatusRefreshInterval,
+		MetricsStalenessThreshold: r.MetricsStalenessThreshold,
+		ConfigLoadResult:          r.ConfigLoadResult,
 	}).SetupWithManager(mgr); err != nil {
 		return fmt.Errorf("failed setting up InferencePoolReconciler: %w", err)
 	}
 
 	if err := (&controller.InferenceObjectiveReconciler{
-		Datastore: r.Datastore,
-		Reader:    mgr.GetClient(),
-		PoolGKNN:  r.PoolGKNN,
+		Datastore:             r.Datastore,
+		Reader:                mgr.GetClient(),
+		PoolGKNN:              r.PoolGKNN,
+		StatusWriter:          statusWriter,
+		StatusRefreshInterval: r.StatusRefreshInterval,
 	}).SetupWithManager(ctx, mgr); err != nil {
 		return fmt.Errorf("failed setting up InferenceObjectiveReconciler: %w", err)
 	}
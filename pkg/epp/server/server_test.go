@@ -190,6 +190,13 @@ func (ts *testDirector) HandleResponse(ctx context.Context, reqCtx *handlers.Req
 	return reqCtx, nil
 }
 
+func (ts *testDirector) HandleResponseBody(ctx context.Context, reqCtx *handlers.RequestContext, body []byte, isStreaming bool, endOfStream bool) []byte {
+	return body
+}
+
+func (ts *testDirector) HandleResponseComplete(ctx context.Context, reqCtx *handlers.RequestContext, body []byte, cancelled bool) {
+}
+
 func (ts *testDirector) GetRandomPod() *backend.Pod {
 	return nil
 }
@@ -18,17 +18,22 @@ package error
 
 import (
 	"fmt"
+	"time"
 )
 
 // Error is an error struct for errors returned by the epp server.
 type Error struct {
 	Code string
 	Msg  string
+	// RetryAfter is an optional estimate of when a rejected request can be retried, returned to the client
+	// as the Retry-After of InferencePoolResourceExhausted errors.
+	RetryAfter time.Duration
 }
 
 const (
 	Unknown                        = "Unknown"
 	BadRequest                     = "BadRequest"
+	ModelNotFound                  = "ModelNotFound"
 	Internal                       = "Internal"
 	ServiceUnavailable             = "ServiceUnavailable"
 	ModelServerError               = "ModelServerError"
@@ -0,0 +1,194 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package request
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+	errutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/error"
+)
+
+// AnthropicMessagesPath is the API path of the Anthropic Messages API.
+const AnthropicMessagesPath = "/v1/messages"
+
+// Content block types of the Anthropic Messages API that are not OpenAI content part types.
+const (
+	anthropicImageBlock      = "image"
+	anthropicDocumentBlock   = "document"
+	anthropicToolUseBlock    = "tool_use"
+	anthropicToolResultBlock = "tool_result"
+	anthropicThinkingBlock   = "thinking"
+)
+
+// anthropicMessagesRequest holds the fields parsed out of an Anthropic Messages API request body.
+type anthropicMessagesRequest struct {
+	System   anthropicContent `json:"system"`
+	Messages []struct {
+		Role    string           `json:"role"`
+		Content anthropicContent `json:"content"`
+	} `json:"messages"`
+	Tools    []interface{} `json:"tools"`
+	Metadata struct {
+		UserID string `json:"user_id"`
+	} `json:"metadata"`
+}
+
+// anthropicContent is the content of a message or of the system prompt, which is either a string or a
+// list of content blocks.
+type anthropicContent struct {
+	types.Content
+}
+
+// anthropicBlock is a content block of the Anthropic Messages API.
+type anthropicBlock struct {
+	Type     string `json:"type"`
+	Text     string `json:"text"`
+	Thinking string `json:"thinking"`
+	// Source is set on image and document blocks.
+	Source *struct {
+		Type   string `json:"type"`
+		Data   string `json:"data"`
+		URL    string `json:"url"`
+		FileID string `json:"file_id"`
+	} `json:"source"`
+	// Name and Input are set on tool use blocks.
+	Name  string          `json:"name"`
+	Input json.RawMessage `json:"input"`
+	// Content is set on tool result blocks.
+	Content *anthropicContent `json:"content"`
+}
+
+// UnmarshalJSON decodes Anthropic content into OpenAI content parts. Text and thinking blocks become text
+// parts, image and document blocks become media parts, tool use blocks become a text part holding the
+// tool name and input, and tool result blocks are flattened into their own parts. Blocks of other types,
+// such as redacted thinking or server tool blocks, are kept as a whole as types.OtherMedia parts.
+func (c *anthropicContent) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	switch {
+	case bytes.Equal(data, []byte("null")):
+		c.Content = types.Content{}
+		return nil
+	case len(data) > 0 && data[0] == '"':
+		c.Content = types.Content{}
+		return json.Unmarshal(data, &c.Content.Raw)
+	}
+
+	var rawBlocks []json.RawMessage
+	if err := json.Unmarshal(data, &rawBlocks); err != nil {
+		return fmt.Errorf("content must be a string or a list of content blocks: %w", err)
+	}
+	c.Content = types.Content{Structured: make([]types.ContentPart, 0, len(rawBlocks))}
+	for i, rawBlock := range rawBlocks {
+		// The type is decoded first, since the fields of other block types don't have the same format.
+		var typed struct {
+			Type string `json:"type"`
+		}
+		if err := json.Unmarshal(rawBlock, &typed); err != nil {
+			return fmt.Errorf("content block %d: %w", i, err)
+		}
+		var block anthropicBlock
+		switch typed.Type {
+		case types.TextContentPart, anthropicThinkingBlock, anthropicImageBlock, anthropicDocumentBlock,
+			anthropicToolUseBlock, anthropicToolResultBlock:
+			if err := json.Unmarshal(rawBlock, &block); err != nil {
+				return fmt.Errorf("content block %d: %w", i, err)
+			}
+		default:
+			c.Structured = append(c.Structured, types.ContentPart{Type: typed.Type, Media: types.NewOtherMediaRef(rawBlock)})
+			continue
+		}
+
+		switch block.Type {
+		case types.TextContentPart:
+			c.Structured = append(c.Structured, types.ContentPart{Type: block.Type, Text: block.Text})
+		case anthropicThinkingBlock:
+			c.Structured = append(c.Structured, types.ContentPart{Type: block.Type, Text: block.Thinking})
+		case anthropicImageBlock, anthropicDocumentBlock:
+			part, err := anthropicMediaPart(block)
+			if err != nil {
+				return fmt.Errorf("content block %d: %w", i, err)
+			}
+			c.Structured = append(c.Structured, part)
+		case anthropicToolUseBlock:
+			c.Structured = append(c.Structured, types.ContentPart{Type: block.Type, Text: block.Name + string(block.Input)})
+		case anthropicToolResultBlock:
+			if block.Content == nil {
+				continue
+			}
+			if block.Content.Structured == nil {
+				c.Structured = append(c.Structured, types.ContentPart{Type: block.Type, Text: block.Content.Raw})
+			} else {
+				c.Structured = append(c.Structured, block.Content.Structured...)
+			}
+		}
+	}
+	return nil
+}
+
+// anthropicMediaPart returns the content part of an image or document block.
+func anthropicMediaPart(block anthropicBlock) (types.ContentPart, error) {
+	mediaType := types.ImageMedia
+	if block.Type == anthropicDocumentBlock {
+		mediaType = types.FileMedia
+	}
+	if block.Source == nil {
+		return types.ContentPart{}, fmt.Errorf("%s block must have a source", block.Type)
+	}
+
+	part := types.ContentPart{Type: block.Type}
+	switch {
+	case block.Source.Type == "text": // plain text documents are part of the prompt
+		part.Text = block.Source.Data
+	case block.Source.Data != "":
+		part.Media = types.NewMediaRef(mediaType, block.Source.Data)
+	case block.Source.URL != "":
+		part.Media = types.NewMediaRef(mediaType, block.Source.URL)
+	case block.Source.FileID != "":
+		part.Media = types.NewMediaRefByID(mediaType, block.Source.FileID)
+	default:
+		return types.ContentPart{}, fmt.Errorf("%s block source must have data, url or file_id", block.Type)
+	}
+	return part, nil
+}
+
+// extractAnthropicMessages parses an Anthropic Messages API request into a chat-completions request.
+// The system prompt becomes the first message.
+func extractAnthropicMessages(jsonBytes []byte) (*types.LLMRequestData, error) {
+	var messages anthropicMessagesRequest
+	if err := json.Unmarshal(jsonBytes, &messages); err != nil {
+		return nil, errutil.Error{Code: errutil.BadRequest, Msg: "invalid messages request: " + err.Error()}
+	}
+	if len(messages.Messages) == 0 {
+		return nil, errutil.Error{Code: errutil.BadRequest, Msg: "messages request must have at least one message"}
+	}
+
+	chat := &types.ChatCompletionsRequest{
+		Messages: make([]types.Message, 0, len(messages.Messages)+1),
+		Tools:    messages.Tools,
+		User:     messages.Metadata.UserID,
+	}
+	if messages.System.Raw != "" || len(messages.System.Structured) > 0 {
+		chat.Messages = append(chat.Messages, types.Message{Role: "system", Content: messages.System.Content})
+	}
+	for _, message := range messages.Messages {
+		chat.Messages = append(chat.Messages, types.Message{Role: message.Role, Content: message.Content.Content})
+	}
+	return &types.LLMRequestData{ChatCompletions: chat}, nil
+}
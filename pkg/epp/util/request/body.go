@@ -23,33 +23,135 @@ import (
 	errutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/error"
 )
 
-// ExtractRequestData extracts the LLMRequestData from the given request body map.
-func ExtractRequestData(body map[string]any) (*types.LLMRequestData, error) {
+// API paths of the OpenAI compatible request types.
+const (
+	CompletionsPath     = "/v1/completions"
+	ChatCompletionsPath = "/v1/chat/completions"
+	ResponsesPath       = "/v1/responses"
+	EmbeddingsPath      = "/v1/embeddings"
+	// RerankPath is matched as a suffix, since model servers serve rerank on /rerank, /v1/rerank and
+	// /v2/rerank.
+	RerankPath = "/rerank"
+)
+
+// ExtractRequestData extracts the LLMRequestData from the given request body map. The parser of the
+// request is selected by the content type (the `content-type` header) and then by the request path (the
+// `:path` header), see RegisterContentTypeParser and RegisterPathParser. If neither has a registered
+// parser, the request type is inferred from the fields in the body.
+func ExtractRequestData(headers map[string]string, body map[string]any) (*types.LLMRequestData, error) {
 	// Convert map back to JSON bytes
 	jsonBytes, err := json.Marshal(body)
 	if err != nil {
 		return nil, errutil.Error{Code: errutil.BadRequest, Msg: "invalid request body"}
 	}
 
+	if parser := lookupParser(headers[PathHeaderKey], headers[ContentTypeHeaderKey]); parser != nil {
+		return parser(jsonBytes)
+	}
+
 	// Try completions request first
+	if data, err := extractCompletions(jsonBytes); err == nil {
+		return data, nil
+	}
+	// Requests without messages are responses, embeddings or rerank requests, if they have their fields.
+	if _, ok := body["messages"]; !ok {
+		if _, ok := body["query"]; ok {
+			return extractRerank(jsonBytes)
+		}
+		if _, ok := body["input"]; ok {
+			if isResponsesBody(body) {
+				return extractResponses(jsonBytes)
+			}
+			return extractEmbeddings(jsonBytes)
+		}
+	}
+	// Try chat completions
+	return extractChatCompletions(jsonBytes)
+}
+
+// responsesFields are fields of responses requests that embeddings requests don't have.
+var responsesFields = []string{
+	"instructions", "previous_response_id", "conversation", "max_output_tokens", "reasoning", "text", "tools", "store",
+}
+
+// isResponsesBody returns whether a request body with an input is a responses request rather than an
+// embeddings request, which is the case if it has a field of responses requests, or input items. The
+// input of embeddings requests is a string, a list of strings, or token IDs.
+func isResponsesBody(body map[string]any) bool {
+	for _, field := range responsesFields {
+		if _, ok := body[field]; ok {
+			return true
+		}
+	}
+	if items, ok := body["input"].([]any); ok {
+		for _, item := range items {
+			if _, ok := item.(map[string]any); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func extractCompletions(jsonBytes []byte) (*types.LLMRequestData, error) {
 	var completions types.CompletionsRequest
-	if err = json.Unmarshal(jsonBytes, &completions); err == nil && completions.Prompt != "" {
-		return &types.LLMRequestData{Completions: &completions}, nil
+	if err := json.Unmarshal(jsonBytes, &completions); err != nil {
+		return nil, errutil.Error{Code: errutil.BadRequest, Msg: "invalid completions request: " + err.Error()}
 	}
+	if completions.Prompt == "" {
+		return nil, errutil.Error{Code: errutil.BadRequest, Msg: "completions request must have a prompt"}
+	}
+	return &types.LLMRequestData{Completions: &completions}, nil
+}
 
-	// Try chat completions
+func extractChatCompletions(jsonBytes []byte) (*types.LLMRequestData, error) {
 	var chatCompletions types.ChatCompletionsRequest
-	if err = json.Unmarshal(jsonBytes, &chatCompletions); err != nil {
+	if err := json.Unmarshal(jsonBytes, &chatCompletions); err != nil {
 		return nil, errutil.Error{Code: errutil.BadRequest, Msg: "invalid request format"}
 	}
 
-	if err = validateChatCompletionsMessages(chatCompletions.Messages); err != nil {
+	if err := validateChatCompletionsMessages(chatCompletions.Messages); err != nil {
 		return nil, errutil.Error{Code: errutil.BadRequest, Msg: "invalid chat-completions request: " + err.Error()}
 	}
 
 	return &types.LLMRequestData{ChatCompletions: &chatCompletions}, nil
 }
 
+func extractResponses(jsonBytes []byte) (*types.LLMRequestData, error) {
+	var responses types.ResponsesRequest
+	if err := json.Unmarshal(jsonBytes, &responses); err != nil {
+		return nil, errutil.Error{Code: errutil.BadRequest, Msg: "invalid responses request: " + err.Error()}
+	}
+	// A request continuing a conversation may have no new input, the model server continuing from the
+	// previous response.
+	if responses.Input.Raw == "" && len(responses.Input.Items) == 0 && responses.PreviousResponseID == "" {
+		return nil, errutil.Error{Code: errutil.BadRequest, Msg: "responses request must have an input or a previous response"}
+	}
+	return &types.LLMRequestData{Responses: &responses}, nil
+}
+
+func extractEmbeddings(jsonBytes []byte) (*types.LLMRequestData, error) {
+	var embeddings types.EmbeddingsRequest
+	if err := json.Unmarshal(jsonBytes, &embeddings); err != nil {
+		return nil, errutil.Error{Code: errutil.BadRequest, Msg: "invalid embeddings request: " + err.Error()}
+	}
+	if embeddings.Input.Len() == 0 {
+		return nil, errutil.Error{Code: errutil.BadRequest, Msg: "embeddings request must have an input"}
+	}
+	return &types.LLMRequestData{Embeddings: &embeddings}, nil
+}
+
+func extractRerank(jsonBytes []byte) (*types.LLMRequestData, error) {
+	var rerank types.RerankRequest
+	if err := json.Unmarshal(jsonBytes, &rerank); err != nil {
+		return nil, errutil.Error{Code: errutil.BadRequest, Msg: "invalid rerank request: " + err.Error()}
+	}
+	if rerank.Query == "" || len(rerank.Documents) == 0 {
+		return nil, errutil.Error{Code: errutil.BadRequest, Msg: "rerank request must have a query and at least one document"}
+	}
+	return &types.LLMRequestData{Rerank: &rerank}, nil
+}
+
 func validateChatCompletionsMessages(messages []types.Message) error {
 	if len(messages) == 0 {
 		return errutil.Error{Code: errutil.BadRequest, Msg: "chat-completions request must have at least one message"}
@@ -26,6 +26,7 @@ import (
 func TestExtractRequestData(t *testing.T) {
 	tests := []struct {
 		name    string
+		path    string
 		body    map[string]any
 		want    *types.LLMRequestData
 		wantErr bool
@@ -58,8 +59,8 @@ func TestExtractRequestData(t *testing.T) {
 			want: &types.LLMRequestData{
 				ChatCompletions: &types.ChatCompletionsRequest{
 					Messages: []types.Message{
-						{Role: "system", Content: "this is a system message"},
-						{Role: "user", Content: "hello"},
+						{Role: "system", Content: types.Content{Raw: "this is a system message"}},
+						{Role: "user", Content: types.Content{Raw: "hello"}},
 					},
 				},
 			},
@@ -81,7 +82,7 @@ func TestExtractRequestData(t *testing.T) {
 			},
 			want: &types.LLMRequestData{
 				ChatCompletions: &types.ChatCompletionsRequest{
-					Messages:                  []types.Message{{Role: "user", Content: "hello"}},
+					Messages:                  []types.Message{{Role: "user", Content: types.Content{Raw: "hello"}}},
 					Tools:                     []any{map[string]any{"type": "function"}},
 					Documents:                 []any{map[string]any{"content": "doc"}},
 					ChatTemplate:              "custom template",
@@ -92,6 +93,345 @@ func TestExtractRequestData(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "responses request with string input",
+			path: "/v1/responses",
+			body: map[string]any{
+				"model":                "test",
+				"input":                "hello",
+				"instructions":         "be brief",
+				"previous_response_id": "resp_123",
+				"user":                 "user",
+			},
+			want: &types.LLMRequestData{
+				Responses: &types.ResponsesRequest{
+					Input:              types.ResponsesInput{Raw: "hello"},
+					Instructions:       "be brief",
+					PreviousResponseID: "resp_123",
+					User:               "user",
+				},
+			},
+		},
+		{
+			name: "responses request with input items",
+			path: "/v1/responses?stream=true",
+			body: map[string]any{
+				"model": "test",
+				"input": []any{
+					map[string]any{"role": "user", "content": []any{
+						map[string]any{"type": "input_text", "text": "what is in this image?"},
+						map[string]any{"type": "input_image", "image_url": "https://example.com/cat.png"},
+					}},
+					map[string]any{"type": "function_call", "call_id": "call_1", "name": "get_weather", "arguments": "{}"},
+					map[string]any{"type": "function_call_output", "call_id": "call_1", "output": "sunny"},
+					map[string]any{"type": "reasoning", "summary": []any{}},
+				},
+				"tools": []any{map[string]any{"type": "function", "name": "get_weather"}},
+			},
+			want: &types.LLMRequestData{
+				Responses: &types.ResponsesRequest{
+					Input: types.ResponsesInput{Items: []types.ResponsesInputItem{
+						{Type: "message", Role: "user", Content: types.Content{Structured: []types.ContentPart{
+							{Type: "input_text", Text: "what is in this image?"},
+							{Type: "input_image", Media: &types.MediaRef{Type: "image", Hash: "4585d6895fac9f64"}},
+						}}},
+						{Type: "function_call", CallID: "call_1", Name: "get_weather", Arguments: "{}"},
+						{Type: "function_call_output", CallID: "call_1", Output: "sunny"},
+						{Type: "reasoning"},
+					}},
+					Tools: []any{map[string]any{"type": "function", "name": "get_weather"}},
+				},
+			},
+		},
+		{
+			name: "responses request without input",
+			path: "/v1/responses",
+			body: map[string]any{
+				"model": "test",
+			},
+			wantErr: true,
+		},
+		{
+			name: "responses request continuing a conversation without input",
+			path: "/v1/responses",
+			body: map[string]any{
+				"model":                "test",
+				"previous_response_id": "resp_123",
+			},
+			want: &types.LLMRequestData{
+				Responses: &types.ResponsesRequest{PreviousResponseID: "resp_123"},
+			},
+		},
+		{
+			name: "responses request on an unknown path",
+			path: "/openai/deployments/test/generate",
+			body: map[string]any{
+				"model":        "test",
+				"input":        "hello",
+				"instructions": "be brief",
+			},
+			want: &types.LLMRequestData{
+				Responses: &types.ResponsesRequest{
+					Input:        types.ResponsesInput{Raw: "hello"},
+					Instructions: "be brief",
+				},
+			},
+		},
+		{
+			name: "responses request with input items on an unknown path",
+			body: map[string]any{
+				"model": "test",
+				"input": []any{map[string]any{"role": "user", "content": "hello"}},
+			},
+			want: &types.LLMRequestData{
+				Responses: &types.ResponsesRequest{
+					Input: types.ResponsesInput{Items: []types.ResponsesInputItem{
+						{Type: "message", Role: "user", Content: types.Content{Raw: "hello"}},
+					}},
+				},
+			},
+		},
+		{
+			name: "embeddings request with string input",
+			body: map[string]any{
+				"model": "test",
+				"input": "embed this",
+			},
+			want: &types.LLMRequestData{
+				Embeddings: &types.EmbeddingsRequest{
+					Input: types.EmbeddingsInput{Texts: []string{"embed this"}},
+				},
+			},
+		},
+		{
+			name: "embeddings request with list input",
+			path: "/v1/embeddings",
+			body: map[string]any{
+				"model":      "test",
+				"input":      []any{"first", "second"},
+				"dimensions": 256,
+				"user":       "user-1",
+			},
+			want: &types.LLMRequestData{
+				Embeddings: &types.EmbeddingsRequest{
+					Input:      types.EmbeddingsInput{Texts: []string{"first", "second"}},
+					Dimensions: 256,
+					User:       "user-1",
+				},
+			},
+		},
+		{
+			name: "embeddings request with token input",
+			path: "/v1/embeddings",
+			body: map[string]any{
+				"model": "test",
+				"input": []any{1, 2, 3},
+			},
+			want: &types.LLMRequestData{
+				Embeddings: &types.EmbeddingsRequest{
+					Input: types.EmbeddingsInput{Tokens: [][]int{{1, 2, 3}}},
+				},
+			},
+		},
+		{
+			name: "embeddings request with token lists input",
+			path: "/v1/embeddings",
+			body: map[string]any{
+				"model": "test",
+				"input": []any{[]any{1, 2}, []any{3}},
+			},
+			want: &types.LLMRequestData{
+				Embeddings: &types.EmbeddingsRequest{
+					Input: types.EmbeddingsInput{Tokens: [][]int{{1, 2}, {3}}},
+				},
+			},
+		},
+		{
+			name: "embeddings request with empty input",
+			path: "/v1/embeddings",
+			body: map[string]any{
+				"model": "test",
+				"input": []any{},
+			},
+			wantErr: true,
+		},
+		{
+			name: "embeddings request with invalid input",
+			path: "/v1/embeddings",
+			body: map[string]any{
+				"model": "test",
+				"input": []any{map[string]any{"text": "embed this"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "rerank request",
+			body: map[string]any{
+				"model":     "test",
+				"query":     "what is a cat?",
+				"documents": []any{"a cat is an animal", map[string]any{"text": "a car is a vehicle"}},
+				"top_n":     1,
+			},
+			want: &types.LLMRequestData{
+				Rerank: &types.RerankRequest{
+					Query:     "what is a cat?",
+					Documents: []types.RerankDocument{"a cat is an animal", "a car is a vehicle"},
+					TopN:      1,
+				},
+			},
+		},
+		{
+			name: "rerank request without documents",
+			path: "/v2/rerank",
+			body: map[string]any{
+				"model": "test",
+				"query": "what is a cat?",
+			},
+			wantErr: true,
+		},
+		{
+			name: "anthropic messages request",
+			path: "/v1/messages",
+			body: map[string]any{
+				"model":      "test",
+				"max_tokens": 1024,
+				"system": []any{
+					map[string]any{"type": "text", "text": "You are a helpful assistant"},
+				},
+				"messages": []any{
+					map[string]any{"role": "user", "content": []any{
+						map[string]any{"type": "image", "source": map[string]any{"type": "base64", "media_type": "image/png", "data": "aGVsbG8="}},
+						map[string]any{"type": "text", "text": "describe this image"},
+					}},
+					map[string]any{"role": "assistant", "content": []any{
+						map[string]any{"type": "tool_use", "id": "call_1", "name": "get_weather", "input": map[string]any{"city": "Paris"}},
+					}},
+					map[string]any{"role": "user", "content": []any{
+						map[string]any{"type": "tool_result", "tool_use_id": "call_1", "content": "sunny"},
+					}},
+				},
+				"tools":    []any{map[string]any{"name": "get_weather"}},
+				"metadata": map[string]any{"user_id": "user-1"},
+			},
+			want: &types.LLMRequestData{
+				ChatCompletions: &types.ChatCompletionsRequest{
+					Messages: []types.Message{
+						{Role: "system", Content: types.Content{Structured: []types.ContentPart{
+							{Type: "text", Text: "You are a helpful assistant"},
+						}}},
+						{Role: "user", Content: types.Content{Structured: []types.ContentPart{
+							{Type: "image", Media: types.NewMediaRef(types.ImageMedia, "aGVsbG8=")},
+							{Type: "text", Text: "describe this image"},
+						}}},
+						{Role: "assistant", Content: types.Content{Structured: []types.ContentPart{
+							{Type: "tool_use", Text: `get_weather{"city":"Paris"}`},
+						}}},
+						{Role: "user", Content: types.Content{Structured: []types.ContentPart{
+							{Type: "tool_result", Text: "sunny"},
+						}}},
+					},
+					Tools: []any{map[string]any{"name": "get_weather"}},
+					User:  "user-1",
+				},
+			},
+		},
+		{
+			name: "anthropic messages request with string system",
+			path: "/v1/messages",
+			body: map[string]any{
+				"model":    "test",
+				"system":   "You are a helpful assistant",
+				"messages": []any{map[string]any{"role": "user", "content": "hello"}},
+			},
+			want: &types.LLMRequestData{
+				ChatCompletions: &types.ChatCompletionsRequest{
+					Messages: []types.Message{
+						{Role: "system", Content: types.Content{Raw: "You are a helpful assistant"}},
+						{Role: "user", Content: types.Content{Raw: "hello"}},
+					},
+				},
+			},
+		},
+		{
+			name: "anthropic messages request without messages",
+			path: "/v1/messages",
+			body: map[string]any{
+				"model":  "test",
+				"system": "You are a helpful assistant",
+			},
+			wantErr: true,
+		},
+		{
+			name: "anthropic messages request with content blocks of other types",
+			path: "/v1/messages",
+			body: map[string]any{
+				"model": "test",
+				"messages": []any{
+					map[string]any{"role": "user", "content": "search the web"},
+					map[string]any{"role": "assistant", "content": []any{
+						map[string]any{"type": "redacted_thinking", "data": "abc"},
+						map[string]any{"type": "web_search_tool_result", "tool_use_id": "srvtoolu_1", "content": map[string]any{
+							"type": "web_search_tool_result_error", "error_code": "unavailable",
+						}},
+						map[string]any{"type": "text", "text": "the search failed"},
+					}},
+				},
+			},
+			want: &types.LLMRequestData{
+				ChatCompletions: &types.ChatCompletionsRequest{
+					Messages: []types.Message{
+						{Role: "user", Content: types.Content{Raw: "search the web"}},
+						{Role: "assistant", Content: types.Content{Structured: []types.ContentPart{
+							{Type: "redacted_thinking", Media: &types.MediaRef{Type: "other", Hash: "23cc9e5fce72894b", Size: 41}},
+							{Type: "web_search_tool_result", Media: &types.MediaRef{Type: "other", Hash: "d45da60123f5e080", Size: 137}},
+							{Type: "text", Text: "the search failed"},
+						}}},
+					},
+				},
+			},
+		},
+		{
+			name: "kserve v2 inference request",
+			path: "/v2/models/test/versions/1/infer",
+			body: map[string]any{
+				"inputs": []any{
+					map[string]any{"name": "text_input", "datatype": "BYTES", "shape": []any{1, 2}, "data": []any{[]any{"hello", "world"}}},
+					map[string]any{"name": "max_tokens", "datatype": "INT32", "shape": []any{1}, "data": []any{128}},
+				},
+			},
+			want: &types.LLMRequestData{
+				Completions: &types.CompletionsRequest{Prompt: "hello\nworld"},
+			},
+		},
+		{
+			name: "kserve v2 inference request with token inputs",
+			path: "/v2/models/test/infer",
+			body: map[string]any{
+				"inputs": []any{
+					map[string]any{"name": "input_ids", "datatype": "INT64", "shape": []any{3}, "data": []any{1, 2, 3}},
+				},
+			},
+			want: &types.LLMRequestData{
+				Completions: &types.CompletionsRequest{Prompt: "[[1,2,3]]"},
+			},
+		},
+		{
+			name: "kserve v2 inference request without inputs",
+			path: "/v2/models/test/infer",
+			body: map[string]any{
+				"inputs": []any{},
+			},
+			wantErr: true,
+		},
+		{
+			name: "path selects the request type",
+			path: "/v1/chat/completions",
+			body: map[string]any{
+				"model":  "test",
+				"prompt": "test prompt",
+			},
+			wantErr: true,
+		},
 		{
 			name:    "nil body",
 			body:    nil,
@@ -138,6 +478,50 @@ func TestExtractRequestData(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "chat completions with multimodal content parts",
+			body: map[string]any{
+				"model": "test",
+				"messages": []any{
+					map[string]any{"role": "user", "content": []any{
+						map[string]any{"type": "text", "text": "describe this image"},
+						map[string]any{"type": "image_url", "image_url": map[string]any{"url": "data:image/png;base64,aGVsbG8gd29ybGQ="}},
+						map[string]any{"type": "image_url", "image_url": map[string]any{"url": "https://example.com/cat.png"}},
+						map[string]any{"type": "input_audio", "input_audio": map[string]any{"data": "aGVsbG8=", "format": "wav"}},
+					}},
+				},
+			},
+			want: &types.LLMRequestData{
+				ChatCompletions: &types.ChatCompletionsRequest{
+					Messages: []types.Message{{Role: "user", Content: types.Content{Structured: []types.ContentPart{
+						{Type: "text", Text: "describe this image"},
+						{Type: "image_url", Media: &types.MediaRef{Type: "image", Hash: "cbacfd8f429b23f1", Size: 11}},
+						{Type: "image_url", Media: &types.MediaRef{Type: "image", Hash: "4585d6895fac9f64"}},
+						{Type: "input_audio", Media: &types.MediaRef{Type: "audio", Hash: "59199b472e3b70a2", Size: 5}},
+					}}}},
+				},
+			},
+		},
+		{
+			name: "message with content part of unknown type",
+			body: map[string]any{
+				"model": "test",
+				"messages": []any{
+					map[string]any{"role": "user", "content": []any{
+						map[string]any{"type": "text", "text": "describe this video"},
+						map[string]any{"type": "video_url", "video_url": map[string]any{"url": "https://example.com/video.mp4"}},
+					}},
+				},
+			},
+			want: &types.LLMRequestData{
+				ChatCompletions: &types.ChatCompletionsRequest{
+					Messages: []types.Message{{Role: "user", Content: types.Content{Structured: []types.ContentPart{
+						{Type: "text", Text: "describe this video"},
+						{Type: "video_url", Media: &types.MediaRef{Type: "other", Hash: "f76adeb17994f210", Size: 72}},
+					}}}},
+				},
+			},
+		},
 		{
 			name: "message with non-string content",
 			body: map[string]any{
@@ -229,7 +613,7 @@ func TestExtractRequestData(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := ExtractRequestData(tt.body)
+			got, err := ExtractRequestData(map[string]string{PathHeaderKey: tt.path}, tt.body)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("ExtractRequestData() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -254,7 +638,7 @@ func BenchmarkExtractRequestData_Completions(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_, err := ExtractRequestData(body)
+		_, err := ExtractRequestData(nil, body)
 		if err != nil {
 			b.Fatal(err)
 		}
@@ -271,7 +655,7 @@ func BenchmarkExtractRequestData_ChatCompletions(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_, err := ExtractRequestData(body)
+		_, err := ExtractRequestData(nil, body)
 		if err != nil {
 			b.Fatal(err)
 		}
@@ -295,9 +679,93 @@ func BenchmarkExtractRequestData_ChatCompletionsWithOptionals(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_, err := ExtractRequestData(body)
+		_, err := ExtractRequestData(nil, body)
 		if err != nil {
 			b.Fatal(err)
 		}
 	}
 }
+
+func TestRegisterParsers(t *testing.T) {
+	parsed := func(prompt string) ParserFunc {
+		return func(_ []byte) (*types.LLMRequestData, error) {
+			return &types.LLMRequestData{Completions: &types.CompletionsRequest{Prompt: prompt}}, nil
+		}
+	}
+	RegisterPathParser("/v1/custom", parsed("custom path"))
+	RegisterPathParser("/v2/v1/custom", parsed("longest path"))
+	RegisterContentTypeParser("application/vnd.custom+json", parsed("custom content type"))
+	t.Cleanup(func() {
+		parsersMu.Lock()
+		defer parsersMu.Unlock()
+		delete(pathParsers, "/v1/custom")
+		delete(pathParsers, "/v2/v1/custom")
+		delete(contentTypeParsers, "application/vnd.custom+json")
+	})
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    string
+	}{
+		{
+			name:    "path",
+			headers: map[string]string{PathHeaderKey: "/v1/custom"},
+			want:    "custom path",
+		},
+		{
+			name:    "longest path suffix",
+			headers: map[string]string{PathHeaderKey: "/v2/v1/custom?debug=true"},
+			want:    "longest path",
+		},
+		{
+			name:    "content type takes precedence over path",
+			headers: map[string]string{PathHeaderKey: "/v1/custom", ContentTypeHeaderKey: "application/vnd.custom+json; charset=utf-8"},
+			want:    "custom content type",
+		},
+		{
+			name:    "unregistered content type falls back to path",
+			headers: map[string]string{PathHeaderKey: "/v1/custom", ContentTypeHeaderKey: "application/json"},
+			want:    "custom path",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExtractRequestData(tt.headers, map[string]any{"model": "test"})
+			if err != nil {
+				t.Fatalf("ExtractRequestData() unexpected error = %v", err)
+			}
+			if got.Completions == nil || got.Completions.Prompt != tt.want {
+				t.Errorf("ExtractRequestData() = %v, want prompt %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestModelFromPath(t *testing.T) {
+	tests := map[string]string{
+		"/v2/models/llama/infer":                "llama",
+		"/v2/models/llama/versions/2/infer?x=1": "llama",
+		"/v1/chat/completions":                  "",
+		"/v2/models":                            "",
+	}
+	for path, want := range tests {
+		if got := ModelFromPath(path); got != want {
+			t.Errorf("ModelFromPath(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestWithModelInPath(t *testing.T) {
+	tests := map[string]string{
+		"/v2/models/llama/infer":                "/v2/models/mistral/infer",
+		"/v2/models/llama/versions/2/infer?x=1": "/v2/models/mistral/versions/2/infer?x=1",
+		"/v2/models/llama?x=1":                  "/v2/models/mistral?x=1",
+		"/v1/chat/completions":                  "/v1/chat/completions",
+	}
+	for path, want := range tests {
+		if got := WithModelInPath(path, "mistral"); got != want {
+			t.Errorf("WithModelInPath(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
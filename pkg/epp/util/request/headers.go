@@ -24,6 +24,10 @@ import (
 
 const (
 	RequestIdHeaderKey = "x-request-id"
+	// PathHeaderKey is the pseudo header holding the request path.
+	PathHeaderKey = ":path"
+	// ContentTypeHeaderKey is the header holding the media type of the request body.
+	ContentTypeHeaderKey = "content-type"
 )
 
 func ExtractHeaderValue(req *extProcPb.ProcessingRequest_RequestHeaders, headerKey string) string {
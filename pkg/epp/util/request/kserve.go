@@ -0,0 +1,128 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package request
+
+import (
+	"encoding/json"
+	"strings"
+
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+	errutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/error"
+)
+
+const (
+	// KServeInferPath is the suffix of the inference path of the KServe Open Inference Protocol (v2),
+	// /v2/models/{model}[/versions/{version}]/infer.
+	KServeInferPath = "/infer"
+
+	kserveModelsPath = "/v2/models/"
+	kserveBytesType  = "BYTES"
+)
+
+// kserveInferRequest holds the fields parsed out of a KServe v2 inference request body.
+type kserveInferRequest struct {
+	Inputs []struct {
+		Name     string          `json:"name"`
+		Datatype string          `json:"datatype"`
+		Data     json.RawMessage `json:"data"`
+	} `json:"inputs"`
+}
+
+// extractKServeInfer parses a KServe v2 inference request into a completions request. The prompt is the
+// text of the BYTES input tensors, such as the `text_input` tensor of LLM model servers, in order. If the
+// request has no BYTES tensors (e.g. it sends token IDs), the prompt is the JSON encoding of the tensor
+// data, which keeps identical inputs matching for prefix aware scheduling.
+func extractKServeInfer(jsonBytes []byte) (*types.LLMRequestData, error) {
+	var infer kserveInferRequest
+	if err := json.Unmarshal(jsonBytes, &infer); err != nil {
+		return nil, errutil.Error{Code: errutil.BadRequest, Msg: "invalid inference request: " + err.Error()}
+	}
+	if len(infer.Inputs) == 0 {
+		return nil, errutil.Error{Code: errutil.BadRequest, Msg: "inference request must have at least one input"}
+	}
+
+	var texts []string
+	for _, input := range infer.Inputs {
+		if input.Datatype != kserveBytesType {
+			continue
+		}
+		inputTexts, err := flattenKServeBytes(input.Data)
+		if err != nil {
+			return nil, errutil.Error{Code: errutil.BadRequest, Msg: "invalid data of input " + input.Name + ": " + err.Error()}
+		}
+		texts = append(texts, inputTexts...)
+	}
+
+	prompt := strings.Join(texts, "\n")
+	if texts == nil {
+		data := make([]json.RawMessage, 0, len(infer.Inputs))
+		for _, input := range infer.Inputs {
+			data = append(data, input.Data)
+		}
+		encoded, _ := json.Marshal(data) // the data was unmarshalled from JSON
+		prompt = string(encoded)
+	}
+	return &types.LLMRequestData{Completions: &types.CompletionsRequest{Prompt: prompt}}, nil
+}
+
+// flattenKServeBytes returns the strings of the data of a BYTES tensor, which is given either flat or as
+// nested lists following the tensor shape.
+func flattenKServeBytes(data json.RawMessage) ([]string, error) {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		return []string{text}, nil
+	}
+	var elements []json.RawMessage
+	if err := json.Unmarshal(data, &elements); err != nil {
+		return nil, err
+	}
+	var texts []string
+	for _, element := range elements {
+		elementTexts, err := flattenKServeBytes(element)
+		if err != nil {
+			return nil, err
+		}
+		texts = append(texts, elementTexts...)
+	}
+	return texts, nil
+}
+
+// ModelFromPath returns the model name in the path of a KServe v2 request,
+// /v2/models/{model}[/versions/{version}]/..., or an empty string if the path has none.
+func ModelFromPath(path string) string {
+	path, _, _ = strings.Cut(path, "?")
+	_, rest, ok := strings.Cut(path, kserveModelsPath)
+	if !ok {
+		return ""
+	}
+	model, _, _ := strings.Cut(rest, "/")
+	return model
+}
+
+// WithModelInPath returns the path of a KServe v2 request with its model name replaced by the given
+// model, or the path unchanged if it has no model name.
+func WithModelInPath(path string, model string) string {
+	prefix, rest, ok := strings.Cut(path, kserveModelsPath)
+	if !ok {
+		return path
+	}
+	end := strings.IndexAny(rest, "/?")
+	if end < 0 {
+		end = len(rest)
+	}
+	return prefix + kserveModelsPath + model + rest[end:]
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package request
+
+import (
+	"mime"
+	"strings"
+	"sync"
+
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+)
+
+// ParserFunc parses the JSON encoded body of a request of a specific API into LLMRequestData. Parsers
+// of APIs other than the OpenAI API normalize the request into one of the OpenAI request types, so that
+// plugins work the same regardless of the API. Parse errors should be errutil.Error with the
+// errutil.BadRequest code.
+type ParserFunc func(jsonBytes []byte) (*types.LLMRequestData, error)
+
+var (
+	parsersMu sync.RWMutex
+	// pathParsers is a mapping from path suffix to parser.
+	pathParsers = map[string]ParserFunc{
+		CompletionsPath:       extractCompletions,
+		ChatCompletionsPath:   extractChatCompletions,
+		ResponsesPath:         extractResponses,
+		EmbeddingsPath:        extractEmbeddings,
+		RerankPath:            extractRerank,
+		AnthropicMessagesPath: extractAnthropicMessages,
+		KServeInferPath:       extractKServeInfer,
+	}
+	// contentTypeParsers is a mapping from media type to parser.
+	contentTypeParsers = map[string]ParserFunc{}
+)
+
+// RegisterPathParser is a static function that can be called to register the parser of the requests
+// whose path (without the query) ends with pathSuffix. If several registered suffixes match a path, the
+// longest one is used. Registering a parser for a registered suffix replaces it.
+func RegisterPathParser(pathSuffix string, parser ParserFunc) {
+	parsersMu.Lock()
+	defer parsersMu.Unlock()
+	pathParsers[pathSuffix] = parser
+}
+
+// RegisterContentTypeParser is a static function that can be called to register the parser of the
+// requests with the given media type (e.g. `application/vnd.example+json`), which takes precedence over
+// the path parsers. Parameters of the content type header, such as the charset, are ignored.
+func RegisterContentTypeParser(mediaType string, parser ParserFunc) {
+	parsersMu.Lock()
+	defer parsersMu.Unlock()
+	contentTypeParsers[strings.ToLower(mediaType)] = parser
+}
+
+// lookupParser returns the parser registered for the content type or the path of a request, or nil
+// if there is none.
+func lookupParser(path string, contentType string) ParserFunc {
+	parsersMu.RLock()
+	defer parsersMu.RUnlock()
+
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		if parser, ok := contentTypeParsers[mediaType]; ok {
+			return parser
+		}
+	}
+
+	path, _, _ = strings.Cut(path, "?")
+	var parser ParserFunc
+	longest := 0
+	for suffix, pathParser := range pathParsers {
+		if len(suffix) > longest && strings.HasSuffix(path, suffix) {
+			parser, longest = pathParser, len(suffix)
+		}
+	}
+	return parser
+}
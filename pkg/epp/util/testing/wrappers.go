@@ -163,6 +163,24 @@ func (m *InferenceObjectiveWrapper) CreationTimestamp(t metav1.Time) *InferenceO
 	return m
 }
 
+// MatchModels adds the models to the match rules of the InferenceObjective.
+func (m *InferenceObjectiveWrapper) MatchModels(models ...string) *InferenceObjectiveWrapper {
+	if m.Spec.Match == nil {
+		m.Spec.Match = &v1alpha2.ObjectiveMatch{}
+	}
+	m.Spec.Match.Models = append(m.Spec.Match.Models, models...)
+	return m
+}
+
+// MatchHeader adds the header to the match rules of the InferenceObjective.
+func (m *InferenceObjectiveWrapper) MatchHeader(name, value string) *InferenceObjectiveWrapper {
+	if m.Spec.Match == nil {
+		m.Spec.Match = &v1alpha2.ObjectiveMatch{}
+	}
+	m.Spec.Match.Headers = append(m.Spec.Match.Headers, v1alpha2.HeaderMatch{Name: name, Value: value})
+	return m
+}
+
 // InferencePoolWrapper wraps an group "inference.networking.k8s.io" InferencePool.
 type InferencePoolWrapper struct {
 	v1.InferencePool
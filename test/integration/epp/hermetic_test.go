@@ -75,6 +75,7 @@ import (
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework/plugins/profile"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework/plugins/scorer"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/server"
+	errutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/error"
 	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/logging"
 	epptestutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/testing"
 	integrationutils "sigs.k8s.io/gateway-api-inference-extension/test/integration"
@@ -221,7 +222,9 @@ func TestFullDuplexStreamed_KubeInferenceObjectiveRequest(t *testing.T) {
 			wantErr: false,
 			wantResponses: integrationutils.NewImmediateErrorResponse(
 				envoyTypePb.StatusCode_BadRequest,
-				"inference gateway: BadRequest - Error unmarshaling request body",
+				errutil.BadRequest,
+				"invalid_request_error",
+				"Error unmarshaling request body",
 			),
 		},
 		{
@@ -830,18 +833,12 @@ func TestFullDuplexStreamed_KubeInferenceObjectiveRequest(t *testing.T) {
 
 			wantMetrics: map[string]string{},
 			wantErr:     true,
-			wantResponses: []*extProcPb.ProcessingResponse{
-				{
-					Response: &extProcPb.ProcessingResponse_ImmediateResponse{
-						ImmediateResponse: &extProcPb.ImmediateResponse{
-							Status: &envoyTypePb.HttpStatus{
-								Code: envoyTypePb.StatusCode_ServiceUnavailable,
-							},
-							Body: []byte("inference gateway: ServiceUnavailable - failed to find candidate pods for serving the request"),
-						},
-					},
-				},
-			},
+			wantResponses: integrationutils.NewImmediateErrorResponse(
+				envoyTypePb.StatusCode_ServiceUnavailable,
+				errutil.ServiceUnavailable,
+				"service_unavailable_error",
+				"failed to find candidate pods for serving the request",
+			),
 		},
 		{
 			name: "no backend pods are available",
@@ -869,18 +866,12 @@ func TestFullDuplexStreamed_KubeInferenceObjectiveRequest(t *testing.T) {
 			pods:        nil,
 			wantMetrics: map[string]string{},
 			wantErr:     true,
-			wantResponses: []*extProcPb.ProcessingResponse{
-				{
-					Response: &extProcPb.ProcessingResponse_ImmediateResponse{
-						ImmediateResponse: &extProcPb.ImmediateResponse{
-							Status: &envoyTypePb.HttpStatus{
-								Code: envoyTypePb.StatusCode_InternalServerError,
-							},
-							Body: []byte("inference gateway: Internal - no pods available in datastore"),
-						},
-					},
-				},
-			},
+			wantResponses: integrationutils.NewImmediateErrorResponse(
+				envoyTypePb.StatusCode_InternalServerError,
+				errutil.Internal,
+				"server_error",
+				"no pods available in datastore",
+			),
 		},
 		{
 			name: "request don't contains invalid payload, model not exist",
@@ -895,18 +886,12 @@ func TestFullDuplexStreamed_KubeInferenceObjectiveRequest(t *testing.T) {
 			},
 			wantErr:     true,
 			wantMetrics: map[string]string{},
-			wantResponses: []*extProcPb.ProcessingResponse{
-				{
-					Response: &extProcPb.ProcessingResponse_ImmediateResponse{
-						ImmediateResponse: &extProcPb.ImmediateResponse{
-							Status: &envoyTypePb.HttpStatus{
-								Code: envoyTypePb.StatusCode_BadRequest,
-							},
-							Body: []byte("inference gateway: BadRequest - model not found in request body"),
-						},
-					},
-				},
-			},
+			wantResponses: integrationutils.NewImmediateErrorResponse(
+				envoyTypePb.StatusCode_BadRequest,
+				errutil.BadRequest,
+				"invalid_request_error",
+				"model not found in request body",
+			),
 		},
 	}
 
@@ -261,16 +261,28 @@ func NewResponseStreamChunk(body string, endOfStream bool) *extProcPb.Processing
 	}
 }
 
-// NewImmediateErrorResponse creates an immediate response to terminate processing.
-// This is used for errors like load shedding or bad requests.
-func NewImmediateErrorResponse(code envoyTypePb.StatusCode, body string) []*extProcPb.ProcessingResponse {
+// NewImmediateErrorResponse creates an immediate response to terminate processing, with the OpenAI compatible
+// error body of the given EPP error code, error type and message.
+func NewImmediateErrorResponse(code envoyTypePb.StatusCode, errorCode string, errorType string, message string) []*extProcPb.ProcessingResponse {
+	type errorDetails struct {
+		Message string `json:"message"`
+		Type    string `json:"type"`
+		Code    string `json:"code"`
+	}
+	body, _ := json.Marshal(map[string]errorDetails{"error": {Message: message, Type: errorType, Code: errorCode}})
 	response := &extProcPb.ProcessingResponse{
 		Response: &extProcPb.ProcessingResponse_ImmediateResponse{
 			ImmediateResponse: &extProcPb.ImmediateResponse{
 				Status: &envoyTypePb.HttpStatus{
 					Code: code,
 				},
-				Body: []byte(body),
+				Headers: &extProcPb.HeaderMutation{
+					SetHeaders: []*envoyCorev3.HeaderValueOption{
+						{Header: &envoyCorev3.HeaderValue{Key: "content-type", RawValue: []byte("application/json")}},
+						{Header: &envoyCorev3.HeaderValue{Key: metadata.ErrorCodeKey, RawValue: []byte(errorCode)}},
+					},
+				},
+				Body: body,
 			},
 		},
 	}